@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
@@ -16,7 +17,11 @@ import (
 	"ths-erp.com/internal/platform/i18n"
 	"ths-erp.com/internal/platform/logger"
 	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/platform/oidc"
+	"ths-erp.com/internal/platform/password"
 	"ths-erp.com/internal/platform/queue"
+	"ths-erp.com/internal/platform/ratelimit"
+	"ths-erp.com/internal/policy"
 	"ths-erp.com/internal/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -32,7 +37,45 @@ func main() {
 	if err != nil {
 		log.Fatalf("Could not load config: %v", err)
 	}
-	auth.SetJWTSecret(cfg.JWTSecret)
+	keySet, err := auth.LoadKeySet(auth.KeySetConfig{
+		KeysDir:       cfg.JWTKeysDir,
+		PrivateKeyPEM: cfg.JWTPrivateKey,
+		ActiveKeyID:   cfg.JWTActiveKeyID,
+		RetiredKeys:   cfg.JWTRetiredKeys,
+		GracePeriod:   cfg.JWTKeyGrace,
+		HMACSecret:    cfg.JWTSecret,
+	})
+	if err != nil {
+		log.Fatalf("Could not load JWT keys: %v", err)
+	}
+	auth.SetKeySet(keySet)
+	auth.SetAccessTokenTTL(cfg.AccessTokenTTL)
+
+	passwordChecker, err := password.NewChecker(password.Policy{
+		MinLength:     cfg.PasswordMinLength,
+		MaxBytes:      cfg.PasswordMaxBytes,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		BlocklistFile: cfg.PasswordBlocklistFile,
+	})
+	if err != nil {
+		log.Fatalf("Could not load password policy: %v", err)
+	}
+	password.SetChecker(passwordChecker)
+
+	hasherConfig := password.DefaultHasherConfig()
+	hasherConfig.Algorithm = cfg.PasswordHashAlgorithm
+	hasherConfig.Argon2id.Memory = uint32(cfg.Argon2Memory)
+	hasherConfig.Argon2id.Iterations = uint32(cfg.Argon2Iterations)
+	hasherConfig.Argon2id.Parallelism = uint8(cfg.Argon2Parallelism)
+	hasherConfig.BcryptCost = cfg.BcryptCost
+	passwordHasher, err := password.NewHasher(hasherConfig)
+	if err != nil {
+		log.Fatalf("Could not configure password hasher: %v", err)
+	}
+	password.SetHasher(passwordHasher)
 
 	// Initialize prometheus metrics platforms
 	i18n.Init()
@@ -45,8 +88,22 @@ func main() {
 	}
 	log.Println("✓ Database connected")
 
-	// Run migrations
-	migration.Migrate(db)
+	// Run migrations. Tablolar sadece geliştirme ortamında ve açıkça istenirse silinir.
+	if cfg.DBResetOnStart {
+		log.Println("DB_RESET_ON_START is set, dropping all tables")
+		err = migration.Reset(db)
+	} else {
+		err = migration.Migrate(db)
+	}
+	if err != nil {
+		log.Fatalf("Could not migrate database: %v", err)
+	}
+	// Bilinen şifreli örnek kullanıcılar sadece açıkça istendiğinde (SEED_DEMO_DATA=true) oluşturulur.
+	if cfg.SeedDemoData {
+		if err := migration.SeedDemoData(db, cfg.DemoUserPassword); err != nil {
+			log.Fatalf("Could not seed demo data: %v", err)
+		}
+	}
 
 	// Connect to RabbitMQ
 	rabbitURL := os.Getenv("RABBITMQ_URL")
@@ -63,6 +120,14 @@ func main() {
 	if err != nil {
 		log.Fatalf("Could not declare queue/exchange: %v", err)
 	}
+	err = rabbitClient.DeclareAndBindQueue("app_exchange", "password_reset_emails_queue", "user.password_reset_email")
+	if err != nil {
+		log.Fatalf("Could not declare queue/exchange: %v", err)
+	}
+	err = rabbitClient.DeclareAndBindQueue("app_exchange", "email_verification_queue", "user.email_verification")
+	if err != nil {
+		log.Fatalf("Could not declare queue/exchange: %v", err)
+	}
 
 	// Connect to Redis
 	redisClient, err := cache.NewRedisClient(cfg)
@@ -71,6 +136,8 @@ func main() {
 	}
 	log.Println("✓ Redis connected")
 
+	appCache := cache.NewRedisCache(redisClient)
+
 	// Mappers
 	userMapper := service.NewUserMapper()
 
@@ -78,8 +145,91 @@ func main() {
 	uowFactory := service.NewUnitOfWorkFactory(db)
 
 	// Services
-	permService := service.NewPermissionService(uowFactory)
-	userService := service.NewUserService(uowFactory, userMapper, rabbitClient)
+	// Yetki önbelleği: instance içi bellek + Redis; değişiklikler pub/sub ile tüm instance'lara yayılır.
+	permCache := service.NewPermissionCache(context.Background(), cache.NewInMemoryCache(time.Minute), appCache, cache.NewRedisPubSub(redisClient), cfg.PermissionCacheTTL)
+	// Migration'lar rol ve yetki tablolarını değiştirmiş olabilir; önceki çalıştırmadan kalan kayıtlar kullanılmaz.
+	permCache.InvalidateAll(context.Background())
+	// Politika motoru: rol yetkilerinin yanında dosya ve veritabanı politikalarını değerlendirir.
+	policyEngine := policy.NewEngine(cfg.PolicyLocation)
+	permService := service.NewPermissionService(uowFactory, permCache, policyEngine)
+	policyService := service.NewPolicyService(context.Background(), uowFactory, policyEngine, permService, cache.NewRedisPubSub(redisClient), cfg.PolicyDir)
+	if err := policyService.Reload(context.Background()); err != nil {
+		log.Fatalf("Could not load authorization policies: %v", err)
+	}
+	tokenService := service.NewTokenService(uowFactory, userMapper, appCache, service.TokenServiceConfig{
+		RefreshTokenTTL:  cfg.RefreshTokenTTL,
+		ImpersonationTTL: cfg.ImpersonationTTL,
+	})
+	twoFactorLimiter := ratelimit.NewRedisFailureLimiter(redisClient, ratelimit.FailureLimiterConfig{
+		Scope:       "2fa",
+		MaxAttempts: cfg.TwoFactorMaxAttempts,
+		Window:      cfg.TwoFactorMaxLockout,
+		BaseLockout: cfg.TwoFactorLockout,
+		MaxLockout:  cfg.TwoFactorMaxLockout,
+	})
+	loginLimiters := service.LoginLimiters{
+		Email: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "login_email",
+			Limit:  cfg.LoginMaxAttemptsPerEmail,
+			Window: cfg.LoginWindow,
+		}),
+		IP: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "login_ip",
+			Limit:  cfg.LoginMaxAttemptsPerIP,
+			Window: cfg.LoginWindow,
+		}),
+		Account: ratelimit.NewRedisFailureLimiter(redisClient, ratelimit.FailureLimiterConfig{
+			Scope:       "login_account",
+			MaxAttempts: cfg.LoginLockThreshold,
+			Window:      cfg.LoginWindow,
+			BaseLockout: cfg.LoginLockout,
+			MaxLockout:  cfg.LoginMaxLockout,
+		}),
+	}
+	verificationLimiters := service.EmailRequestLimiters{
+		Email: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "verification_email",
+			Limit:  cfg.VerificationEmailMaxPerEmail,
+			Window: cfg.VerificationEmailWindow,
+		}),
+		IP: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "verification_email_ip",
+			Limit:  cfg.VerificationEmailMaxPerIP,
+			Window: cfg.VerificationEmailWindow,
+		}),
+	}
+	passwordResetLimiters := service.EmailRequestLimiters{
+		Email: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "password_reset_email",
+			Limit:  cfg.PasswordResetMaxPerEmail,
+			Window: cfg.PasswordResetWindow,
+		}),
+		IP: ratelimit.NewRedisSlidingWindowLimiter(redisClient, ratelimit.SlidingWindowConfig{
+			Scope:  "password_reset_ip",
+			Limit:  cfg.PasswordResetMaxPerIP,
+			Window: cfg.PasswordResetWindow,
+		}),
+	}
+	userService := service.NewUserService(uowFactory, userMapper, rabbitClient, tokenService, permCache, twoFactorLimiter, loginLimiters, verificationLimiters, passwordResetLimiters, service.UserServiceConfig{
+		RequireEmailVerification: cfg.RequireEmailVerification,
+	})
+
+	apiKeyService := service.NewAPIKeyService(uowFactory, appCache, service.APIKeyServiceConfig{
+		DefaultTTL: cfg.APIKeyDefaultTTL,
+		MaxTTL:     cfg.APIKeyMaxTTL,
+	})
+
+	oidcConfigs := make([]oidc.ProviderConfig, 0, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		oidcConfigs = append(oidcConfigs, oidc.ProviderConfig(p))
+	}
+	oidcRegistry, err := oidc.NewRegistry(oidcConfigs, nil)
+	if err != nil {
+		log.Fatalf("Could not configure OIDC providers: %v", err)
+	}
+	oidcService := service.NewOIDCService(uowFactory, appCache, oidcRegistry, "/api/v1/auth/oidc")
+	organizationService := service.NewOrganizationService(uowFactory, permCache)
+	impersonationService := service.NewImpersonationService(uowFactory, permService, userMapper, appCache, cfg.ImpersonationTTL)
 
 	// Setup server
 	app := fiber.New(
@@ -92,14 +242,17 @@ func main() {
 	)
 
 	app.Use(recover.New())
+	app.Use(middleware.AttachLogger)
+	app.Use(middleware.PrometheusMiddleware)
+	app.Use(i18n.Middleware)
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 	}))
 
 	// Routes
-	http.SetupRoutes(app, db, permService, rabbitClient, redisClient)
-	graphql.SetupHandler(app, userService, permService)
+	http.SetupRoutes(app, db, userService, permService, policyService, organizationService, tokenService, apiKeyService, oidcService, impersonationService, rabbitClient, redisClient)
+	graphql.SetupHandler(app, userService, permService, tokenService, apiKeyService, impersonationService)
 
 	// Start server
 	port := os.Getenv("PORT")
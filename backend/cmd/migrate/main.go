@@ -0,0 +1,118 @@
+// Command migrate, veritabanı migration'larını API'yi başlatmadan yönetir.
+//
+//	go run ./cmd/migrate up             bekleyen migration'ları uygular
+//	go run ./cmd/migrate down [n]       son n (varsayılan 1) migration'ı geri alır
+//	go run ./cmd/migrate status         migration'ları ve uygulanma zamanlarını listeler
+//	go run ./cmd/migrate create-admin   ADMIN_NAME, ADMIN_EMAIL ve ADMIN_PASSWORD ile ilk yöneticiyi oluşturur
+//	go run ./cmd/migrate seed           DEMO_USER_PASSWORD ile örnek kullanıcıları ekler (sadece SEED_DEMO_DATA=true)
+//	go run ./cmd/migrate reset          tüm tabloları silip şemayı baştan oluşturur (sadece APP_ENV=development);
+//	                                    SEED_DEMO_DATA=true ise örnek kullanıcıları da ekler
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"ths-erp.com/internal/config"
+	"ths-erp.com/internal/platform/database"
+	"ths-erp.com/internal/platform/database/migration"
+	"ths-erp.com/internal/platform/password"
+)
+
+func main() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Could not load config: %v", err)
+	}
+
+	command := "up"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	switch command {
+	case "up":
+		err = migration.Migrate(db)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps: %s", os.Args[2])
+			}
+		}
+		err = migration.Rollback(db, steps)
+	case "status":
+		var statuses []migration.Status
+		statuses, err = migration.Statuses(db)
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-32s %s\n", s.Version, s.Name, applied)
+		}
+	case "create-admin":
+		// Şifre komut satırında verilmez; kabuk geçmişine ve süreç listesine düşmemesi için
+		// ortam değişkeninden okunur.
+		configurePasswords(cfg)
+		if err = migration.Migrate(db); err == nil {
+			err = migration.CreateAdmin(db, os.Getenv("ADMIN_NAME"), os.Getenv("ADMIN_EMAIL"), os.Getenv("ADMIN_PASSWORD"))
+		}
+	case "seed":
+		if !cfg.SeedDemoData {
+			log.Fatal("seed is only allowed when SEED_DEMO_DATA=true")
+		}
+		configurePasswords(cfg)
+		err = migration.SeedDemoData(db, cfg.DemoUserPassword)
+	case "reset":
+		if cfg.AppEnv != "development" {
+			log.Fatal("reset is only allowed when APP_ENV=development")
+		}
+		configurePasswords(cfg)
+		if err = migration.Reset(db); err == nil && cfg.SeedDemoData {
+			err = migration.SeedDemoData(db, cfg.DemoUserPassword)
+		}
+	default:
+		log.Fatalf("Unknown command %q (expected up, down, status, create-admin, seed or reset)", command)
+	}
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+// configurePasswords, şifre politikasını ve özet algoritmasını API ile aynı ayarlarla kurar.
+func configurePasswords(cfg *config.Config) {
+	checker, err := password.NewChecker(password.Policy{
+		MinLength:     cfg.PasswordMinLength,
+		MaxBytes:      cfg.PasswordMaxBytes,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		BlocklistFile: cfg.PasswordBlocklistFile,
+	})
+	if err != nil {
+		log.Fatalf("Could not load password policy: %v", err)
+	}
+	password.SetChecker(checker)
+
+	hasherConfig := password.DefaultHasherConfig()
+	hasherConfig.Algorithm = cfg.PasswordHashAlgorithm
+	hasherConfig.Argon2id.Memory = uint32(cfg.Argon2Memory)
+	hasherConfig.Argon2id.Iterations = uint32(cfg.Argon2Iterations)
+	hasherConfig.Argon2id.Parallelism = uint8(cfg.Argon2Parallelism)
+	hasherConfig.BcryptCost = cfg.BcryptCost
+	hasher, err := password.NewHasher(hasherConfig)
+	if err != nil {
+		log.Fatalf("Could not configure password hasher: %v", err)
+	}
+	password.SetHasher(hasher)
+}
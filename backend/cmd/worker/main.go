@@ -6,6 +6,7 @@ import (
 
 	"ths-erp.com/internal/config"
 	"ths-erp.com/internal/platform/database"
+	"ths-erp.com/internal/platform/database/migration"
 	"ths-erp.com/internal/platform/logger"
 	"ths-erp.com/internal/platform/queue"
 	"ths-erp.com/internal/service"
@@ -29,6 +30,12 @@ func main() {
 	}
 	log.Println("✓ Database connected for worker")
 
+	// Worker API'den önce açılabilir; migration'lar advisory lock ile korunduğu için aynı anda
+	// çalışmaları güvenlidir.
+	if err := migration.Migrate(db); err != nil {
+		log.Fatalf("Could not migrate database: %v", err)
+	}
+
 	// 3. RabbitMQ Bağlantısı
 	rabbitURL := os.Getenv("RABBITMQ_URL")
 	if rabbitURL == "" {
@@ -45,6 +52,14 @@ func main() {
 	if err != nil {
 		log.Fatalf("Could not declare queue/exchange: %v", err)
 	}
+	err = rabbitClient.DeclareAndBindQueue("app_exchange", "password_reset_emails_queue", "user.password_reset_email")
+	if err != nil {
+		log.Fatalf("Could not declare queue/exchange: %v", err)
+	}
+	err = rabbitClient.DeclareAndBindQueue("app_exchange", "email_verification_queue", "user.email_verification")
+	if err != nil {
+		log.Fatalf("Could not declare queue/exchange: %v", err)
+	}
 
 	// 4. Bağımlılıkları Oluştur
 	uowFactory := service.NewUnitOfWorkFactory(db)
@@ -52,8 +67,8 @@ func main() {
 
 	// Worker'ın RabbitMQ'ya mesaj GÖNDERMESİNE gerek olmadığı için nil geçiyoruz.
 	// Eğer worker başka bir görevi tetikleyecek olsaydı, client'ı buraya da geçerdik.
-	userService := service.NewUserService(uowFactory, userMapper, nil)
-	reportService := service.NewReportService(uowFactory, nil)
+	userService := service.NewUserService(uowFactory, userMapper, nil, nil, nil, nil, service.LoginLimiters{}, service.EmailRequestLimiters{}, service.EmailRequestLimiters{}, service.UserServiceConfig{})
+	reportService := service.NewReportService(uowFactory, nil, nil)
 
 	// 5. Consumer'ı Başlat
 	jobConsumer := worker.NewJobConsumer(rabbitClient.Channel, userService, reportService)
@@ -13,5 +13,45 @@ var (
 	ErrInternalServer       = errors.New("sunucu hatası")
 	ErrInvalidRequest       = errors.New("geçersiz istek")
 	Err2FASetupNotCompleted = errors.New("2FA kurulumu tamamlanmamış")
+	Err2FAAlreadyEnabled    = errors.New("2FA zaten etkin")
 	ErrInvalid2FACode       = errors.New("geçersiz 2FA kodu")
+	ErrInvalidRefreshToken  = errors.New("geçersiz veya süresi dolmuş refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token yeniden kullanıldı, oturum iptal edildi")
+	ErrTokenRevoked         = errors.New("token iptal edilmiş")
+	ErrInvalidChallenge     = errors.New("geçersiz veya süresi dolmuş 2FA oturumu")
+	ErrInvalidResetToken    = errors.New("geçersiz veya süresi dolmuş şifre sıfırlama bağlantısı")
+	ErrInvalidVerifyToken   = errors.New("geçersiz veya süresi dolmuş e-posta doğrulama bağlantısı")
+	ErrEmailNotVerified     = errors.New("e-posta adresi doğrulanmamış")
+	ErrWeakPassword         = errors.New("şifre, şifre politikasını karşılamıyor")
+	ErrAccountLocked        = errors.New("hesap geçici olarak kilitlendi")
+	ErrTooManyAttempts      = errors.New("çok fazla başarısız deneme, lütfen daha sonra tekrar deneyin")
+	ErrSessionNotFound      = errors.New("oturum bulunamadı")
+	ErrAPIKeyNotFound       = errors.New("API anahtarı bulunamadı")
+	ErrInvalidAPIKey        = errors.New("geçersiz, süresi dolmuş veya iptal edilmiş API anahtarı")
+	ErrAPIKeyNotAllowed     = errors.New("bu işlem API anahtarı ile yapılamaz")
+	ErrNotServiceAccount    = errors.New("başka bir kullanıcı adına sadece servis hesapları için anahtar oluşturulabilir")
+	ErrOIDCProviderNotFound = errors.New("kimlik sağlayıcı bulunamadı")
+	ErrInvalidOIDCState     = errors.New("geçersiz veya süresi dolmuş OIDC oturumu")
+	ErrOIDCLoginFailed      = errors.New("kimlik sağlayıcı ile giriş doğrulanamadı")
+	ErrOIDCAccountNotLinked = errors.New("kimlik sağlayıcı hesabı bir kullanıcı ile eşleştirilemedi")
+	ErrImpersonationDenied  = errors.New("bu kullanıcı adına işlem yapılamaz")
+	ErrNotImpersonating     = errors.New("aktif bir impersonation oturumu yok")
+	ErrImpersonationBlocked = errors.New("bu işlem impersonation sırasında yapılamaz")
+	ErrRoleNotFound         = errors.New("rol bulunamadı")
+	ErrRoleExists           = errors.New("bu isimde bir rol zaten var")
+	ErrSystemRole           = errors.New("sistem rolleri değiştirilemez veya silinemez")
+	ErrRoleHasChildren      = errors.New("rol başka rollerin üst rolü olduğu için silinemez")
+	ErrInvalidPermission    = errors.New("geçersiz veya tanımsız yetki")
+	ErrPermissionDelegation = errors.New("sahip olunmayan yetki verilemez veya kendi yetkileri değiştirilemez")
+	ErrPolicyNotFound       = errors.New("politika bulunamadı")
+	ErrPolicyExists         = errors.New("bu isimde bir politika zaten var")
+	ErrInvalidPolicy        = errors.New("geçersiz politika")
+	ErrNoOrganization       = errors.New("aktif bir organizasyon seçilmemiş")
+	ErrCrossTenant          = errors.New("kayıt başka bir organizasyona ait")
+	ErrOrganizationNotFound = errors.New("organizasyon bulunamadı")
+	ErrOrganizationExists   = errors.New("bu kısa adla bir organizasyon zaten var")
+	ErrNotMember            = errors.New("kullanıcı bu organizasyonun üyesi değil")
+	ErrAlreadyMember        = errors.New("kullanıcı zaten bu organizasyonun üyesi")
+	ErrInvitationNotFound   = errors.New("davet bulunamadı veya süresi dolmuş")
+	ErrUserInOtherOrgs      = errors.New("kullanıcı başka organizasyonların da üyesi; hesabı sadece kendisi değiştirebilir, bu organizasyondan çıkarılabilir")
 )
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"ths-erp.com/internal/domain"
+)
+
+// APIKeyPrefix, üretilen tüm API anahtarlarının başına eklenir; böylece sızdırılmış
+// anahtarlar loglarda ve kod taramalarında kolayca tanınır.
+const APIKeyPrefix = "thk_"
+
+// GenerateAPIKey, "thk_<8 hex>_<opak token>" biçiminde yeni bir API anahtarı üretir.
+// prefix ("thk_<8 hex>") anahtarı listelerde tanımak için düz metin saklanır; anahtarın
+// kendisi sadece HashToken ile özetlenerek saklanır.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(b)
+	return prefix + "_" + secret, prefix, nil
+}
+
+// APIKeyFromHeaders, isteğin "X-API-Key" veya "Authorization: ApiKey <anahtar>" başlıklarından
+// API anahtarını çıkarır; ikisi de yoksa boş döner.
+func APIKeyFromHeaders(apiKeyHeader, authorizationHeader string) string {
+	if key := strings.TrimSpace(apiKeyHeader); key != "" {
+		return key
+	}
+	const scheme = "ApiKey "
+	if len(authorizationHeader) > len(scheme) && strings.EqualFold(authorizationHeader[:len(scheme)], scheme) {
+		return strings.TrimSpace(authorizationHeader[len(scheme):])
+	}
+	return ""
+}
+
+// ScopeAllows, kapsam listesinin verilen kaynak ve işleme izin verip vermediğini döner.
+// Kapsamlar rol yetkileri ile aynı söz dizimini kullanır; geçerli kapsamlar permission.ValidGrant ile doğrulanır.
+func ScopeAllows(scopes []string, resource, action string) bool {
+	return domain.PermissionGranted(scopes, resource, action)
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+const ClientInfoContextKey contextKey = "client_info"
+
+// ClientInfo, isteği yapan istemciye ait bilgileri taşır. Login denemeleri, oturumlar ve
+// denetim kayıtları gibi HTTP katmanına bağımlı olmaması gereken yerlerde kullanılır.
+type ClientInfo struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+// WithClientInfo, istemci bilgilerini context'e ekler.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, ClientInfoContextKey, info)
+}
+
+// GetClientInfo, context'teki istemci bilgilerini döner; yoksa boş değer döner.
+func GetClientInfo(ctx context.Context) ClientInfo {
+	if ctx == nil {
+		return ClientInfo{}
+	}
+	info, _ := ctx.Value(ClientInfoContextKey).(ClientInfo)
+	return info
+}
+
+// Device, User-Agent'tan "Chrome on Windows" gibi kısa bir cihaz açıklaması üretir.
+// Oturum listesinde kullanıcının cihazlarını ayırt edebilmesi içindir; tanınmayan
+// istemcilerde boş değil "Unknown" döner.
+func (c ClientInfo) Device() string {
+	ua := c.UserAgent
+	if ua == "" {
+		return "Unknown"
+	}
+
+	browser := firstMatch(ua, []uaPattern{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+		{"okhttp/", "OkHttp"},
+		{"Go-http-client/", "Go HTTP client"},
+	})
+	os := firstMatch(ua, []uaPattern{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown"
+	}
+}
+
+type uaPattern struct {
+	token string
+	name  string
+}
+
+func firstMatch(ua string, patterns []uaPattern) string {
+	for _, p := range patterns {
+		if strings.Contains(ua, p.token) {
+			return p.name
+		}
+	}
+	return ""
+}
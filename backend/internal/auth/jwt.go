@@ -6,22 +6,47 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type JWTClaims struct {
+	UserID          int          `json:"user_id"`
+	Email           string       `json:"email"`
+	SessionID       string       `json:"sid,omitempty"`
+	TenantID        int          `json:"tid,omitempty"` // Aktif organizasyon (kiracı)
+	Actor           *ActorClaims `json:"act,omitempty"` // Impersonation token'larında işlemi yapan yönetici (RFC 8693)
+	ImpersonationID int          `json:"imp,omitempty"` // Impersonation token'larında impersonation_sessions kaydı
+	jwt.RegisteredClaims
+}
+
+// ActorClaims, başka bir kullanıcı adına işlem yapan yöneticiyi tanımlar.
+type ActorClaims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
-	jwt.RegisteredClaims
 }
 
-var jwtSecret []byte
+// Token türleri "aud" claim'i ile ayrılır; böylece bir 2FA challenge token'ı
+// access token yerine kullanılamaz (veya tersi).
+const (
+	AudienceAccess    = "ths-erp:access"
+	AudienceChallenge = "ths-erp:2fa-challenge"
+
+	// ChallengeTokenTTL, şifre doğrulandıktan sonra 2FA kodunun girilmesi için tanınan süredir.
+	ChallengeTokenTTL = 5 * time.Minute
+)
 
-func SetJWTSecret(secret string) {
-	jwtSecret = []byte(secret)
+var accessTokenTTL = 15 * time.Minute
+
+// SetAccessTokenTTL, üretilen access token'ların geçerlilik süresini ayarlar.
+func SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		accessTokenTTL = ttl
+	}
 }
 
-func GetJWTSecret() []byte {
-	return jwtSecret
+// GetAccessTokenTTL, access token'ların geçerlilik süresini döner.
+func GetAccessTokenTTL() time.Duration {
+	return accessTokenTTL
 }
 
 type contextKey string
@@ -29,8 +54,37 @@ type contextKey string
 const UserContextKey contextKey = "user"
 
 type AuthUser struct {
-	UserID int
-	Email  string
+	UserID         int
+	Email          string
+	TokenID        string    // Access token'ın jti değeri
+	SessionID      string    // Token'ın ait olduğu oturum (refresh token ailesi)
+	TenantID       int       // Aktif organizasyon; 0 ise kullanıcı hiçbir organizasyonun üyesi değildir
+	TokenExpiresAt time.Time // Access token'ın bitiş zamanı
+	APIKeyID       int       // İstek bir API anahtarı ile doğrulandıysa anahtarın ID'si
+	Scopes         []string  // API anahtarının kapsamları ("kaynak:işlem")
+	// Impersonation: UserID/Email taklit edilen kullanıcıyı, aşağıdaki alanlar işlemi yapan yöneticiyi gösterir.
+	ImpersonatorID    int
+	ImpersonatorEmail string
+	ImpersonationID   int
+}
+
+// IsImpersonated, isteğin bir yönetici tarafından kullanıcı adına yapıldığını söyler.
+func (u *AuthUser) IsImpersonated() bool {
+	return u.ImpersonatorID != 0
+}
+
+// IsAPIKey, kimliğin bir JWT yerine API anahtarı ile doğrulandığını söyler.
+func (u *AuthUser) IsAPIKey() bool {
+	return u.APIKeyID != 0
+}
+
+// AllowsScope, API anahtarı ile gelen isteklerde işlemin anahtarın kapsamlarında olup
+// olmadığını kontrol eder. JWT ile gelen isteklerde kapsam sınırı yoktur.
+func (u *AuthUser) AllowsScope(resource, action string) bool {
+	if !u.IsAPIKey() {
+		return true
+	}
+	return ScopeAllows(u.Scopes, resource, action)
 }
 
 func GetUserFromContext(ctx context.Context) (*AuthUser, error) {
@@ -46,16 +100,92 @@ func GetUserFromContext(ctx context.Context) (*AuthUser, error) {
 	return user, nil
 }
 
-func GenerateJWT(userID int, email string) (string, error) {
+// GenerateJWT, kısa ömürlü bir access token üretir.
+// sessionID, token'ın ait olduğu refresh token ailesini (oturumu), tenantID ise aktif organizasyonu belirtir.
+func GenerateJWT(userID int, email, sessionID string, tenantID int) (string, error) {
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
+		TenantID:  tenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return keySet.Sign(claims)
+}
+
+// GenerateChallengeToken, şifre kontrolünü geçmiş ancak henüz 2FA kodunu girmemiş
+// kullanıcıya verilen kısa ömürlü token'ı üretir. Bu token API erişimi sağlamaz.
+func GenerateChallengeToken(userID int, email string) (string, error) {
+	now := time.Now()
 	claims := &JWTClaims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{AudienceChallenge},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ChallengeTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return keySet.Sign(claims)
+}
+
+// GenerateImpersonationJWT, yöneticinin (actor) kullanıcı adına işlem yapmasını sağlayan
+// access token'ı üretir. Token yenilenemez; ttl sonunda impersonation kendiliğinden biter.
+// tenantID, yöneticinin aktif organizasyonudur; impersonation bu organizasyonla sınırlıdır.
+func GenerateImpersonationJWT(userID int, email string, tenantID int, actor ActorClaims, impersonationID int, tokenID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:          userID,
+		Email:           email,
+		TenantID:        tenantID,
+		Actor:           &actor,
+		ImpersonationID: impersonationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Audience:  jwt.ClaimStrings{AudienceAccess},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return keySet.Sign(claims)
+}
+
+// ParseJWT, bir access token'ın imzasını ve süresini doğrular ve claim'lerini döner.
+// İptal (revocation) kontrolleri burada yapılmaz; bunun için TokenService kullanılmalıdır.
+func ParseJWT(tokenString string) (*JWTClaims, error) {
+	return parseToken(tokenString, AudienceAccess)
+}
+
+// ParseChallengeToken, bir 2FA challenge token'ını doğrular ve claim'lerini döner.
+func ParseChallengeToken(tokenString string) (*JWTClaims, error) {
+	return parseToken(tokenString, AudienceChallenge)
+}
+
+func parseToken(tokenString, audience string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, keySet.Keyfunc, jwt.WithAudience(audience))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+// WithUser, doğrulanmış kullanıcıyı context'e ekler.
+func WithUser(ctx context.Context, user *AuthUser) context.Context {
+	return context.WithValue(ctx, UserContextKey, user)
 }
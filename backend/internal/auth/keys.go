@@ -0,0 +1,266 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// hmacKeyID, asimetrik anahtar tanımlanmadığında kullanılan HS256 anahtarının kid değeridir.
+const hmacKeyID = "hs256"
+
+// SigningKey, kid ile tanımlanan tek bir JWT anahtarını temsil eder.
+// signKey nil ise anahtar sadece doğrulama için kullanılır (örn. emekliye ayrılmış anahtarlar).
+type SigningKey struct {
+	KID       string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	NotAfter  time.Time // Sıfır değilse, anahtar bu zamandan sonra doğrulama için de kullanılmaz
+}
+
+// usable, anahtarın verilen anda doğrulama için geçerli olup olmadığını döner.
+func (k *SigningKey) usable(now time.Time) bool {
+	return k.NotAfter.IsZero() || now.Before(k.NotAfter)
+}
+
+// isAsymmetric, anahtarın JWKS ile yayınlanabilir olup olmadığını döner.
+func (k *SigningKey) isAsymmetric() bool {
+	_, isHMAC := k.Method.(*jwt.SigningMethodHMAC)
+	return !isHMAC
+}
+
+// KeySetConfig, anahtar setinin nereden ve nasıl yükleneceğini tanımlar.
+type KeySetConfig struct {
+	KeysDir       string               // <kid>.pem dosyalarını içeren dizin
+	PrivateKeyPEM string               // Ortam değişkeninden gelen PEM anahtar (kid olarak ActiveKeyID kullanılır)
+	ActiveKeyID   string               // İmzalama için kullanılacak anahtarın kid değeri
+	RetiredKeys   map[string]time.Time // kid -> emekliye ayrılma zamanı
+	GracePeriod   time.Duration        // Emekli anahtarların doğrulamaya devam edeceği süre
+	HMACSecret    string               // Asimetrik anahtar yoksa kullanılan HS256 secret (sadece geliştirme)
+}
+
+// KeySet, imzalama için aktif anahtarı ve doğrulama için kabul edilen tüm anahtarları tutar.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+var keySet = &KeySet{keys: map[string]*SigningKey{}}
+
+// SetKeySet, uygulama genelinde kullanılacak anahtar setini ayarlar.
+func SetKeySet(ks *KeySet) {
+	keySet = ks
+}
+
+// GetKeySet, uygulama genelinde kullanılan anahtar setini döner.
+func GetKeySet() *KeySet {
+	return keySet
+}
+
+// LoadKeySet, anahtarları dosya sisteminden ve/veya ortam değişkeninden yükler.
+// Hiç asimetrik anahtar bulunamazsa HMACSecret ile HS256'ya geri düşer.
+func LoadKeySet(cfg KeySetConfig) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*SigningKey{}}
+
+	if cfg.KeysDir != "" {
+		files, err := filepath.Glob(filepath.Join(cfg.KeysDir, "*.pem"))
+		if err != nil {
+			return nil, fmt.Errorf("could not list JWT keys: %w", err)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("could not read JWT key %s: %w", file, err)
+			}
+			kid := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			key, err := parsePEMKey(kid, data)
+			if err != nil {
+				return nil, err
+			}
+			ks.keys[kid] = key
+		}
+	}
+
+	if cfg.PrivateKeyPEM != "" {
+		if cfg.ActiveKeyID == "" {
+			return nil, fmt.Errorf("JWT_ACTIVE_KID must be set when JWT_PRIVATE_KEY is used")
+		}
+		// Ortam değişkenlerinde satır sonları genellikle "\n" olarak kaçırılır.
+		pemData := strings.ReplaceAll(cfg.PrivateKeyPEM, `\n`, "\n")
+		key, err := parsePEMKey(cfg.ActiveKeyID, []byte(pemData))
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[cfg.ActiveKeyID] = key
+	}
+
+	if len(ks.keys) == 0 {
+		if cfg.HMACSecret == "" {
+			return nil, fmt.Errorf("no JWT signing keys configured")
+		}
+		log.Println("WARNING: No asymmetric JWT keys configured, falling back to HS256 with JWT_SECRET")
+		secret := []byte(cfg.HMACSecret)
+		ks.active = &SigningKey{KID: hmacKeyID, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+		ks.keys[hmacKeyID] = ks.active
+		return ks, nil
+	}
+
+	for kid, retiredAt := range cfg.RetiredKeys {
+		if key, ok := ks.keys[kid]; ok {
+			key.NotAfter = retiredAt.Add(cfg.GracePeriod)
+		}
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" && len(ks.keys) == 1 {
+		for kid := range ks.keys {
+			activeID = kid
+		}
+	}
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active JWT key %q not found", activeID)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active JWT key %q has no private key", activeID)
+	}
+	if !active.NotAfter.IsZero() {
+		return nil, fmt.Errorf("active JWT key %q is listed as retired", activeID)
+	}
+	ks.active = active
+
+	log.Printf("✓ JWT keys loaded (active kid: %s, total: %d)", active.KID, len(ks.keys))
+	return ks, nil
+}
+
+// parsePEMKey, PKCS#8/PKCS#1 özel anahtarları veya PKIX açık anahtarları okur.
+// Sadece açık anahtar verilmişse anahtar doğrulama amaçlı kullanılır.
+func parsePEMKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("JWT key %q is not valid PEM", kid)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("JWT key %q has unsupported PEM type %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse JWT key %q: %w", kid, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodRS256, verifyKey: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{KID: kid, Method: jwt.SigningMethodEdDSA, verifyKey: k}, nil
+	default:
+		return nil, fmt.Errorf("JWT key %q has unsupported key type %T", kid, parsed)
+	}
+}
+
+// Sign, claim'leri aktif anahtar ile imzalar ve header'a kid ekler.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	active := ks.active
+	if active == nil {
+		return "", fmt.Errorf("no active JWT signing key")
+	}
+
+	token := jwt.NewWithClaims(active.Method, claims)
+	token.Header["kid"] = active.KID
+	return token.SignedString(active.signKey)
+}
+
+// Keyfunc, jwt.Parse için kid'e göre doğrulama anahtarını seçer. Token'ın algoritması
+// anahtarın algoritmasıyla eşleşmek zorundadır; böylece algoritma karıştırma saldırıları engellenir.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok && kid == "" && ks.active != nil && !ks.active.isAsymmetric() {
+		// kid içermeyen eski HS256 token'ları
+		key, ok = ks.active, true
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if !key.usable(time.Now()) {
+		return nil, fmt.Errorf("signing key %q is no longer valid", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
+
+// JWK, RFC 7517'ye göre tek bir açık anahtarı temsil eder.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS, /.well-known/jwks.json ile yayınlanan anahtar setidir.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS, doğrulama için hâlâ geçerli olan tüm asimetrik açık anahtarları döner.
+// HMAC anahtarları gizli olduğundan asla yayınlanmaz.
+func (ks *KeySet) JWKS() JWKS {
+	now := time.Now()
+	result := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		if !key.isAsymmetric() || !key.usable(now) {
+			continue
+		}
+		jwk := JWK{Kid: key.KID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+	sort.Slice(result.Keys, func(i, j int) bool { return result.Keys[i].Kid < result.Keys[j].Kid })
+	return result
+}
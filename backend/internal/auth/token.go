@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken, refresh token gibi sunucu tarafında saklanan
+// tahmin edilemez token'lar için kriptografik olarak güvenli rastgele bir değer üretir.
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken, opak bir token'ın veritabanında saklanacak SHA-256 özetini döner.
+// Token'ların kendisi asla saklanmaz, sadece özetleri tutulur.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,20 +3,99 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBHost        string
-	DBPort        string
-	DBUser        string
-	DBPassword    string
-	DBName        string
-	JWTSecret     string
-	RedisAddr     string
-	RedisPassword string
-	RedisDB       int
+	DBHost          string
+	DBPort          string
+	DBUser          string
+	DBPassword      string
+	DBName          string
+	JWTSecret       string
+	JWTKeysDir      string
+	JWTPrivateKey   string
+	JWTActiveKeyID  string
+	JWTRetiredKeys  map[string]time.Time
+	JWTKeyGrace     time.Duration
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	RedisAddr       string
+	RedisPassword   string
+	RedisDB         int
+	// 2FA deneme sınırlaması
+	TwoFactorMaxAttempts int
+	TwoFactorLockout     time.Duration
+	TwoFactorMaxLockout  time.Duration
+	// true ise e-posta adresi doğrulanmamış kullanıcılar giriş yapamaz
+	RequireEmailVerification bool
+	// Login deneme sınırlaması
+	LoginMaxAttemptsPerEmail int
+	LoginMaxAttemptsPerIP    int
+	LoginWindow              time.Duration
+	LoginLockThreshold       int
+	LoginLockout             time.Duration
+	LoginMaxLockout          time.Duration
+	// Doğrulama e-postası yeniden gönderme sınırlaması
+	VerificationEmailMaxPerEmail int
+	VerificationEmailMaxPerIP    int
+	VerificationEmailWindow      time.Duration
+	// Şifre sıfırlama e-postası isteği sınırlaması
+	PasswordResetMaxPerEmail int
+	PasswordResetMaxPerIP    int
+	PasswordResetWindow      time.Duration
+	// Şifre politikası
+	PasswordMinLength     int
+	PasswordMaxBytes      int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordBlocklistFile string
+	// Şifre özetleme
+	PasswordHashAlgorithm string
+	Argon2Memory          int // KiB
+	Argon2Iterations      int
+	Argon2Parallelism     int
+	BcryptCost            int
+	// API anahtarları
+	APIKeyDefaultTTL time.Duration
+	APIKeyMaxTTL     time.Duration
+	// Harici kimlik sağlayıcılar (OIDC)
+	OIDCProviders []OIDCProviderConfig
+	// Yöneticinin kullanıcı adına işlem yapabileceği süre
+	ImpersonationTTL time.Duration
+	// Kullanıcıların çözümlenmiş yetki kümelerinin önbellekte tutulma süresi
+	PermissionCacheTTL time.Duration
+	// Yetkilendirme politikaları: dosya politikalarının dizini (boşsa sadece veritabanı politikaları)
+	// ve env.hour/env.weekday gibi ortam özniteliklerinin saat dilimi
+	PolicyDir      string
+	PolicyLocation *time.Location
+	// Çalışma ortamı (APP_ENV, varsayılan "production"). DBResetOnStart true ise açılışta tüm tablolar
+	// silinip şema baştan oluşturulur (bkz. migration.Reset); veri kaybına yol açtığı için sadece
+	// APP_ENV=development iken kabul edilir.
+	AppEnv         string
+	DBResetOnStart bool
+	// Örnek veriler (SEED_DEMO_DATA, varsayılan false): true ise boş veritabanına DemoUserPassword
+	// şifresiyle örnek kullanıcılar eklenir (bkz. migration.SeedDemoData). APP_ENV'e bağlı değildir;
+	// sadece açıkça istendiğinde çalışır.
+	SeedDemoData     bool
+	DemoUserPassword string
+}
+
+// OIDCProviderConfig, OIDC_<AD>_* ortam değişkenlerinden okunan tek bir sağlayıcıdır.
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AutoCreate   bool
 }
 
 func LoadConfig() (*Config, error) {
@@ -34,15 +113,329 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	accessTokenMinutes, err := getEnvInt("JWT_ACCESS_TOKEN_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenHours, err := getEnvInt("JWT_REFRESH_TOKEN_HOURS", 720)
+	if err != nil {
+		return nil, err
+	}
+	// Emekliye ayrılan anahtarlar varsayılan olarak en uzun ömürlü access token kadar doğrulamaya devam eder.
+	keyGraceMinutes, err := getEnvInt("JWT_KEY_GRACE_MINUTES", accessTokenMinutes)
+	if err != nil {
+		return nil, err
+	}
+	retiredKeys, err := parseRetiredKeys(os.Getenv("JWT_RETIRED_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactorMaxAttempts, err := getEnvInt("TWO_FACTOR_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	twoFactorLockoutSeconds, err := getEnvInt("TWO_FACTOR_LOCKOUT_SECONDS", 60)
+	if err != nil {
+		return nil, err
+	}
+	twoFactorMaxLockoutMinutes, err := getEnvInt("TWO_FACTOR_MAX_LOCKOUT_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+
+	requireEmailVerification, err := getEnvBool("REQUIRE_EMAIL_VERIFICATION", false)
+	if err != nil {
+		return nil, err
+	}
+
+	loginMaxAttemptsPerEmail, err := getEnvInt("LOGIN_MAX_ATTEMPTS_PER_EMAIL", 10)
+	if err != nil {
+		return nil, err
+	}
+	loginMaxAttemptsPerIP, err := getEnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 50)
+	if err != nil {
+		return nil, err
+	}
+	loginWindowMinutes, err := getEnvInt("LOGIN_WINDOW_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	loginLockThreshold, err := getEnvInt("LOGIN_LOCK_THRESHOLD", 5)
+	if err != nil {
+		return nil, err
+	}
+	loginLockoutMinutes, err := getEnvInt("LOGIN_LOCKOUT_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	loginMaxLockoutMinutes, err := getEnvInt("LOGIN_MAX_LOCKOUT_MINUTES", 1440)
+	if err != nil {
+		return nil, err
+	}
+	verificationEmailMaxPerEmail, err := getEnvInt("VERIFICATION_EMAIL_MAX_PER_EMAIL", 3)
+	if err != nil {
+		return nil, err
+	}
+	verificationEmailMaxPerIP, err := getEnvInt("VERIFICATION_EMAIL_MAX_PER_IP", 20)
+	if err != nil {
+		return nil, err
+	}
+	verificationEmailWindowMinutes, err := getEnvInt("VERIFICATION_EMAIL_WINDOW_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetMaxPerEmail, err := getEnvInt("PASSWORD_RESET_MAX_PER_EMAIL", 3)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetMaxPerIP, err := getEnvInt("PASSWORD_RESET_MAX_PER_IP", 20)
+	if err != nil {
+		return nil, err
+	}
+	passwordResetWindowMinutes, err := getEnvInt("PASSWORD_RESET_WINDOW_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordMinLength, err := getEnvInt("PASSWORD_MIN_LENGTH", 8)
+	if err != nil {
+		return nil, err
+	}
+	passwordMaxBytes, err := getEnvInt("PASSWORD_MAX_BYTES", 72)
+	if err != nil {
+		return nil, err
+	}
+	passwordRequireUpper, err := getEnvBool("PASSWORD_REQUIRE_UPPER", true)
+	if err != nil {
+		return nil, err
+	}
+	passwordRequireLower, err := getEnvBool("PASSWORD_REQUIRE_LOWER", true)
+	if err != nil {
+		return nil, err
+	}
+	passwordRequireDigit, err := getEnvBool("PASSWORD_REQUIRE_DIGIT", true)
+	if err != nil {
+		return nil, err
+	}
+	passwordRequireSymbol, err := getEnvBool("PASSWORD_REQUIRE_SYMBOL", false)
+	if err != nil {
+		return nil, err
+	}
+	argon2Memory, err := getEnvInt("ARGON2_MEMORY_KB", 64*1024)
+	if err != nil {
+		return nil, err
+	}
+	argon2Iterations, err := getEnvInt("ARGON2_ITERATIONS", 3)
+	if err != nil {
+		return nil, err
+	}
+	argon2Parallelism, err := getEnvInt("ARGON2_PARALLELISM", 2)
+	if err != nil {
+		return nil, err
+	}
+	if argon2Memory <= 0 || argon2Iterations <= 0 || argon2Parallelism <= 0 || argon2Parallelism > 255 {
+		return nil, fmt.Errorf("invalid argon2 parameters: memory=%d iterations=%d parallelism=%d", argon2Memory, argon2Iterations, argon2Parallelism)
+	}
+	bcryptCost, err := getEnvInt("BCRYPT_COST", 10)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyDefaultTTLDays, err := getEnvInt("API_KEY_DEFAULT_TTL_DAYS", 90)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyMaxTTLDays, err := getEnvInt("API_KEY_MAX_TTL_DAYS", 365)
+	if err != nil {
+		return nil, err
+	}
+	oidcProviders, err := loadOIDCProviders(os.Getenv("OIDC_PROVIDERS"))
+	if err != nil {
+		return nil, err
+	}
+	impersonationTTLMinutes, err := getEnvInt("IMPERSONATION_TTL_MINUTES", 30)
+	if err != nil {
+		return nil, err
+	}
+	permissionCacheTTLSeconds, err := getEnvInt("PERMISSION_CACHE_TTL_SECONDS", 300)
+	if err != nil {
+		return nil, err
+	}
+	policyLocation, err := time.LoadLocation(getEnv("POLICY_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid POLICY_TIMEZONE: %w", err)
+	}
+	appEnv := getEnv("APP_ENV", "production")
+	dbResetOnStart, err := getEnvBool("DB_RESET_ON_START", false)
+	if err != nil {
+		return nil, err
+	}
+	if dbResetOnStart && appEnv != "development" {
+		return nil, fmt.Errorf("DB_RESET_ON_START is only allowed when APP_ENV=development")
+	}
+	seedDemoData, err := getEnvBool("SEED_DEMO_DATA", false)
+	if err != nil {
+		return nil, err
+	}
+	demoUserPassword := os.Getenv("DEMO_USER_PASSWORD")
+	if seedDemoData && demoUserPassword == "" {
+		return nil, fmt.Errorf("DEMO_USER_PASSWORD is required when SEED_DEMO_DATA=true")
+	}
+
 	return &Config{
-		DBHost:        os.Getenv("DB_HOST"),
-		DBPort:        os.Getenv("DB_PORT"),
-		DBUser:        os.Getenv("DB_USER"),
-		DBPassword:    os.Getenv("DB_PASSWORD"),
-		DBName:        os.Getenv("DB_NAME"),
-		JWTSecret:     os.Getenv("JWT_SECRET"),
-		RedisAddr:     os.Getenv("REDIS_ADDR"),
-		RedisPassword: os.Getenv("REDIS_PASSWORD"),
-		RedisDB:       redisDB,
+		DBHost:          os.Getenv("DB_HOST"),
+		DBPort:          os.Getenv("DB_PORT"),
+		DBUser:          os.Getenv("DB_USER"),
+		DBPassword:      os.Getenv("DB_PASSWORD"),
+		DBName:          os.Getenv("DB_NAME"),
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		JWTKeysDir:      os.Getenv("JWT_KEYS_DIR"),
+		JWTPrivateKey:   os.Getenv("JWT_PRIVATE_KEY"),
+		JWTActiveKeyID:  os.Getenv("JWT_ACTIVE_KID"),
+		JWTRetiredKeys:  retiredKeys,
+		JWTKeyGrace:     time.Duration(keyGraceMinutes) * time.Minute,
+		AccessTokenTTL:  time.Duration(accessTokenMinutes) * time.Minute,
+		RefreshTokenTTL: time.Duration(refreshTokenHours) * time.Hour,
+		RedisAddr:       os.Getenv("REDIS_ADDR"),
+		RedisPassword:   os.Getenv("REDIS_PASSWORD"),
+		RedisDB:         redisDB,
+
+		TwoFactorMaxAttempts: twoFactorMaxAttempts,
+		TwoFactorLockout:     time.Duration(twoFactorLockoutSeconds) * time.Second,
+		TwoFactorMaxLockout:  time.Duration(twoFactorMaxLockoutMinutes) * time.Minute,
+
+		RequireEmailVerification: requireEmailVerification,
+
+		LoginMaxAttemptsPerEmail: loginMaxAttemptsPerEmail,
+		LoginMaxAttemptsPerIP:    loginMaxAttemptsPerIP,
+		LoginWindow:              time.Duration(loginWindowMinutes) * time.Minute,
+		LoginLockThreshold:       loginLockThreshold,
+		LoginLockout:             time.Duration(loginLockoutMinutes) * time.Minute,
+		LoginMaxLockout:          time.Duration(loginMaxLockoutMinutes) * time.Minute,
+
+		VerificationEmailMaxPerEmail: verificationEmailMaxPerEmail,
+		VerificationEmailMaxPerIP:    verificationEmailMaxPerIP,
+		VerificationEmailWindow:      time.Duration(verificationEmailWindowMinutes) * time.Minute,
+
+		PasswordResetMaxPerEmail: passwordResetMaxPerEmail,
+		PasswordResetMaxPerIP:    passwordResetMaxPerIP,
+		PasswordResetWindow:      time.Duration(passwordResetWindowMinutes) * time.Minute,
+
+		PasswordMinLength:     passwordMinLength,
+		PasswordMaxBytes:      passwordMaxBytes,
+		PasswordRequireUpper:  passwordRequireUpper,
+		PasswordRequireLower:  passwordRequireLower,
+		PasswordRequireDigit:  passwordRequireDigit,
+		PasswordRequireSymbol: passwordRequireSymbol,
+		PasswordBlocklistFile: os.Getenv("PASSWORD_BLOCKLIST_FILE"),
+
+		PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+		Argon2Memory:          argon2Memory,
+		Argon2Iterations:      argon2Iterations,
+		Argon2Parallelism:     argon2Parallelism,
+		BcryptCost:            bcryptCost,
+
+		APIKeyDefaultTTL: time.Duration(apiKeyDefaultTTLDays) * 24 * time.Hour,
+		APIKeyMaxTTL:     time.Duration(apiKeyMaxTTLDays) * 24 * time.Hour,
+
+		OIDCProviders:    oidcProviders,
+		ImpersonationTTL: time.Duration(impersonationTTLMinutes) * time.Minute,
+
+		PermissionCacheTTL: time.Duration(permissionCacheTTLSeconds) * time.Second,
+
+		PolicyDir:      getEnv("POLICY_DIR", ""),
+		PolicyLocation: policyLocation,
+		AppEnv:         appEnv,
+		DBResetOnStart: dbResetOnStart,
+
+		SeedDemoData:     seedDemoData,
+		DemoUserPassword: demoUserPassword,
 	}, nil
 }
+
+// getEnv, bir ortam değişkenini okur; tanımlı değilse varsayılan değeri döner.
+func getEnv(key, defaultValue string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// getEnvInt, bir ortam değişkenini tamsayı olarak okur; tanımlı değilse varsayılan değeri döner.
+func getEnvInt(key string, defaultValue int) (int, error) {
+	valStr := os.Getenv(key)
+	if valStr == "" {
+		return defaultValue, nil
+	}
+	var val int
+	if _, err := fmt.Sscanf(valStr, "%d", &val); err != nil {
+		return 0, fmt.Errorf("could not parse %s: %w", key, err)
+	}
+	return val, nil
+}
+
+// getEnvBool, bir ortam değişkenini bool olarak okur; tanımlı değilse varsayılan değeri döner.
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	valStr := os.Getenv(key)
+	if valStr == "" {
+		return defaultValue, nil
+	}
+	val, err := strconv.ParseBool(valStr)
+	if err != nil {
+		return false, fmt.Errorf("could not parse %s: %w", key, err)
+	}
+	return val, nil
+}
+
+// parseRetiredKeys, "kid1=2026-01-01T00:00:00Z,kid2=..." formatındaki emekli anahtar listesini okur.
+func parseRetiredKeys(value string) (map[string]time.Time, error) {
+	retired := map[string]time.Time{}
+	if value == "" {
+		return retired, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		kid, retiredAtStr, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("could not parse JWT_RETIRED_KEYS entry %q", entry)
+		}
+		retiredAt, err := time.Parse(time.RFC3339, retiredAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse JWT_RETIRED_KEYS entry %q: %w", entry, err)
+		}
+		retired[kid] = retiredAt
+	}
+	return retired, nil
+}
+
+// loadOIDCProviders, "corp,mock" gibi virgülle ayrılmış sağlayıcı adları için
+// OIDC_<AD>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL, _SCOPES ve _AUTO_CREATE
+// değişkenlerini okur.
+func loadOIDCProviders(value string) ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		provider := OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.IssuerURL == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("%sISSUER, %sCLIENT_ID and %sREDIRECT_URL are required", prefix, prefix, prefix)
+		}
+		autoCreate, err := getEnvBool(prefix+"AUTO_CREATE", false)
+		if err != nil {
+			return nil, err
+		}
+		provider.AutoCreate = autoCreate
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// APIKey, entegrasyonların (EDI, BI araçları vb.) kullanıcı adına login olmadan API'ye
+// erişmesini sağlayan anahtardır. Anahtarın kendisi saklanmaz; sadece SHA-256 özeti ve
+// listelerde tanınması için ilk kısmı (Prefix) tutulur.
+type APIKey struct {
+	BaseEntity
+	UserID         int            `json:"userId" gorm:"column:user_id;index"`                                    // Anahtarın adına işlem yaptığı kullanıcı veya servis hesabı
+	OrganizationID int            `json:"organizationId" gorm:"column:organization_id;not null;default:0;index"` // İsteklerin çalıştığı organizasyon; sahibi üye değilse anahtar geçersizdir
+	Name           string         `json:"name" gorm:"column:name;size:100"`
+	Prefix         string         `json:"prefix" gorm:"column:prefix;size:16"`
+	KeyHash        string         `json:"-" gorm:"column:key_hash;uniqueIndex;size:64"`
+	Scopes         pq.StringArray `json:"scopes" gorm:"column:scopes;type:text[]"` // "kaynak:işlem", "kaynak:*" veya "*"
+	ExpiresAt      time.Time      `json:"expiresAt" gorm:"column:expires_at"`
+	LastUsedAt     *time.Time     `json:"lastUsedAt,omitempty" gorm:"column:last_used_at"`
+	RevokedAt      *time.Time     `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	CreatedByID    int            `json:"createdById" gorm:"column:created_by_id"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+// IsExpired, anahtarın süresinin dolup dolmadığını kontrol eder.
+func (k *APIKey) IsExpired() bool {
+	return time.Now().After(k.ExpiresAt)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Denetim kaydı varlık türleri
+const (
+	AuditEntityUser          = "user"
+	AuditEntityCountry       = "country"
+	AuditEntityRole          = "role"
+	AuditEntityPolicy        = "policy"
+	AuditEntityOrganization  = "organization"
+	AuditEntityAPIKey        = "api_key"
+	AuditEntityImpersonation = "impersonation"
+)
+
+// Denetim kaydı işlemleri. Yetki yönetimi işlemleri PermissionAudit* sabitleri ile kaydedilir.
+const (
+	AuditActionCreated                  = "created"
+	AuditActionUpdated                  = "updated"
+	AuditActionDeleted                  = "deleted"
+	AuditActionPasswordChanged          = "password_changed"
+	AuditActionPasswordReset            = "password_reset"
+	AuditActionTwoFactorEnabled         = "2fa_enabled"
+	AuditActionTwoFactorDisabled        = "2fa_disabled"
+	AuditActionRevoked                  = "revoked"
+	AuditActionEnded                    = "ended"
+	AuditActionEmailVerified            = "email_verified"
+	AuditActionUnlocked                 = "unlocked"
+	AuditActionSessionRevoked           = "session_revoked"
+	AuditActionRecoveryCodesRegenerated = "recovery_codes_regenerated"
+)
+
+// AuditEvent, veriyi değiştiren bir işlemin kaydıdır. Kayıtlar değişiklik ile aynı transaction'da
+// IUnitOfWork üzerinden yazılır; transaction geri alınırsa kayıt da geri alınır. Tablo sadece
+// eklemeye açıktır: kayıtlar güncellenmez ve silinmez (bkz. migration'daki trigger).
+type AuditEvent struct {
+	BaseEntity
+	OrganizationID int       `json:"organizationId" gorm:"column:organization_id;not null;default:0;index:idx_audit_events_org_created,priority:1"`
+	ActorID        *int      `json:"actorId,omitempty" gorm:"column:actor_id;index"`         // İşlemi yapan kullanıcı; şifre sıfırlama gibi anonim işlemlerde boştur
+	ImpersonatorID *int      `json:"impersonatorId,omitempty" gorm:"column:impersonator_id"` // Kullanıcı adına işlem yapan yönetici
+	APIKeyID       *int      `json:"apiKeyId,omitempty" gorm:"column:api_key_id"`
+	EntityType     string    `json:"entityType" gorm:"column:entity_type;size:32;not null;index:idx_audit_events_entity,priority:1"`
+	EntityID       string    `json:"entityId" gorm:"column:entity_id;size:128;index:idx_audit_events_entity,priority:2"`
+	Action         string    `json:"action" gorm:"column:action;size:64;not null;index"`
+	Changes        string    `json:"changes" gorm:"column:changes;type:jsonb;not null;default:'{}'"` // Alan adı -> {"from": eski, "to": yeni}
+	RequestID      string    `json:"requestId" gorm:"column:request_id;size:64;index"`
+	IP             string    `json:"ip" gorm:"column:ip;size:64"`
+	CreatedAt      time.Time `json:"createdAt" gorm:"index:idx_audit_events_org_created,priority:2"`
+}
+
+// AuditEventFilter, denetim kayıtlarını sorgulama kriterleridir. Boş alanlar filtrelenmez.
+type AuditEventFilter struct {
+	OrganizationID int
+	ActorID        int
+	EntityType     string
+	EntityID       string
+	Action         string
+	RequestID      string
+	From           *time.Time
+	To             *time.Time
+}
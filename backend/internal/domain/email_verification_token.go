@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// EmailVerificationToken, bir e-posta adresinin sahipliğini doğrulamak için gönderilen
+// tek kullanımlık token'ı temsil eder. Email, doğrulanan adrestir; e-posta değişikliğinde
+// bu adres kullanıcının henüz onaylanmamış yeni adresidir.
+type EmailVerificationToken struct {
+	BaseEntity
+	UserID    int        `json:"userId" gorm:"column:user_id;index"`
+	Email     string     `json:"email" gorm:"column:email"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;uniqueIndex;size:64"`
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"column:expires_at"`
+	UsedAt    *time.Time `json:"usedAt,omitempty" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// IsExpired, token'ın süresinin dolup dolmadığını kontrol eder.
+func (t *EmailVerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
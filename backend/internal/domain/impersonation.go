@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// ImpersonationSession, bir yöneticinin bir kullanıcı adına işlem yaptığı süreyi kaydeder.
+// Kayıtlar silinmez; kimin, kimin adına, neden ve ne kadar süre işlem yaptığının denetim izidir.
+type ImpersonationSession struct {
+	BaseEntity
+	ImpersonatorID int        `json:"impersonatorId" gorm:"column:impersonator_id;index"` // İşlemi yapan yönetici
+	UserID         int        `json:"userId" gorm:"column:user_id;index"`                 // Adına işlem yapılan kullanıcı
+	Reason         string     `json:"reason" gorm:"column:reason"`
+	TokenID        string     `json:"-" gorm:"column:token_id;size:64"` // Üretilen token'ın jti değeri
+	IP             string     `json:"ip" gorm:"column:ip;size:64"`
+	ExpiresAt      time.Time  `json:"expiresAt" gorm:"column:expires_at"`
+	EndedAt        *time.Time `json:"endedAt,omitempty" gorm:"column:ended_at"` // Açıkça sonlandırıldıysa
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// IsActive, impersonation'ın hâlâ sürüp sürmediğini söyler.
+func (s *ImpersonationSession) IsActive() bool {
+	return s.EndedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// ImpersonationRequest, impersonation sırasında yapılan tek bir API isteğidir.
+type ImpersonationRequest struct {
+	BaseEntity
+	ImpersonationID int       `json:"impersonationId" gorm:"column:impersonation_id;index"`
+	RequestID       string    `json:"requestId" gorm:"column:request_id;size:64"`
+	Method          string    `json:"method" gorm:"column:method;size:16"`
+	Path            string    `json:"path" gorm:"column:path"`
+	Status          int       `json:"status" gorm:"column:status"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Login denemesi sonuçları
+const (
+	LoginResultSuccess            = "success"
+	LoginResultInvalidCredentials = "invalid_credentials"
+	LoginResultLocked             = "locked"
+	LoginResultThrottled          = "throttled"
+	LoginResultUnverified         = "unverified"
+)
+
+// LoginAttempt, 'login_attempts' tablosunda tutulan tek bir giriş denemesidir.
+// Bilinmeyen e-posta adresleriyle yapılan denemelerde UserID boştur.
+type LoginAttempt struct {
+	BaseEntity
+	UserID    *int      `json:"userId,omitempty" gorm:"column:user_id;index"`
+	Email     string    `json:"email" gorm:"column:email;index"`
+	IP        string    `json:"ip" gorm:"column:ip;size:64"`
+	UserAgent string    `json:"userAgent" gorm:"column:user_agent"`
+	Success   bool      `json:"success" gorm:"column:success"`
+	Result    string    `json:"result" gorm:"column:result;size:32"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// DefaultOrganizationSlug, çok kiracılı yapıya geçişte mevcut kullanıcıların ve kayıtların
+// taşındığı organizasyonun kısa adıdır.
+const DefaultOrganizationSlug = "default"
+
+// Organization, aynı kurulumda barındırılan şirketlerden (kiracı, tenant) biridir. Kiracıya ait
+// tablolar (bkz. ITenantEntity) organization_id sütunu ile organizasyona bağlanır.
+type Organization struct {
+	BaseEntity
+	Name      string    `json:"name" gorm:"column:name;size:128;not null"`
+	Slug      string    `json:"slug" gorm:"column:slug;size:64;uniqueIndex;not null"` // URL'lerde ve seçim ekranlarında kullanılan kısa ad
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// OrganizationMember, bir kullanıcının bir organizasyona üyeliğidir. Kullanıcılar birden fazla
+// organizasyonun üyesi olabilir; access token'daki "tid" claim'i aktif organizasyonu taşır.
+type OrganizationMember struct {
+	BaseEntity
+	OrganizationID int        `json:"organizationId" gorm:"column:organization_id;uniqueIndex:idx_organization_members_org_user"`
+	UserID         int        `json:"userId" gorm:"column:user_id;uniqueIndex:idx_organization_members_org_user;index"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty" gorm:"column:last_used_at"` // Login'de varsayılan organizasyonu seçmek için
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// OrganizationInvitation, bir kullanıcının bir organizasyona katılma davetidir. Kullanıcılar bütün
+// organizasyonlarda ortak olduğundan yönetici mevcut bir kullanıcıyı doğrudan üye yapamaz; kullanıcı
+// daveti kabul ettiğinde üye olur.
+type OrganizationInvitation struct {
+	BaseEntity
+	OrganizationID int          `json:"organizationId" gorm:"column:organization_id;uniqueIndex:idx_organization_invitations_org_user"`
+	UserID         int          `json:"userId" gorm:"column:user_id;uniqueIndex:idx_organization_invitations_org_user;index"`
+	InvitedByID    int          `json:"invitedById" gorm:"column:invited_by_id"`
+	ExpiresAt      time.Time    `json:"expiresAt" gorm:"column:expires_at;not null"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	Organization   Organization `json:"-" gorm:"foreignKey:OrganizationID"`
+}
+
+// IsExpired, davetin süresinin dolup dolmadığını kontrol eder.
+func (i *OrganizationInvitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+// ITenantEntity, bir organizasyona ait tabloların modelleridir. Bu modellerin sorguları
+// tenant.Plugin tarafından context'teki aktif organizasyonla otomatik olarak sınırlanır ve
+// oluşturulan kayıtların organization_id sütunu aktif organizasyonla doldurulur.
+type ITenantEntity interface {
+	GetOrganizationID() int
+}
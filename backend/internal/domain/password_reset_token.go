@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// PasswordResetToken, e-posta ile gönderilen tek kullanımlık şifre sıfırlama token'ını temsil eder.
+// Token'ın kendisi değil, sadece SHA-256 özeti saklanır.
+type PasswordResetToken struct {
+	BaseEntity
+	UserID    int        `json:"userId" gorm:"column:user_id;index"`
+	TokenHash string     `json:"-" gorm:"column:token_hash;uniqueIndex;size:64"`
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"column:expires_at"`
+	UsedAt    *time.Time `json:"usedAt,omitempty" gorm:"column:used_at"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// IsExpired, token'ın süresinin dolup dolmadığını kontrol eder.
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Yetki denetim kaydı işlemleri
+const (
+	PermissionAuditRoleCreated       = "role.created"
+	PermissionAuditRoleUpdated       = "role.updated"
+	PermissionAuditRoleDeleted       = "role.deleted"
+	PermissionAuditRoleAssigned      = "role.assigned"
+	PermissionAuditRoleUnassigned    = "role.unassigned"
+	PermissionAuditPermissionGranted = "permission.granted"
+	PermissionAuditPermissionRevoked = "permission.revoked"
+	PermissionAuditPolicyCreated     = "policy.created"
+	PermissionAuditPolicyUpdated     = "policy.updated"
+	PermissionAuditPolicyDeleted     = "policy.deleted"
+	PermissionAuditMemberInvited     = "organization.member_invited"
+	PermissionAuditInviteCanceled    = "organization.invite_canceled"
+	PermissionAuditMemberAdded       = "organization.member_added"
+	PermissionAuditMemberRemoved     = "organization.member_removed"
+)
+
+// PermissionAuditLog, rol ve yetki yönetiminde yapılan her değişikliğin kaydıdır.
+// Kayıtlar değişiklik ile aynı transaction'da yazılır ve silinmez.
+type PermissionAuditLog struct {
+	BaseEntity
+	OrganizationID int       `json:"organizationId" gorm:"column:organization_id;not null;default:0;index"`
+	Action         string    `json:"action" gorm:"column:action;size:32;index"`
+	ActorID        int       `json:"actorId" gorm:"column:actor_id;index"`                      // Değişikliği yapan kullanıcı
+	TargetUserID   *int      `json:"targetUserId,omitempty" gorm:"column:target_user_id;index"` // Yetkisi değişen kullanıcı
+	RoleID         *int      `json:"roleId,omitempty" gorm:"column:role_id;index"`
+	RoleName       string    `json:"roleName,omitempty" gorm:"column:role_name;size:64"`
+	Permission     string    `json:"permission,omitempty" gorm:"column:permission;size:128"`
+	Detail         string    `json:"detail,omitempty" gorm:"column:detail"` // Rol güncellemelerinde eklenen/çıkarılan yetkiler, politika değişikliklerinde politika adı
+	IP             string    `json:"ip" gorm:"column:ip;size:64"`
+	RequestID      string    `json:"requestId" gorm:"column:request_id;size:64"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func (l *PermissionAuditLog) GetOrganizationID() int {
+	return l.OrganizationID
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Policy, veritabanında saklanan bir yetkilendirme politikasıdır. Document, internal/policy
+// paketindeki politika JSON'unu tutar; kayıt adı dokümandaki ad ile aynıdır. Veritabanı
+// politikaları sadece ait oldukları organizasyondaki isteklere uygulanır.
+type Policy struct {
+	BaseEntity
+	OrganizationID int       `json:"organizationId" gorm:"column:organization_id;not null;default:0;uniqueIndex:idx_policies_org_name"`
+	Name           string    `json:"name" gorm:"column:name;size:128;uniqueIndex:idx_policies_org_name;not null"`
+	Document       string    `json:"document" gorm:"column:document;type:text;not null"`
+	Enabled        bool      `json:"enabled" gorm:"column:enabled;default:true"`
+	UpdatedBy      int       `json:"updatedBy" gorm:"column:updated_by"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (p *Policy) GetOrganizationID() int {
+	return p.OrganizationID
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RefreshToken, sunucu tarafında saklanan opak bir refresh token'ı temsil eder.
+// Aynı login ile başlayan tüm token'lar aynı FamilyID'yi paylaşır; rotasyon
+// sırasında eski token iptal edilir ve ReplacedByID yeni token'ı gösterir.
+type RefreshToken struct {
+	BaseEntity
+	UserID       int        `json:"userId" gorm:"column:user_id;index"`
+	FamilyID     string     `json:"familyId" gorm:"column:family_id;index;size:36"`
+	TokenHash    string     `json:"-" gorm:"column:token_hash;uniqueIndex;size:64"`
+	ExpiresAt    time.Time  `json:"expiresAt" gorm:"column:expires_at"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	ReplacedByID *int       `json:"replacedById,omitempty" gorm:"column:replaced_by_id"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// IsExpired, token'ın süresinin dolup dolmadığını kontrol eder.
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
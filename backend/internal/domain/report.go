@@ -15,13 +15,20 @@ const (
 )
 
 // Report, asenkron olarak oluşturulan bir raporu temsil eder.
+// Kullanıcılar sadece kendi istedikleri raporları görür (bkz. permission.ReportReadAll).
 type Report struct {
 	BaseEntity
-	Type      string          `json:"type" gorm:"column:type"`
-	Status    ReportStatus    `json:"status" gorm:"column:status"`
-	Payload   string          `json:"payload" gorm:"column:payload"` // Raporu oluşturmak için gereken parametreler (JSON)
-	Result    json.RawMessage `json:"result" gorm:"column:result"`   // Raporun sonucu (JSON)
-	Error     string          `json:"error,omitempty" gorm:"column:error"`
-	CreatedAt time.Time       `json:"createdAt"`
-	UpdatedAt time.Time       `json:"updatedAt"`
+	OrganizationID int             `json:"organizationId" gorm:"column:organization_id;not null;default:0;index"`
+	RequestedBy    int             `json:"requestedBy" gorm:"column:requested_by;index"` // Raporu isteyen kullanıcı; okuma bu sütuna göre kısıtlanır
+	Type           string          `json:"type" gorm:"column:type"`
+	Status         ReportStatus    `json:"status" gorm:"column:status"`
+	Payload        string          `json:"payload" gorm:"column:payload"` // Raporu oluşturmak için gereken parametreler (JSON)
+	Result         json.RawMessage `json:"result" gorm:"column:result"`   // Raporun sonucu (JSON)
+	Error          string          `json:"error,omitempty" gorm:"column:error"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+func (r *Report) GetOrganizationID() int {
+	return r.OrganizationID
 }
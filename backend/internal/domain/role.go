@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// SystemAdminRole, her kaynak üzerinde her işleme yetkili sistem rolünün adıdır.
+// Sistem rolleri API üzerinden silinemez ve yetkileri değiştirilemez.
+const SystemAdminRole = "admin"
+
+// PermissionWildcard, tüm kaynaklar ve işlemler için verilen yetkidir.
+const PermissionWildcard = "*"
+
+// Role, isimlendirilmiş bir yetki kümesidir. ParentID verilmişse rol, üst rolün
+// yetkilerini de devralır (örn. "muhasebe-sefi" -> "muhasebe"). Roller organizasyona aittir;
+// her organizasyon kendi rollerini tanımlar ve rol adları organizasyon içinde tekildir.
+type Role struct {
+	BaseEntity
+	OrganizationID int              `json:"organizationId" gorm:"column:organization_id;not null;default:0;uniqueIndex:idx_roles_org_name"`
+	Name           string           `json:"name" gorm:"column:name;uniqueIndex:idx_roles_org_name;size:64"`
+	Description    string           `json:"description" gorm:"column:description"`
+	ParentID       *int             `json:"parentId,omitempty" gorm:"column:parent_id;index"`
+	IsSystem       bool             `json:"isSystem" gorm:"column:is_system;default:false"`
+	Permissions    []RolePermission `json:"permissions,omitempty" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
+
+func (r *Role) GetOrganizationID() int {
+	return r.OrganizationID
+}
+
+// RolePermission, bir role verilen tek bir "kaynak:işlem" yetkisidir.
+// "kaynak:*" kaynağın tüm işlemlerini, "*" tüm yetkileri kapsar.
+type RolePermission struct {
+	BaseEntity
+	RoleID     int    `json:"roleId" gorm:"column:role_id;uniqueIndex:idx_role_permissions_role_permission"`
+	Permission string `json:"permission" gorm:"column:permission;size:128;uniqueIndex:idx_role_permissions_role_permission"`
+}
+
+// UserRole, bir kullanıcıya bir organizasyonda atanmış roldür. Aynı kullanıcının farklı
+// organizasyonlarda farklı rolleri olabilir.
+type UserRole struct {
+	BaseEntity
+	OrganizationID int       `json:"organizationId" gorm:"column:organization_id;not null;default:0;uniqueIndex:idx_user_roles_org_user_role"`
+	UserID         int       `json:"userId" gorm:"column:user_id;uniqueIndex:idx_user_roles_org_user_role"`
+	RoleID         int       `json:"roleId" gorm:"column:role_id;uniqueIndex:idx_user_roles_org_user_role;index"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func (r *UserRole) GetOrganizationID() int {
+	return r.OrganizationID
+}
+
+// UserGrant, bir kullanıcıya rol dışında doğrudan verilen yetkidir. Kalıcı yetkiler için
+// rol tanımlamak tercih edilmelidir; doğrudan yetkiler istisnai durumlar içindir.
+type UserGrant struct {
+	BaseEntity
+	OrganizationID int       `json:"organizationId" gorm:"column:organization_id;not null;default:0;uniqueIndex:idx_user_grants_org_user_permission"`
+	UserID         int       `json:"userId" gorm:"column:user_id;uniqueIndex:idx_user_grants_org_user_permission"`
+	Permission     string    `json:"permission" gorm:"column:permission;size:128;uniqueIndex:idx_user_grants_org_user_permission"`
+	GrantedBy      int       `json:"grantedBy" gorm:"column:granted_by"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func (g *UserGrant) GetOrganizationID() int {
+	return g.OrganizationID
+}
+
+// PermissionName, kaynak ve işlemden "kaynak:işlem" biçimindeki yetki adını üretir.
+func PermissionName(resource, action string) string {
+	return resource + ":" + action
+}
+
+// ValidPermission, bir yetkinin "kaynak:işlem", "kaynak:*" veya "*" biçiminde olduğunu kontrol eder.
+func ValidPermission(permission string) bool {
+	if permission == PermissionWildcard {
+		return true
+	}
+	resource, action, ok := strings.Cut(permission, ":")
+	return ok && resource != "" && action != "" && resource != "*" && !strings.Contains(action, ":")
+}
+
+// PermissionGranted, verilen yetki listesinin kaynak üzerindeki işleme izin verip vermediğini döner.
+func PermissionGranted(granted []string, resource, action string) bool {
+	for _, permission := range granted {
+		if permission == PermissionWildcard || permission == resource+":*" || permission == PermissionName(resource, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionCovers, verilen yetki listesinin bir yetkiyi (joker karakterler dahil) tamamen
+// kapsayıp kapsamadığını döner. "kaynak:*" sadece "*" veya "kaynak:*" ile, "*" sadece "*" ile kapsanır.
+// Yetki devrinde kullanıcının sahip olmadığı bir yetkiyi başkasına vermesini engellemek için kullanılır.
+func PermissionCovers(granted []string, permission string) bool {
+	for _, g := range granted {
+		if g == PermissionWildcard {
+			return true
+		}
+	}
+	if permission == PermissionWildcard {
+		return false
+	}
+	resource, action, _ := strings.Cut(permission, ":")
+	if action == "*" {
+		for _, g := range granted {
+			if g == resource+":*" {
+				return true
+			}
+		}
+		return false
+	}
+	return PermissionGranted(granted, resource, action)
+}
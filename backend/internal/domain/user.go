@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/lib/pq"
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
 
 // User, veritabanındaki 'users' tablosunu temsil eden ana modeldir.
 type User struct {
@@ -11,9 +15,18 @@ type User struct {
 	TwoFactorEnabled       bool           `json:"twoFactorEnabled" gorm:"column:two_factor_enabled;default:false"`
 	TwoFactorSecret        string         `json:"-" gorm:"column:two_factor_secret"`
 	TwoFactorRecoveryCodes pq.StringArray `json:"-" gorm:"column:two_factor_recovery_codes;type:text[]"`
+	TokensValidAfter       *time.Time     `json:"-" gorm:"column:tokens_valid_after"`             // Bu zamandan önce üretilen tüm token'lar geçersizdir
+	TwoFactorLastStep      int64          `json:"-" gorm:"column:two_factor_last_step;default:0"` // Son kabul edilen TOTP zaman adımı (replay koruması)
+	EmailVerifiedAt        *time.Time     `json:"emailVerifiedAt" gorm:"column:email_verified_at"`
+	PendingEmail           string         `json:"pendingEmail,omitempty" gorm:"column:pending_email"`         // Onay bekleyen yeni e-posta adresi
+	LockedUntil            *time.Time     `json:"lockedUntil,omitempty" gorm:"column:locked_until"`           // Çok fazla hatalı girişten sonra hesabın kilitli kalacağı zaman
+	ServiceAccount         bool           `json:"serviceAccount" gorm:"column:service_account;default:false"` // Şifre ile giriş yapamayan, sadece API anahtarı kullanan hesap
 }
 
 // UserPermission, bir kullanıcının belirli bir kaynak üzerindeki yetkilerini tanımlar.
+//
+// Deprecated: Yetkiler artık roller (Role, RolePermission, UserRole) üzerinden verilir.
+// Tablo sadece eski kayıtların rollere taşınması için tutulur.
 type UserPermission struct {
 	BaseEntity
 	UserID     int    `json:"userId" gorm:"column:user_id"`
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// UserIdentity, bir kullanıcıyı harici bir OIDC sağlayıcısındaki hesabına bağlar.
+// Eşleştirme e-posta ile değil (Provider, Subject) çifti ile yapılır; IdP tarafında
+// e-posta değişse bile kullanıcı aynı hesaba giriş yapar.
+type UserIdentity struct {
+	BaseEntity
+	UserID      int        `json:"userId" gorm:"column:user_id;index"`
+	Provider    string     `json:"provider" gorm:"column:provider;size:64;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject     string     `json:"subject" gorm:"column:subject;size:255;uniqueIndex:idx_user_identities_provider_subject"` // ID token'daki "sub"
+	Email       string     `json:"email" gorm:"column:email"`                                                               // Bağlantı anındaki IdP e-postası (bilgi amaçlı)
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty" gorm:"column:last_login_at"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// UserSession, bir login ile başlayan oturumu temsil eder. SessionID, oturumun refresh token
+// ailesinin kimliğidir (FamilyID) ve access token'larda "sid" claim'i olarak taşınır.
+type UserSession struct {
+	BaseEntity
+	SessionID      string     `json:"sessionId" gorm:"column:session_id;uniqueIndex;size:36"`
+	UserID         int        `json:"userId" gorm:"column:user_id;index"`
+	OrganizationID int        `json:"organizationId" gorm:"column:organization_id;not null;default:0"` // Aktif organizasyon; token yenilendiğinde korunur
+	Device         string     `json:"device" gorm:"column:device;size:128"`
+	UserAgent      string     `json:"userAgent" gorm:"column:user_agent"`
+	IP             string     `json:"ip" gorm:"column:ip;size:64"`
+	LastSeenAt     time.Time  `json:"lastSeenAt" gorm:"column:last_seen_at"`
+	ExpiresAt      time.Time  `json:"expiresAt" gorm:"column:expires_at"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty" gorm:"column:revoked_at"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// IsActive, oturumun iptal edilmemiş ve süresinin dolmamış olduğunu kontrol eder.
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
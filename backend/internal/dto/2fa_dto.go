@@ -14,7 +14,28 @@ type Enable2FARequest struct {
 }
 
 // Login2FARequest represents the request to verify 2FA during login.
+// ChallengeToken is the short-lived token returned by the password step;
+// Code is either a current TOTP code or an unused recovery code.
 type Login2FARequest struct {
-	UserID int    `json:"userId"`
-	Code   string `json:"code"`
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// Disable2FARequest represents the request to disable 2FA.
+// Either a valid TOTP/recovery code or the account password must be supplied.
+type Disable2FARequest struct {
+	Code     string `json:"code"`
+	Password string `json:"password"`
+}
+
+// RegenerateRecoveryCodesRequest represents the request to issue a new set of recovery codes.
+// Only a current TOTP code is accepted.
+type RegenerateRecoveryCodesRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorStatusResponse reports whether 2FA is enabled and how many recovery codes remain.
+type TwoFactorStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	RemainingRecoveryCodes int  `json:"remainingRecoveryCodes"`
 }
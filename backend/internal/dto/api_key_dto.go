@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// CreateAPIKeyRequest - Yeni API anahtarı oluşturmak için kullanılan DTO.
+// ExpiresInDays verilmezse varsayılan süre uygulanır; en fazla süre yapılandırma ile sınırlıdır.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required"`
+	Scopes        []string `json:"scopes" validate:"required"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// APIKeyResponse - Listelerde gösterilen anahtar bilgisi. Anahtarın kendisi asla dönmez.
+type APIKeyResponse struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	OrganizationID int        `json:"organizationId"`
+	Scopes         []string   `json:"scopes"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Expired        bool       `json:"expired"`
+}
+
+// APIKeyCreatedResponse - Anahtar oluşturulduğunda bir kez dönen yanıt. Key alanı
+// daha sonra tekrar gösterilemez.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
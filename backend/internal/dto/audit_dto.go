@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEventResponse - Veriyi değiştiren bir işlemin denetim kaydı.
+type AuditEventResponse struct {
+	ID             int             `json:"id"`
+	ActorID        *int            `json:"actorId,omitempty"`
+	ImpersonatorID *int            `json:"impersonatorId,omitempty"`
+	APIKeyID       *int            `json:"apiKeyId,omitempty"`
+	EntityType     string          `json:"entityType"`
+	EntityID       string          `json:"entityId"`
+	Action         string          `json:"action"`
+	Changes        json.RawMessage `json:"changes"` // Alan adı -> {"from": eski, "to": yeni}
+	RequestID      string          `json:"requestId"`
+	IP             string          `json:"ip"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// VerifyEmailRequest - E-postadaki token ile adresi doğrulamak için kullanılan DTO.
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest - Doğrulama e-postasını yeniden göndermek için kullanılan DTO.
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
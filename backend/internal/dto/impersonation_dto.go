@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// StartImpersonationRequest - Yöneticinin bir kullanıcı adına işlem yapmaya başlaması için DTO.
+type StartImpersonationRequest struct {
+	Reason string `json:"reason"` // Zorunlu; denetim kaydında saklanır (örn. destek talebi numarası)
+}
+
+// ImpersonationResponse - Impersonation başladığında dönen token. Refresh token verilmez.
+type ImpersonationResponse struct {
+	ImpersonationID int           `json:"impersonationId"`
+	Token           string        `json:"token"`
+	ExpiresIn       int           `json:"expiresIn"` // Token'ın saniye cinsinden geçerlilik süresi
+	User            *UserResponse `json:"user"`
+	ImpersonatorID  int           `json:"impersonatorId"`
+}
+
+// ImpersonationSessionResponse - Denetim için listelenen impersonation kaydı.
+type ImpersonationSessionResponse struct {
+	ID             int        `json:"id"`
+	ImpersonatorID int        `json:"impersonatorId"`
+	UserID         int        `json:"userId"`
+	Reason         string     `json:"reason"`
+	IP             string     `json:"ip"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	EndedAt        *time.Time `json:"endedAt,omitempty"`
+	Active         bool       `json:"active"`
+}
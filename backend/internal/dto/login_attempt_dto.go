@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// LoginAttemptResponse - Bir login denemesinin yöneticiye gösterilen özeti.
+type LoginAttemptResponse struct {
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	Success   bool      `json:"success"`
+	Result    string    `json:"result"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AccountLockStatusResponse - Bir hesabın kilit durumu ve son login denemeleri.
+type AccountLockStatusResponse struct {
+	Locked         bool                   `json:"locked"`
+	LockedUntil    *time.Time             `json:"lockedUntil,omitempty"`
+	RecentAttempts []LoginAttemptResponse `json:"recentAttempts"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// OIDCProviderResponse - Giriş ekranında gösterilecek harici kimlik sağlayıcı.
+type OIDCProviderResponse struct {
+	Name     string `json:"name"`
+	LoginURL string `json:"loginUrl"` // Tarayıcının yönlendirileceği başlangıç adresi
+}
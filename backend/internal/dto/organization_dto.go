@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+// OrganizationResponse - Kullanıcının üyesi olduğu bir organizasyon.
+type OrganizationResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Current   bool      `json:"current"` // Access token'ın aktif organizasyonu mu
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateOrganizationRequest - Yeni organizasyon oluşturmak için DTO. Slug küçük harf, rakam ve
+// tirelerden oluşur (örn. "acme-lojistik") ve sonradan değiştirilemez.
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// UpdateOrganizationRequest - Aktif organizasyonun adını değiştirmek için DTO.
+type UpdateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// SwitchOrganizationRequest - Oturumun aktif organizasyonunu değiştirmek için DTO.
+type SwitchOrganizationRequest struct {
+	OrganizationID int `json:"organizationId"`
+}
+
+// InviteOrganizationMemberRequest - Mevcut bir kullanıcıyı e-posta adresiyle aktif organizasyona davet etmek için DTO.
+type InviteOrganizationMemberRequest struct {
+	Email string `json:"email"`
+}
+
+// OrganizationInvitationResponse - Kullanıcının bekleyen bir organizasyon daveti.
+type OrganizationInvitationResponse struct {
+	OrganizationID   int       `json:"organizationId"`
+	OrganizationName string    `json:"organizationName"`
+	OrganizationSlug string    `json:"organizationSlug"`
+	InvitedByID      int       `json:"invitedById"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ForgotPasswordRequest - Şifre sıfırlama e-postası istemek için kullanılan DTO.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ChangePasswordRequest - Oturum açmış kullanıcının şifresini değiştirmesi için kullanılan DTO.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+// ResetPasswordRequest - E-postadaki token ile yeni şifre belirlemek için kullanılan DTO.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+import "time"
+
+// PermissionResponse - Registry'de tanımlı tek bir yetki.
+type PermissionResponse struct {
+	Permission  string `json:"permission"` // "kaynak:işlem"; rollerde ve API anahtarı kapsamlarında bu değer kullanılır
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+// CreateRoleRequest - Yeni rol oluşturmak için DTO.
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ParentID    *int     `json:"parentId"`    // Verilirse rol, üst rolün yetkilerini devralır
+	Permissions []string `json:"permissions"` // "kaynak:işlem", "kaynak:*" veya "*"
+}
+
+// UpdateRoleRequest - Rolü güncellemek için DTO. Yetki listesi mevcut listenin yerine geçer.
+type UpdateRoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ParentID    *int     `json:"parentId"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleResponse - Rol ve role doğrudan verilen yetkiler. Devralınan yetkiler dahil değildir.
+type RoleResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ParentID    *int      `json:"parentId,omitempty"`
+	IsSystem    bool      `json:"isSystem"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// AssignRoleRequest - Kullanıcıya rol atamak için DTO.
+type AssignRoleRequest struct {
+	RoleID int `json:"roleId"`
+}
+
+// GrantPermissionRequest - Kullanıcıya doğrudan yetki vermek için DTO.
+type GrantPermissionRequest struct {
+	Permission string `json:"permission"`
+}
+
+// UserGrantResponse - Kullanıcıya doğrudan verilmiş bir yetki.
+type UserGrantResponse struct {
+	Permission string    `json:"permission"`
+	GrantedBy  int       `json:"grantedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// UserAccessResponse - Kullanıcıya atanmış roller ve doğrudan verilmiş yetkiler.
+type UserAccessResponse struct {
+	UserID int                 `json:"userId"`
+	Roles  []RoleResponse      `json:"roles"`
+	Grants []UserGrantResponse `json:"grants"`
+}
+
+// EffectivePermissionsResponse - Kullanıcının rolleri, kalıtım ve doğrudan yetkiler sonucunda sahip olduğu yetkiler.
+type EffectivePermissionsResponse struct {
+	UserID int      `json:"userId"`
+	Roles  []string `json:"roles"`  // Atanmış ve devralınan roller
+	Grants []string `json:"grants"` // Joker karakterler dahil ham yetkiler
+	// Permissions, registry'deki yetkilerden kullanıcının sahip olduklarıdır; joker karakterler açılmıştır.
+	// Arayüz menü öğelerini bu listeye göre gösterir veya gizler.
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionAuditResponse - Rol ve yetki yönetimi denetim kaydı.
+type PermissionAuditResponse struct {
+	ID           int       `json:"id"`
+	Action       string    `json:"action"`
+	ActorID      int       `json:"actorId"`
+	TargetUserID *int      `json:"targetUserId,omitempty"`
+	RoleID       *int      `json:"roleId,omitempty"`
+	RoleName     string    `json:"roleName,omitempty"`
+	Permission   string    `json:"permission,omitempty"`
+	Detail       string    `json:"detail,omitempty"`
+	IP           string    `json:"ip"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"ths-erp.com/internal/policy"
+)
+
+// PolicyRequest - Veritabanında politika oluşturmak veya güncellemek için DTO.
+type PolicyRequest struct {
+	// Document, politika JSON'udur (bkz. internal/policy). Bilinmeyen alanlar reddedilir.
+	Document json.RawMessage `json:"document"`
+	Enabled  *bool           `json:"enabled"` // Verilmezse politika aktiftir
+}
+
+// PolicyResponse - Yüklü veya veritabanında kayıtlı bir politika.
+type PolicyResponse struct {
+	ID        int           `json:"id,omitempty"` // Sadece veritabanı politikalarında
+	Source    string        `json:"source"`       // "file:<dosya>" veya "db"
+	Enabled   bool          `json:"enabled"`
+	Policy    policy.Policy `json:"policy"`
+	UpdatedBy int           `json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time    `json:"updatedAt,omitempty"`
+}
+
+// ExplainPolicyRequest - Bir yetki kararını açıklamak (dry-run) için DTO.
+type ExplainPolicyRequest struct {
+	UserID     int               `json:"userId"`
+	Permission string            `json:"permission"` // Registry'de tanımlı "kaynak:işlem"
+	Resource   policy.Attributes `json:"resource"`   // Kaynak öznitelikleri (örn. {"id": 5, "amount": 9000})
+	Env        policy.Attributes `json:"env"`        // Ortam özniteliklerini değiştirmek için (örn. {"hour": 22})
+	// Policies, kaydedilmeden önce etkisini görmek için sadece bu değerlendirmede uygulanacak politikalardır.
+	Policies []json.RawMessage `json:"policies"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// SessionResponse - Kullanıcının açık bir oturumu (cihazı).
+type SessionResponse struct {
+	SessionID  string    `json:"sessionId"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"` // İsteği yapan oturum ise true
+}
@@ -18,16 +18,31 @@ type LoginRequest struct {
 }
 
 // LoginResponse - Başarılı giriş sonrası dönen DTO.
+// Token kısa ömürlü access token'dır; süresi dolduğunda RefreshToken ile yenilenir.
+// Kullanıcının 2FA'sı açıksa sadece TwoFactorRequired ve ChallengeToken döner;
+// giriş /login/2fa ile tamamlanır.
 type LoginResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token             string        `json:"token,omitempty"`
+	RefreshToken      string        `json:"refreshToken,omitempty"`
+	ExpiresIn         int           `json:"expiresIn"` // Token'ın saniye cinsinden geçerlilik süresi
+	User              *UserResponse `json:"user,omitempty"`
+	OrganizationID    int           `json:"organizationId,omitempty"` // Token'ın "tid" claim'indeki aktif organizasyon
+	TwoFactorRequired bool          `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string        `json:"challengeToken,omitempty"`
+}
+
+// RefreshTokenRequest - Access token yenilemek için kullanılan DTO.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
 // CreateUserRequest - Yeni kullanıcı oluşturmak için kullanılan DTO.
+// ServiceAccount true ise şifre verilmez; hesap sadece API anahtarları ile kullanılır.
 type CreateUserRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Name           string `json:"name" validate:"required"`
+	Email          string `json:"email" validate:"required,email"`
+	Password       string `json:"password"`
+	ServiceAccount bool   `json:"serviceAccount"`
 }
 
 // UpdateUserRequest - Kullanıcı bilgilerini güncellemek için kullanılan DTO.
@@ -40,8 +55,11 @@ type UpdateUserRequest struct {
 // Bu DTO, domain.User modelindeki hassas bilgileri (örn: PasswordHash) dışarıya sızdırmaz.
 type UserResponse struct {
 	BaseResponse
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"emailVerified"`
+	PendingEmail   string `json:"pendingEmail,omitempty"` // Onay bekleyen yeni e-posta adresi
+	ServiceAccount bool   `json:"serviceAccount,omitempty"`
 }
 
 // Derleme zamanında UserResponse'un domain.IResponse arayüzünü uyguladığını kontrol eder.
@@ -2,21 +2,20 @@ package graphql
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strings"
 
 	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/handler/http/middleware"
 	"ths-erp.com/internal/service"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/graphql-go/graphql"
 )
 
 // SetupHandler, GraphQL endpoint'ini ve GraphiQL arayüzünü Fiber app'e ekler.
-func SetupHandler(app *fiber.App, userService service.IUserService, permService service.IPermissionService) {
-	schema, err := buildGraphQLSchema(userService, permService)
+func SetupHandler(app *fiber.App, userService service.IUserService, permService service.IPermissionService, tokenService service.ITokenService, apiKeyService service.IAPIKeyService, impersonationService service.IImpersonationService) {
+	schema, err := buildGraphQLSchema(userService, permService, tokenService)
 	if err != nil {
 		log.Fatalf("Failed to create GraphQL schema: %v", err)
 	}
@@ -28,10 +27,11 @@ func SetupHandler(app *fiber.App, userService service.IUserService, permService
 	}
 
 	// GraphQL endpoint'i
-	app.All("/graphql", authMiddleware, func(c *fiber.Ctx) error {
+	app.All("/graphql", authMiddleware(tokenService, apiKeyService), middleware.ImpersonationAudit(impersonationService), func(c *fiber.Ctx) error {
 		// Middleware'den gelen kullanıcıyı al
 		user := c.Locals("user")
-		ctx := context.Background()
+		// UserContext, AttachLogger'ın eklediği logger ve istemci bilgilerini taşır.
+		ctx := c.UserContext()
 		if user != nil {
 			// GraphQL context'ine kullanıcıyı ekle
 			ctx = context.WithValue(ctx, auth.UserContextKey, user)
@@ -61,41 +61,37 @@ func SetupHandler(app *fiber.App, userService service.IUserService, permService
 	})
 }
 
-// authMiddleware, GraphQL endpoint'i için özel bir JWT doğrulama middleware'idir.
+// authMiddleware, GraphQL endpoint'i için özel bir JWT / API anahtarı doğrulama middleware'idir.
 // REST API'den farklı olarak, token olmasa bile devam eder, sadece context'e kullanıcı eklemez.
-func authMiddleware(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return c.Next()
-	}
+func authMiddleware(tokenService service.ITokenService, apiKeyService service.IAPIKeyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+
+		if apiKey := auth.APIKeyFromHeaders(c.Get("X-API-Key"), authHeader); apiKey != "" {
+			if user, err := apiKeyService.Authenticate(c.UserContext(), apiKey); err == nil {
+				c.Locals("user", user)
+			}
+			return c.Next()
+		}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
-		return c.Next() // Hatalı format, yine de devam et.
-	}
+		if authHeader == "" {
+			return c.Next()
+		}
 
-	token, err := jwt.ParseWithClaims(tokenString, &auth.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader {
+			return c.Next() // Hatalı format, yine de devam et.
 		}
-		return auth.GetJWTSecret(), nil
-	})
 
-	// Token geçersizse veya süresi dolmuşsa, yine de devam et.
-	// Resolver'lar context'te kullanıcı olup olmadığını kontrol ederek yetkilendirme yapar.
-	if err != nil || !token.Valid {
-		return c.Next()
-	}
+		// Token geçersizse, süresi dolmuşsa veya iptal edilmişse, yine de devam et.
+		// Resolver'lar context'te kullanıcı olup olmadığını kontrol ederek yetkilendirme yapar.
+		user, err := tokenService.ValidateAccessToken(c.UserContext(), tokenString)
+		if err != nil {
+			return c.Next()
+		}
+
+		c.Locals("user", user)
 
-	claims, ok := token.Claims.(*auth.JWTClaims)
-	if !ok {
 		return c.Next()
 	}
-
-	c.Locals("user", &auth.AuthUser{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-	})
-
-	return c.Next()
 }
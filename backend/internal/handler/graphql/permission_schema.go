@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/service"
+
+	"github.com/graphql-go/graphql"
+)
+
+// permissionTypes, rol ve yetki alanlarının kullandığı GraphQL tipleridir.
+type permissionTypes struct {
+	role                 *graphql.Object
+	effectivePermissions *graphql.Object
+}
+
+func newPermissionTypes() permissionTypes {
+	return permissionTypes{
+		role: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Role",
+			Fields: graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.Int},
+				"name":        &graphql.Field{Type: graphql.String},
+				"description": &graphql.Field{Type: graphql.String},
+				"parentId":    &graphql.Field{Type: graphql.Int},
+				"isSystem":    &graphql.Field{Type: graphql.Boolean},
+				"permissions": &graphql.Field{Type: graphql.NewList(graphql.String)},
+			},
+		}),
+		effectivePermissions: graphql.NewObject(graphql.ObjectConfig{
+			Name: "EffectivePermissions",
+			Fields: graphql.Fields{
+				"userId": &graphql.Field{Type: graphql.Int},
+				"roles":  &graphql.Field{Type: graphql.NewList(graphql.String)},
+				"grants": &graphql.Field{Type: graphql.NewList(graphql.String)},
+				// Registry'deki yetkilerden kullanıcının sahip oldukları; arayüz menüleri bu listeyi kullanır.
+				"permissions": &graphql.Field{Type: graphql.NewList(graphql.String)},
+			},
+		}),
+	}
+}
+
+// buildPermissionQueryFields, rol ve yetki sorgularını oluşturur.
+func buildPermissionQueryFields(permService service.IPermissionService, types permissionTypes) graphql.Fields {
+	return graphql.Fields{
+		"roles": &graphql.Field{
+			Type: graphql.NewList(types.role),
+			Resolve: withPermission(permService, permission.RoleSelect, func(p graphql.ResolveParams) (interface{}, error) {
+				return permService.ListRoles(p.Context)
+			}),
+		},
+		"userPermissions": &graphql.Field{
+			Type: types.effectivePermissions,
+			Args: graphql.FieldConfigArgument{
+				"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: withPermission(permService, permission.RoleSelect, func(p graphql.ResolveParams) (interface{}, error) {
+				return permService.GetEffectivePermissions(p.Context, p.Args["userId"].(int))
+			}),
+		},
+		"myPermissions": &graphql.Field{
+			Type: types.effectivePermissions,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				authUser, err := auth.GetUserFromContext(p.Context)
+				if err != nil {
+					return nil, err
+				}
+
+				return permService.GetMyPermissions(p.Context, authUser)
+			},
+		},
+	}
+}
+
+// buildPermissionMutationFields, kullanıcılara rol ve yetki atama mutasyonlarını oluşturur.
+func buildPermissionMutationFields(permService service.IPermissionService) graphql.Fields {
+	userRoleArgs := graphql.FieldConfigArgument{
+		"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"roleId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+	userPermissionArgs := graphql.FieldConfigArgument{
+		"userId":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"permission": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+	}
+
+	return graphql.Fields{
+		"assignRole": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: userRoleArgs,
+			Resolve: withPermission(permService, permission.RoleAssign, func(p graphql.ResolveParams) (interface{}, error) {
+				err := permService.AssignRole(p.Context, p.Args["userId"].(int), p.Args["roleId"].(int))
+				return err == nil, err
+			}),
+		},
+		"unassignRole": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: userRoleArgs,
+			Resolve: withPermission(permService, permission.RoleAssign, func(p graphql.ResolveParams) (interface{}, error) {
+				err := permService.UnassignRole(p.Context, p.Args["userId"].(int), p.Args["roleId"].(int))
+				return err == nil, err
+			}),
+		},
+		"grantPermission": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: userPermissionArgs,
+			Resolve: withPermission(permService, permission.RoleAssign, func(p graphql.ResolveParams) (interface{}, error) {
+				err := permService.GrantPermission(p.Context, p.Args["userId"].(int), p.Args["permission"].(string))
+				return err == nil, err
+			}),
+		},
+		"revokePermission": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: userPermissionArgs,
+			Resolve: withPermission(permService, permission.RoleAssign, func(p graphql.ResolveParams) (interface{}, error) {
+				err := permService.RevokePermission(p.Context, p.Args["userId"].(int), p.Args["permission"].(string))
+				return err == nil, err
+			}),
+		},
+	}
+}
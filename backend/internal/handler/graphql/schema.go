@@ -1,17 +1,21 @@
 package graphql
 
 import (
+	"errors"
 	"fmt"
 
+	"ths-erp.com/internal/apperrors"
 	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/policy"
 	"ths-erp.com/internal/service"
 
 	"github.com/graphql-go/graphql"
 )
 
 // buildGraphQLSchema, servisleri kullanarak GraphQL şemasını oluşturur.
-func buildGraphQLSchema(userService service.IUserService, permService service.IPermissionService) (graphql.Schema, error) {
+func buildGraphQLSchema(userService service.IUserService, permService service.IPermissionService, tokenService service.ITokenService) (graphql.Schema, error) {
 	// User Tipi
 	userType := graphql.NewObject(graphql.ObjectConfig{
 		Name: "User",
@@ -19,6 +23,10 @@ func buildGraphQLSchema(userService service.IUserService, permService service.IP
 			"id":    &graphql.Field{Type: graphql.Int},
 			"name":  &graphql.Field{Type: graphql.String},
 			"email": &graphql.Field{Type: graphql.String},
+			// E-posta değişikliği onaylanana kadar yeni adres pendingEmail'de bekler.
+			"emailVerified":  &graphql.Field{Type: graphql.Boolean},
+			"pendingEmail":   &graphql.Field{Type: graphql.String},
+			"serviceAccount": &graphql.Field{Type: graphql.Boolean},
 		},
 	})
 
@@ -26,21 +34,37 @@ func buildGraphQLSchema(userService service.IUserService, permService service.IP
 	loginResponseType := graphql.NewObject(graphql.ObjectConfig{
 		Name: "LoginResponse",
 		Fields: graphql.Fields{
-			"token": &graphql.Field{Type: graphql.String},
-			"user":  &graphql.Field{Type: userType},
+			"token":        &graphql.Field{Type: graphql.String},
+			"refreshToken": &graphql.Field{Type: graphql.String},
+			"expiresIn":    &graphql.Field{Type: graphql.Int},
+			"user":         &graphql.Field{Type: userType},
+			// 2FA açıksa login sadece bu alanları döner; giriş login2FA ile tamamlanır.
+			"twoFactorRequired": &graphql.Field{Type: graphql.Boolean},
+			"challengeToken":    &graphql.Field{Type: graphql.String},
 		},
 	})
 
+	// Rol ve yetki tipleri
+	permTypes := newPermissionTypes()
+
 	// Root Query
+	queryFields := buildQueryFields(userService, permService, userType)
+	for name, field := range buildPermissionQueryFields(permService, permTypes) {
+		queryFields[name] = field
+	}
 	rootQuery := graphql.NewObject(graphql.ObjectConfig{
 		Name:   "Query",
-		Fields: buildQueryFields(userService, permService, userType),
+		Fields: queryFields,
 	})
 
 	// Root Mutation
+	mutationFields := buildMutationFields(userService, permService, tokenService, userType, loginResponseType)
+	for name, field := range buildPermissionMutationFields(permService) {
+		mutationFields[name] = field
+	}
 	rootMutation := graphql.NewObject(graphql.ObjectConfig{
 		Name:   "Mutation",
-		Fields: buildMutationFields(userService, permService, userType, loginResponseType),
+		Fields: mutationFields,
 	})
 
 	// Şemayı Oluştur
@@ -58,40 +82,16 @@ func buildQueryFields(userService service.IUserService, permService service.IPer
 			Args: graphql.FieldConfigArgument{
 				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
 			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				authUser, err := auth.GetUserFromContext(p.Context)
-				if err != nil {
-					return nil, err
-				}
-
-				if allowed, err := permService.CheckPermission(p.Context, authUser.UserID, "users", "select"); !allowed || err != nil {
-					if err != nil {
-						return nil, fmt.Errorf("permission check failed: %v", err)
-					}
-					return nil, fmt.Errorf("permission denied")
-				}
-
+			Resolve: withPolicy(permService, permission.UserSelect, argAttributes("id"), func(p graphql.ResolveParams) (interface{}, error) {
 				id := p.Args["id"].(int)
 				return userService.GetUser(p.Context, id)
-			},
+			}),
 		},
 		"users": &graphql.Field{
 			Type: graphql.NewList(userType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				authUser, err := auth.GetUserFromContext(p.Context)
-				if err != nil {
-					return nil, err
-				}
-
-				if allowed, err := permService.CheckPermission(p.Context, authUser.UserID, "users", "select"); !allowed || err != nil {
-					if err != nil {
-						return nil, fmt.Errorf("permission check failed: %v", err)
-					}
-					return nil, fmt.Errorf("permission denied")
-				}
-
+			Resolve: withPermission(permService, permission.UserSelect, func(p graphql.ResolveParams) (interface{}, error) {
 				return userService.GetAllUsers(p.Context)
-			},
+			}),
 		},
 		"me": &graphql.Field{
 			Type: userType,
@@ -108,7 +108,7 @@ func buildQueryFields(userService service.IUserService, permService service.IPer
 }
 
 // buildMutationFields, GraphQL Mutation alanlarını oluşturur.
-func buildMutationFields(userService service.IUserService, permService service.IPermissionService, userType, loginResponseType *graphql.Object) graphql.Fields {
+func buildMutationFields(userService service.IUserService, permService service.IPermissionService, tokenService service.ITokenService, userType, loginResponseType *graphql.Object) graphql.Fields {
 	return graphql.Fields{
 		"login": &graphql.Field{
 			Type: loginResponseType,
@@ -122,53 +122,67 @@ func buildMutationFields(userService service.IUserService, permService service.I
 
 				user, err := userService.Authenticate(p.Context, email, password)
 				if err != nil {
+					if errors.Is(err, apperrors.ErrEmailNotVerified) {
+						return nil, fmt.Errorf("email address is not verified")
+					}
+					if errors.Is(err, apperrors.ErrAccountLocked) || errors.Is(err, apperrors.ErrTooManyAttempts) {
+						return nil, fmt.Errorf("too many failed attempts, try again later")
+					}
 					return nil, fmt.Errorf("invalid credentials")
 				}
-				token, err := auth.GenerateJWT(user.ID, user.Email)
-				if err != nil {
-					return nil, fmt.Errorf("could not generate token")
+
+				if user.TwoFactorEnabled {
+					return tokenService.IssueChallenge(p.Context, user)
 				}
 
-				userResponse, err := userService.GetUser(p.Context, user.ID)
+				loginResponse, err := tokenService.IssueTokens(p.Context, user)
 				if err != nil {
-					return nil, err
+					return nil, fmt.Errorf("could not generate token")
 				}
 
-				return dto.LoginResponse{Token: token, User: userResponse}, nil
+				return loginResponse, nil
 			},
 		},
-		"createUser": &graphql.Field{
-			Type: userType,
+		"login2FA": &graphql.Field{
+			Type: loginResponseType,
 			Args: graphql.FieldConfigArgument{
-				"name":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
-				"email":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
-				"password": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"challengeToken": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"code":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				authUser, err := auth.GetUserFromContext(p.Context)
-				if err != nil {
-					return nil, err
-				}
+				challengeToken := p.Args["challengeToken"].(string)
+				code := p.Args["code"].(string)
 
-				if allowed, err := permService.CheckPermission(p.Context, authUser.UserID, "users", "add"); !allowed || err != nil {
-					if err != nil {
-						return nil, fmt.Errorf("permission check failed: %v", err)
+				loginResponse, err := userService.Login2FA(p.Context, challengeToken, code)
+				if err != nil {
+					if errors.Is(err, apperrors.ErrTooManyAttempts) {
+						return nil, fmt.Errorf("too many failed attempts, try again later")
 					}
-					return nil, fmt.Errorf("permission denied")
+					return nil, fmt.Errorf("invalid 2FA challenge or code")
 				}
 
-				req := &dto.CreateUserRequest{
-					Name:     p.Args["name"].(string),
-					Email:    p.Args["email"].(string),
-					Password: p.Args["password"].(string),
+				return loginResponse, nil
+			},
+		},
+		"refreshToken": &graphql.Field{
+			Type: loginResponseType,
+			Args: graphql.FieldConfigArgument{
+				"refreshToken": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				refreshToken := p.Args["refreshToken"].(string)
+
+				loginResponse, err := tokenService.Refresh(p.Context, refreshToken)
+				if err != nil {
+					return nil, fmt.Errorf("invalid or expired refresh token")
 				}
-				return userService.CreateUser(p.Context, req)
+
+				return loginResponse, nil
 			},
 		},
-		"updateUser": &graphql.Field{
+		"updateMe": &graphql.Field{
 			Type: userType,
 			Args: graphql.FieldConfigArgument{
-				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
 				"name":  &graphql.ArgumentConfig{Type: graphql.String},
 				"email": &graphql.ArgumentConfig{Type: graphql.String},
 			},
@@ -177,15 +191,13 @@ func buildMutationFields(userService service.IUserService, permService service.I
 				if err != nil {
 					return nil, err
 				}
-
-				if allowed, err := permService.CheckPermission(p.Context, authUser.UserID, "users", "update"); !allowed || err != nil {
-					if err != nil {
-						return nil, fmt.Errorf("permission check failed: %v", err)
-					}
-					return nil, fmt.Errorf("permission denied")
+				if authUser.IsAPIKey() {
+					return nil, apperrors.ErrAPIKeyNotAllowed
+				}
+				if authUser.IsImpersonated() {
+					return nil, apperrors.ErrImpersonationBlocked
 				}
 
-				id := p.Args["id"].(int)
 				req := &dto.UpdateUserRequest{}
 				if name, ok := p.Args["name"].(string); ok {
 					req.Name = name
@@ -193,31 +205,136 @@ func buildMutationFields(userService service.IUserService, permService service.I
 				if email, ok := p.Args["email"].(string); ok {
 					req.Email = email
 				}
-				return userService.UpdateUser(p.Context, id, req)
+				return userService.UpdateUser(p.Context, authUser.UserID, req)
 			},
 		},
-		"deleteUser": &graphql.Field{
+		"changePassword": &graphql.Field{
 			Type: graphql.Boolean,
 			Args: graphql.FieldConfigArgument{
-				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"currentPassword": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"newPassword":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				authUser, err := auth.GetUserFromContext(p.Context)
 				if err != nil {
 					return nil, err
 				}
-
-				if allowed, err := permService.CheckPermission(p.Context, authUser.UserID, "users", "delete"); !allowed || err != nil {
-					if err != nil {
-						return nil, fmt.Errorf("permission check failed: %v", err)
-					}
-					return nil, fmt.Errorf("permission denied")
+				if authUser.IsAPIKey() {
+					return nil, apperrors.ErrAPIKeyNotAllowed
+				}
+				if authUser.IsImpersonated() {
+					return nil, apperrors.ErrImpersonationBlocked
 				}
 
+				req := &dto.ChangePasswordRequest{
+					CurrentPassword: p.Args["currentPassword"].(string),
+					NewPassword:     p.Args["newPassword"].(string),
+				}
+				if err := userService.ChangePassword(p.Context, authUser.UserID, authUser.SessionID, req); err != nil {
+					return false, err
+				}
+				return true, nil
+			},
+		},
+		"createUser": &graphql.Field{
+			Type: userType,
+			Args: graphql.FieldConfigArgument{
+				"name":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"email":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"password": &graphql.ArgumentConfig{Type: graphql.String},
+				// Servis hesapları şifresizdir ve sadece API anahtarları ile kullanılır.
+				"serviceAccount": &graphql.ArgumentConfig{Type: graphql.Boolean},
+			},
+			Resolve: withPermission(permService, permission.UserAdd, func(p graphql.ResolveParams) (interface{}, error) {
+				req := &dto.CreateUserRequest{
+					Name:  p.Args["name"].(string),
+					Email: p.Args["email"].(string),
+				}
+				if password, ok := p.Args["password"].(string); ok {
+					req.Password = password
+				}
+				if serviceAccount, ok := p.Args["serviceAccount"].(bool); ok {
+					req.ServiceAccount = serviceAccount
+				}
+				return userService.CreateUser(p.Context, req)
+			}),
+		},
+		"updateUser": &graphql.Field{
+			Type: userType,
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"name":  &graphql.ArgumentConfig{Type: graphql.String},
+				"email": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: withPolicy(permService, permission.UserUpdate, argAttributes("id"), func(p graphql.ResolveParams) (interface{}, error) {
 				id := p.Args["id"].(int)
-				err = userService.DeleteUser(p.Context, id)
-				return err == nil, err
+				req := &dto.UpdateUserRequest{}
+				if name, ok := p.Args["name"].(string); ok {
+					req.Name = name
+				}
+				if email, ok := p.Args["email"].(string); ok {
+					req.Email = email
+				}
+				return userService.UpdateUser(p.Context, id, req)
+			}),
+		},
+		"deleteUser": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
 			},
+			Resolve: withPolicy(permService, permission.UserDelete, argAttributes("id"), func(p graphql.ResolveParams) (interface{}, error) {
+				id := p.Args["id"].(int)
+				err := userService.DeleteUser(p.Context, id)
+				return err == nil, err
+			}),
 		},
 	}
 }
+
+// withPermission, resolver'ı oturum ve yetki kontrolü ile sarar. Yetki şema oluşturulurken
+// doğrulanır; registry'de olmayan bir yetki kullanan resolver uygulamanın açılmasını engeller.
+func withPermission(permService service.IPermissionService, perm permission.Permission, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return withPolicy(permService, perm, nil, resolve)
+}
+
+// withPolicy, withPermission gibidir; ek olarak attrs ile argümanlardan çıkarılan kaynak
+// öznitelikleri politikalara iletilir (REST'teki middleware.ParamAttributes karşılığı).
+func withPolicy(permService service.IPermissionService, perm permission.Permission, attrs func(p graphql.ResolveParams) policy.Attributes, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	permission.MustBeDeclared(perm)
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		authUser, err := auth.GetUserFromContext(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := service.AuthorizeOptions{}
+		if attrs != nil {
+			opts.Resource = attrs(p)
+		}
+		decision, err := permService.Authorize(p.Context, authUser.UserID, perm, opts)
+		if err != nil {
+			return nil, fmt.Errorf("permission check failed: %v", err)
+		}
+		if !decision.Allowed {
+			return nil, fmt.Errorf("permission denied")
+		}
+
+		return resolve(p)
+	}
+}
+
+// argAttributes, resolver argümanlarını aynı adla kaynak özniteliği olarak verir
+// (örn. argAttributes("id") ile user(id) için resource.id).
+func argAttributes(names ...string) func(p graphql.ResolveParams) policy.Attributes {
+	return func(p graphql.ResolveParams) policy.Attributes {
+		attrs := policy.Attributes{}
+		for _, name := range names {
+			if value, ok := p.Args[name]; ok {
+				attrs[name] = value
+			}
+		}
+		return attrs
+	}
+}
@@ -6,15 +6,20 @@ import (
 
 	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
 	"ths-erp.com/internal/platform/web"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func (h *UserHandler) Setup2FARoutes(router fiber.Router) {
-	router.Post("/2fa/setup", h.Setup2FA)
-	router.Post("/2fa/enable", h.Enable2FA)
-	router.Post("/2fa/disable", h.Disable2FA)
+// Setup2FARoutes, 2FA uç noktalarını router'a ekler. guard, tüm uç noktalardan önce
+// çalıştırılır (örn. API anahtarlarını reddeden middleware).
+func (h *UserHandler) Setup2FARoutes(router fiber.Router, guard fiber.Handler) {
+	router.Post("/2fa/setup", guard, h.Setup2FA)
+	router.Post("/2fa/enable", guard, h.Enable2FA)
+	router.Post("/2fa/disable", guard, h.Disable2FA)
+	router.Get("/2fa/status", guard, h.Get2FAStatus)
+	router.Post("/2fa/recovery-codes", guard, h.RegenerateRecoveryCodes)
 }
 
 func (h *UserHandler) Setup2FA(c *fiber.Ctx) error {
@@ -28,7 +33,7 @@ func (h *UserHandler) Setup2FA(c *fiber.Ctx) error {
 
 	resp, err := h.userService.Setup2FA(ctx, user.UserID)
 	if err != nil {
-		return web.CustomError(c, fiber.StatusInternalServerError, "Could not setup 2FA")
+		return h.handleError(c, err)
 	}
 
 	return web.Success(c, fiber.StatusOK, resp, "2FA setup initiated")
@@ -50,7 +55,7 @@ func (h *UserHandler) Enable2FA(c *fiber.Ctx) error {
 
 	recoveryCodes, err := h.userService.Enable2FA(ctx, user.UserID, req.Code)
 	if err != nil {
-		return web.CustomError(c, fiber.StatusBadRequest, err.Error())
+		return h.handleError(c, err)
 	}
 
 	return web.Success(c, fiber.StatusOK, recoveryCodes, "2FA enabled successfully")
@@ -60,14 +65,63 @@ func (h *UserHandler) Disable2FA(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
 	defer cancel()
 
+	lang := c.Locals("lang").(string)
 	user, err := auth.GetUserFromContext(c.UserContext())
 	if err != nil {
 		return web.Unauthorized(c)
 	}
 
-	if err := h.userService.Disable2FA(ctx, user.UserID); err != nil {
-		return web.CustomError(c, fiber.StatusInternalServerError, "Could not disable 2FA")
+	var req dto.Disable2FARequest
+	if err := c.BodyParser(&req); err != nil || (req.Code == "" && req.Password == "") {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
 	}
 
-	return web.Success(c, fiber.StatusOK, nil, "2FA disabled successfully")
+	if err := h.userService.Disable2FA(ctx, user.UserID, &req); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "2fa_disabled_successfully"))
+}
+
+// Get2FAStatus, 2FA'nın açık olup olmadığını ve kalan kurtarma kodu sayısını döner.
+func (h *UserHandler) Get2FAStatus(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	user, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	status, err := h.userService.Get2FAStatus(ctx, user.UserID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, status)
+}
+
+// RegenerateRecoveryCodes, güncel bir TOTP kodu karşılığında yeni kurtarma kodları üretir.
+// Eski kodların tamamı geçersiz olur.
+func (h *UserHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	user, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	var req dto.RegenerateRecoveryCodesRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	recoveryCodes, err := h.userService.RegenerateRecoveryCodes(ctx, user.UserID, req.Code)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, recoveryCodes, i18n.Get(lang, "recovery_codes_regenerated"))
 }
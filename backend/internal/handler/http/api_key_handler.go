@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// APIKeyHandler, API anahtarlarının yönetimi ile ilgili HTTP isteklerini karşılar.
+type APIKeyHandler struct {
+	apiKeyService service.IAPIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.IAPIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *APIKeyHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrAPIKeyNotFound):
+		return web.NotFound(c, i18n.Get(lang, "api_key_not_found"))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return web.NotFound(c, i18n.Get(lang, "user_not_found"))
+	case errors.Is(err, apperrors.ErrValidation):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	case errors.Is(err, apperrors.ErrNoOrganization):
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	case errors.Is(err, apperrors.ErrNotMember):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "not_member"))
+	case errors.Is(err, apperrors.ErrNotServiceAccount):
+		return web.Forbidden(c, i18n.Get(lang, "api_key_service_account_only"))
+	case errors.Is(err, apperrors.ErrPermissionDelegation):
+		return web.Forbidden(c, i18n.Get(lang, "api_key_scope_not_held"))
+	default:
+		log.Printf("Unhandled error in APIKeyHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetMyKeys, oturum açmış kullanıcının API anahtarlarını listeler.
+func (h *APIKeyHandler) GetMyKeys(c *fiber.Ctx) error {
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+	return h.list(c, authUser.UserID)
+}
+
+// CreateMyKey, oturum açmış kullanıcı adına yeni bir API anahtarı oluşturur.
+func (h *APIKeyHandler) CreateMyKey(c *fiber.Ctx) error {
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+	return h.create(c, authUser.UserID, authUser.UserID)
+}
+
+// RevokeMyKey, oturum açmış kullanıcının API anahtarlarından birini iptal eder.
+func (h *APIKeyHandler) RevokeMyKey(c *fiber.Ctx) error {
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+	return h.revoke(c, authUser.UserID)
+}
+
+// GetUserKeys, bir kullanıcının veya servis hesabının API anahtarlarını yöneticiye listeler.
+func (h *APIKeyHandler) GetUserKeys(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(c.Locals("lang").(string), "invalid_request"))
+	}
+	return h.list(c, id)
+}
+
+// CreateUserKey, yöneticinin bir servis hesabı adına anahtar oluşturmasını sağlar. Diğer
+// kullanıcılar anahtarlarını kendileri /me/api-keys üzerinden oluşturur.
+func (h *APIKeyHandler) CreateUserKey(c *fiber.Ctx) error {
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(c.Locals("lang").(string), "invalid_request"))
+	}
+	return h.create(c, id, authUser.UserID)
+}
+
+// RevokeUserKey, yöneticinin bir kullanıcının anahtarını iptal etmesini sağlar.
+func (h *APIKeyHandler) RevokeUserKey(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(c.Locals("lang").(string), "invalid_request"))
+	}
+	return h.revoke(c, id)
+}
+
+func (h *APIKeyHandler) list(c *fiber.Ctx, ownerID int) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	keys, err := h.apiKeyService.ListKeys(ctx, ownerID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, keys, i18n.Get(lang, "api_keys_retrieved"))
+}
+
+func (h *APIKeyHandler) create(c *fiber.Ctx, ownerID, createdByID int) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	key, err := h.apiKeyService.CreateKey(ctx, ownerID, createdByID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusCreated, key, i18n.Get(lang, "api_key_created"))
+}
+
+func (h *APIKeyHandler) revoke(c *fiber.Ctx, ownerID int) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	keyID, err := c.ParamsInt("keyId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.apiKeyService.RevokeKey(ctx, ownerID, keyID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "api_key_revoked"))
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// auditExportTimeout, CSV dışa aktarımının en uzun süresidir. Dışa aktarım tüm kayıtları
+// okuduğu için diğer isteklerin süre sınırından (handlerTimeout) uzundur.
+const auditExportTimeout = time.Minute
+
+// AuditHandler, aktif organizasyonun denetim kayıtlarını sorgulama ve dışa aktarma isteklerini karşılar.
+type AuditHandler struct {
+	auditService service.IAuditService
+}
+
+func NewAuditHandler(auditService service.IAuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *AuditHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrNoOrganization):
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	default:
+		log.Printf("Unhandled error in AuditHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetEvents, filtreye uyan denetim kayıtlarını sayfalı olarak listeler. Varsayılan sıralama
+// yeniden eskiyedir.
+func (h *AuditHandler) GetEvents(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_audit_filter"))
+	}
+	pagination := &domain.Pagination{
+		Page:      c.QueryInt("page", 1),
+		PageSize:  c.QueryInt("pageSize", 20),
+		SortBy:    c.Query("sortBy", "created_at"),
+		SortOrder: c.Query("sortOrder", "desc"),
+	}
+	if pagination.PageSize > 100 {
+		pagination.PageSize = 100
+	}
+
+	events, pagination, err := h.auditService.ListEvents(ctx, filter, pagination)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Paginated(c, events, pagination)
+}
+
+// Export, filtreye uyan tüm denetim kayıtlarını CSV dosyası olarak indirir.
+func (h *AuditHandler) Export(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), auditExportTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_audit_filter"))
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Attachment("audit-events-" + time.Now().UTC().Format("20060102-150405") + ".csv")
+	if err := h.auditService.ExportCSV(ctx, filter, c.Response().BodyWriter()); err != nil {
+		// Yarım kalan dosya gönderilmez; hata JSON olarak döner.
+		c.Response().ResetBody()
+		c.Response().Header.Del(fiber.HeaderContentDisposition)
+		return h.handleError(c, err)
+	}
+	return nil
+}
+
+// parseAuditFilter, sorgu parametrelerini denetim kaydı filtresine çevirir. Zaman aralığı
+// RFC 3339 biçiminde verilir; "from" dahil, "to" hariçtir.
+func parseAuditFilter(c *fiber.Ctx) (domain.AuditEventFilter, error) {
+	filter := domain.AuditEventFilter{
+		ActorID:    c.QueryInt("actorId"),
+		EntityType: c.Query("entityType"),
+		EntityID:   c.Query("entityId"),
+		Action:     c.Query("action"),
+		RequestID:  c.Query("requestId"),
+	}
+	for key, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, err
+		}
+		*target = &t
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, apperrors.ErrValidation
+	}
+	return filter, nil
+}
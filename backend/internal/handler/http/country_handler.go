@@ -68,7 +68,7 @@ func (h *CountryHandler) Create(c *fiber.Ctx) error {
 		return web.ValidationError(c, err)
 	}
 
-	if err := h.countryService.Create(c.Context(), req); err != nil {
+	if err := h.countryService.Create(c.UserContext(), req); err != nil {
 		return web.CustomError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -87,7 +87,7 @@ func (h *CountryHandler) Update(c *fiber.Ctx) error {
 		return web.ValidationError(c, err)
 	}
 
-	if err := h.countryService.Update(c.Context(), code, req); err != nil {
+	if err := h.countryService.Update(c.UserContext(), code, req); err != nil {
 		return web.CustomError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -97,7 +97,7 @@ func (h *CountryHandler) Update(c *fiber.Ctx) error {
 // Delete handles the DELETE /api/v1/countries/:code request.
 func (h *CountryHandler) Delete(c *fiber.Ctx) error {
 	code := c.Params("code")
-	if err := h.countryService.Delete(c.Context(), code); err != nil {
+	if err := h.countryService.Delete(c.UserContext(), code); err != nil {
 		return web.CustomError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+)
+
+// VerifyEmail, e-postadaki token ile adresi doğrular. E-posta değişikliğinde yeni adres
+// ancak bu adımdan sonra hesabın adresi olur.
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.VerifyEmailRequest
+
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.VerifyEmail(ctx, req.Token); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "email_verified"))
+}
+
+// ResendVerificationEmail, doğrulama e-postasını yeniden gönderir. Hesapların tespit
+// edilememesi için adres kayıtlı olsun ya da olmasın aynı yanıt döner.
+func (h *UserHandler) ResendVerificationEmail(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.ResendVerificationRequest
+
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.ResendVerificationEmail(ctx, req.Email); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "verification_email_sent"))
+}
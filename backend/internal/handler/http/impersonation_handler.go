@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// ImpersonationHandler, yöneticilerin kullanıcı adına işlem yapması ile ilgili HTTP isteklerini karşılar.
+type ImpersonationHandler struct {
+	impersonationService service.IImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService service.IImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *ImpersonationHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return web.NotFound(c, i18n.Get(lang, "user_not_found"))
+	case errors.Is(err, apperrors.ErrValidation):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "impersonation_reason_required"))
+	case errors.Is(err, apperrors.ErrImpersonationDenied):
+		return web.Forbidden(c, i18n.Get(lang, "impersonation_denied"))
+	case errors.Is(err, apperrors.ErrNotImpersonating):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "not_impersonating"))
+	default:
+		log.Printf("Unhandled error in ImpersonationHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// Start, yöneticinin bir kullanıcı adına işlem yapmasını sağlayan kısa ömürlü token'ı üretir.
+func (h *ImpersonationHandler) Start(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	var req dto.StartImpersonationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	resp, err := h.impersonationService.Start(ctx, authUser, id, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusCreated, resp, i18n.Get(lang, "impersonation_started"))
+}
+
+// End, impersonation token'ı ile çağrılır ve impersonation'ı sonlandırır.
+func (h *ImpersonationHandler) End(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	if err := h.impersonationService.End(ctx, authUser); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "impersonation_ended"))
+}
+
+// GetUserImpersonations, bir kullanıcının adına yapılan ve kullanıcının yaptığı impersonation'ları listeler.
+func (h *ImpersonationHandler) GetUserImpersonations(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	sessions, err := h.impersonationService.ListForUser(ctx, id)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, sessions, i18n.Get(lang, "impersonations_retrieved"))
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/auth"
+)
+
+// JWKS, access token'ları doğrulamak için kullanılan açık anahtarları yayınlar.
+// Diğer servisler bu endpoint sayesinde imzalama anahtarına ihtiyaç duymadan token doğrulayabilir.
+// Yanıt, standart JWKS formatında olması gerektiği için web.Response ile sarmalanmaz.
+func JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(auth.GetKeySet().JWKS())
+}
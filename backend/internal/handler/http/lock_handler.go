@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+)
+
+// GetLockStatus, bir kullanıcının hesap kilidi durumunu ve son login denemelerini döner.
+func (h *UserHandler) GetLockStatus(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	status, err := h.userService.GetLockStatus(ctx, id)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, status, i18n.Get(lang, "lock_status_retrieved"))
+}
+
+// Unlock, kilitlenmiş bir hesabın kilidini süresi dolmadan kaldırır.
+func (h *UserHandler) Unlock(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.UnlockUser(ctx, id); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "account_unlocked"))
+}
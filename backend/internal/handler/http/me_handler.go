@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+)
+
+// GetMe, oturum açmış kullanıcının kendi profilini döner.
+func (h *UserHandler) GetMe(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	user, err := h.userService.GetUser(ctx, authUser.UserID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, user, i18n.Get(lang, "profile_retrieved"))
+}
+
+// UpdateMe, oturum açmış kullanıcının kendi profilini günceller. Yeni e-posta adresi
+// doğrulanana kadar mevcut adres kullanılmaya devam eder.
+func (h *UserHandler) UpdateMe(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	user, err := h.userService.UpdateUser(ctx, authUser.UserID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, user, i18n.Get(lang, "profile_updated"))
+}
+
+// ChangePassword, mevcut şifreyi doğrulayarak yeni şifreyi belirler. İstek yapılan
+// oturum açık kalır, diğer tüm oturumlar sonlandırılır.
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil || req.CurrentPassword == "" || req.NewPassword == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.ChangePassword(ctx, authUser.UserID, authUser.SessionID, &req); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "password_changed"))
+}
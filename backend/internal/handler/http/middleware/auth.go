@@ -4,40 +4,66 @@ import (
 	"strings"
 
 	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/platform/i18n"
 	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return web.Unauthorized(c, "Authorization header is missing")
-	}
+// AuthMiddleware, Bearer token'ı veya API anahtarını ("X-API-Key" ya da "Authorization: ApiKey ...")
+// doğrular ve kimliği doğrulanmış kullanıcıyı hem fiber Locals'a hem de UserContext'e ekler.
+// İptal edilmiş token'lar ve anahtarlar reddedilir.
+func AuthMiddleware(tokenService service.ITokenService, apiKeyService service.IAPIKeyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
-		return web.Unauthorized(c, "Invalid authorization header format")
-	}
+		if apiKey := auth.APIKeyFromHeaders(c.Get("X-API-Key"), authHeader); apiKey != "" {
+			user, err := apiKeyService.Authenticate(c.UserContext(), apiKey)
+			if err != nil {
+				return web.Unauthorized(c, "Invalid, expired or revoked API key")
+			}
 
-	token, err := jwt.ParseWithClaims(tokenString, &auth.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return auth.GetJWTSecret(), nil
-	})
+			c.Locals("user", user)
+			c.SetUserContext(auth.WithUser(c.UserContext(), user))
+			return c.Next()
+		}
 
-	if err != nil || !token.Valid {
-		return web.Unauthorized(c, "Invalid or expired token")
-	}
+		if authHeader == "" {
+			return web.Unauthorized(c, "Authorization header is missing")
+		}
 
-	claims, ok := token.Claims.(*auth.JWTClaims)
-	if !ok {
-		return web.Unauthorized(c, "Invalid token claims")
-	}
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if tokenString == authHeader {
+			return web.Unauthorized(c, "Invalid authorization header format")
+		}
+
+		user, err := tokenService.ValidateAccessToken(c.UserContext(), tokenString)
+		if err != nil {
+			return web.Unauthorized(c, "Invalid or expired token")
+		}
 
-	c.Locals("user", &auth.AuthUser{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-	})
+		c.Locals("user", user)
+		c.SetUserContext(auth.WithUser(c.UserContext(), user))
 
-	return c.Next()
+		return c.Next()
+	}
+}
+
+// InteractiveOnly, API anahtarı veya impersonation ile yapılamayacak işlemleri (şifre, 2FA,
+// oturum ve anahtar yönetimi) korur. Sızdırılmış dar kapsamlı bir anahtarın kendine yeni anahtar
+// üretmesini, kullanıcı adına işlem yapan bir yöneticinin de hesabın kimlik bilgilerini değiştirmesini engeller.
+func InteractiveOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*auth.AuthUser)
+		if ok && user != nil && user.IsAPIKey() {
+			lang, _ := c.Locals("lang").(string)
+			return web.Forbidden(c, i18n.Get(lang, "api_key_not_allowed"))
+		}
+		if ok && user != nil && user.IsImpersonated() {
+			lang, _ := c.Locals("lang").(string)
+			return web.Forbidden(c, i18n.Get(lang, "impersonation_not_allowed"))
+		}
+		return c.Next()
+	}
 }
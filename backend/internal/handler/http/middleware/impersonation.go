@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rs/zerolog"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/service"
+)
+
+// ImpersonationAudit, impersonation token'ı ile yapılan istekleri işaretler: isteğin log
+// satırlarına yöneticinin kimliği eklenir, yanıta "X-Impersonated-By" başlığı konur ve
+// istek, sonucu ile birlikte impersonation denetim kaydına yazılır. AuthMiddleware'den sonra kullanılmalıdır.
+func ImpersonationAudit(impersonationService service.IImpersonationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(*auth.AuthUser)
+		if !ok || user == nil || !user.IsImpersonated() {
+			return c.Next()
+		}
+
+		// AttachLogger'ın isteğe özel logger'ı yerinde güncellenir; böylece "Request finished" satırı da
+		// işaretlenir. Global logger'a dokunulmaması için GetLogger'ın fallback'i kullanılmaz.
+		if reqLogger, ok := c.UserContext().Value(loggerKey).(*zerolog.Logger); ok && reqLogger != nil {
+			reqLogger.UpdateContext(func(l zerolog.Context) zerolog.Context {
+				return l.Int("user_id", user.UserID).
+					Int("impersonator_id", user.ImpersonatorID).
+					Int("impersonation_id", user.ImpersonationID)
+			})
+		}
+		c.Set("X-Impersonated-By", user.ImpersonatorEmail)
+
+		// Fiber'ın döndürdüğü string'ler istek tamponuna bağlıdır; kopyalanarak saklanır.
+		method, path := utils.CopyString(c.Method()), utils.CopyString(c.Path())
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		}
+		impersonationService.RecordRequest(c.UserContext(), user, method, path, status)
+		return err
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/platform/logger" // Kendi modül adınızla
 )
 
@@ -30,6 +31,12 @@ func AttachLogger(c *fiber.Ctx) error {
 	// Logger'ı context'e ekle
 	// c.UserContext() zaten bir context.Context döndürür.
 	ctx := context.WithValue(c.UserContext(), loggerKey, &reqLogger)
+	// İstemci bilgileri servis katmanında (login denemeleri, oturumlar) kullanılır.
+	ctx = auth.WithClientInfo(ctx, auth.ClientInfo{
+		RequestID: reqID,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
 	c.SetUserContext(ctx)
 
 	start := time.Now()
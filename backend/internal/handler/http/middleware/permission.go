@@ -4,14 +4,38 @@ import (
 	"log"
 
 	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/permission"
 	"ths-erp.com/internal/platform/i18n"
 	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/policy"
 	"ths-erp.com/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func PermissionMiddleware(permService service.IPermissionService, resource, action string) fiber.Handler {
+// ResourceAttributes, istekten politikaların değerlendireceği kaynak özniteliklerini çıkarır.
+type ResourceAttributes func(c *fiber.Ctx) policy.Attributes
+
+// ParamAttributes, route parametrelerini aynı adla kaynak özniteliği olarak verir
+// (örn. ParamAttributes("id") ile /users/:id için resource.id).
+func ParamAttributes(names ...string) ResourceAttributes {
+	return func(c *fiber.Ctx) policy.Attributes {
+		attrs := policy.Attributes{}
+		for _, name := range names {
+			if value := c.Params(name); value != "" {
+				attrs[name] = value
+			}
+		}
+		return attrs
+	}
+}
+
+// PermissionMiddleware, kullanıcının verilen yetkiye sahip olup olmadığını rol yetkileri ve
+// politikalar ile kontrol eder. attrs verilirse istekten çıkarılan kaynak öznitelikleri politikalara
+// iletilir. Yetki route tanımlanırken doğrulanır; registry'de olmayan bir yetki uygulamanın açılmasını engeller.
+func PermissionMiddleware(permService service.IPermissionService, perm permission.Permission, attrs ...ResourceAttributes) fiber.Handler {
+	permission.MustBeDeclared(perm)
+
 	return func(c *fiber.Ctx) error {
 		lang := c.Query("lang", "tr")
 
@@ -20,13 +44,25 @@ func PermissionMiddleware(permService service.IPermissionService, resource, acti
 			return web.Unauthorized(c)
 		}
 
-		allowed, err := permService.CheckPermission(c.Context(), user.UserID, resource, action)
+		// API anahtarları, sahibinin yetkilerine ek olarak kendi kapsamlarıyla sınırlıdır.
+		if !user.AllowsScope(perm.Resource, perm.Action) {
+			return web.Forbidden(c, i18n.Get(lang, "api_key_scope_denied"))
+		}
+
+		opts := service.AuthorizeOptions{Resource: policy.Attributes{}}
+		for _, fn := range attrs {
+			for k, v := range fn(c) {
+				opts.Resource[k] = v
+			}
+		}
+
+		decision, err := permService.Authorize(c.UserContext(), user.UserID, perm, opts)
 		if err != nil {
 			log.Printf("Permission check error: %v", err)
 			return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "database_error"))
 		}
 
-		if !allowed {
+		if !decision.Allowed {
 			return web.Forbidden(c, i18n.Get(lang, "permission_denied"))
 		}
 
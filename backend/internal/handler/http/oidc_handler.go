@@ -0,0 +1,125 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// oidcHandlerTimeout, callback'te IdP'nin token ve JWKS endpoint'lerine yapılan
+// istekleri de kapsadığı için handlerTimeout'tan uzundur.
+const oidcHandlerTimeout = 15 * time.Second
+
+// oidcStateCookie, giriş akışını başlatan tarayıcıya verilen ve state'in özetini taşıyan cookie'dir.
+const oidcStateCookie = "oidc_state"
+
+// OIDCHandler, harici kimlik sağlayıcılar ile giriş akışının HTTP isteklerini karşılar.
+type OIDCHandler struct {
+	oidcService  service.IOIDCService
+	tokenService service.ITokenService
+}
+
+func NewOIDCHandler(oidcService service.IOIDCService, tokenService service.ITokenService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService, tokenService: tokenService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *OIDCHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrOIDCProviderNotFound):
+		return web.NotFound(c, i18n.Get(lang, "oidc_provider_not_found"))
+	case errors.Is(err, apperrors.ErrInvalidOIDCState):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "oidc_invalid_state"))
+	case errors.Is(err, apperrors.ErrOIDCLoginFailed):
+		return web.Unauthorized(c, i18n.Get(lang, "oidc_login_failed"))
+	case errors.Is(err, apperrors.ErrOIDCAccountNotLinked):
+		return web.CustomError(c, fiber.StatusForbidden, i18n.Get(lang, "oidc_account_not_linked"))
+	case errors.Is(err, apperrors.ErrAccountLocked):
+		return web.CustomError(c, fiber.StatusTooManyRequests, i18n.Get(lang, "account_locked"))
+	default:
+		log.Printf("Unhandled error in OIDCHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetProviders, yapılandırılmış kimlik sağlayıcılarını listeler.
+func (h *OIDCHandler) GetProviders(c *fiber.Ctx) error {
+	lang := c.Locals("lang").(string)
+	return web.Success(c, fiber.StatusOK, h.oidcService.ListProviders(), i18n.Get(lang, "oidc_providers_retrieved"))
+}
+
+// Login, kullanıcıyı PKCE parametreleri ile sağlayıcının giriş sayfasına yönlendirir.
+func (h *OIDCHandler) Login(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), oidcHandlerTimeout)
+	defer cancel()
+
+	authURL, stateHash, err := h.oidcService.BeginLogin(ctx, c.Params("provider"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	h.setStateCookie(c, stateHash, time.Now().Add(service.OIDCStateTTL))
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// setStateCookie, state cookie'sini sadece sağlayıcının callback adresine gönderilecek şekilde yazar.
+// IdP'den dönüş siteler arası bir yönlendirme olduğu için SameSite=Lax kullanılır; Strict cookie'yi
+// göndermez. Boş değer ve geçmiş bir zaman cookie'yi siler.
+func (h *OIDCHandler) setStateCookie(c *fiber.Ctx, value string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     strings.TrimSuffix(c.Path(), "/login") + "/callback",
+		Expires:  expires,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+// Callback, sağlayıcının döndürdüğü code'u doğrular ve normal login ile aynı şekilde
+// token çifti veya 2FA challenge'ı döner.
+func (h *OIDCHandler) Callback(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), oidcHandlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	provider := c.Params("provider")
+	stateHash := c.Cookies(oidcStateCookie)
+	h.setStateCookie(c, "", time.Unix(0, 0))
+
+	// Kullanıcı IdP'de girişi reddettiyse veya IdP hata döndüyse code gelmez.
+	if idpErr := c.Query("error"); idpErr != "" {
+		log.Printf("WARN: OIDC provider %s returned error %q: %s", provider, idpErr, c.Query("error_description"))
+		return web.Unauthorized(c, i18n.Get(lang, "oidc_login_failed"))
+	}
+
+	user, err := h.oidcService.CompleteLogin(ctx, provider, c.Query("state"), stateHash, c.Query("code"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := h.tokenService.IssueChallenge(ctx, user)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		return web.Success(c, fiber.StatusOK, challenge, i18n.Get(lang, "2fa_required"))
+	}
+
+	loginResponse, err := h.tokenService.IssueTokens(ctx, user)
+	if err != nil {
+		log.Printf("Error issuing tokens for user %d: %v", user.ID, err)
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, loginResponse, i18n.Get(lang, "login_successful"))
+}
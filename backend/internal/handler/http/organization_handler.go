@@ -0,0 +1,241 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// OrganizationHandler, organizasyonlar (kiracılar), üyelikler ve aktif organizasyonun
+// değiştirilmesi ile ilgili HTTP isteklerini karşılar.
+type OrganizationHandler struct {
+	organizationService service.IOrganizationService
+	tokenService        service.ITokenService
+}
+
+func NewOrganizationHandler(organizationService service.IOrganizationService, tokenService service.ITokenService) *OrganizationHandler {
+	return &OrganizationHandler{organizationService: organizationService, tokenService: tokenService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *OrganizationHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrOrganizationNotFound):
+		return web.NotFound(c, i18n.Get(lang, "organization_not_found"))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return web.NotFound(c, i18n.Get(lang, "user_not_found"))
+	case errors.Is(err, apperrors.ErrOrganizationExists):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "organization_exists"))
+	case errors.Is(err, apperrors.ErrAlreadyMember):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "already_member"))
+	case errors.Is(err, apperrors.ErrNotMember):
+		return web.NotFound(c, i18n.Get(lang, "not_member"))
+	case errors.Is(err, apperrors.ErrInvitationNotFound):
+		return web.NotFound(c, i18n.Get(lang, "invitation_not_found"))
+	case errors.Is(err, apperrors.ErrNoOrganization):
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	case errors.Is(err, apperrors.ErrValidation):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	case errors.Is(err, apperrors.ErrPermissionDelegation):
+		return web.Forbidden(c, i18n.Get(lang, "permission_delegation_denied"))
+	case errors.Is(err, apperrors.ErrAPIKeyNotAllowed):
+		return web.Forbidden(c, i18n.Get(lang, "api_key_not_allowed"))
+	case errors.Is(err, apperrors.ErrImpersonationBlocked):
+		return web.Forbidden(c, i18n.Get(lang, "impersonation_not_allowed"))
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return web.Unauthorized(c)
+	default:
+		log.Printf("Unhandled error in OrganizationHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetMine, oturum açmış kullanıcının üyesi olduğu organizasyonları listeler.
+func (h *OrganizationHandler) GetMine(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	orgs, err := h.organizationService.ListMine(ctx)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, orgs, i18n.Get(lang, "organizations_retrieved"))
+}
+
+// Switch, oturumun aktif organizasyonunu değiştirir ve yeni organizasyonu taşıyan bir access token döner.
+func (h *OrganizationHandler) Switch(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	var req dto.SwitchOrganizationRequest
+	if err := c.BodyParser(&req); err != nil || req.OrganizationID <= 0 {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	resp, err := h.tokenService.SwitchOrganization(ctx, authUser, req.OrganizationID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, resp, i18n.Get(lang, "organization_switched"))
+}
+
+// Create, yeni bir organizasyon oluşturur; oluşturan kullanıcı organizasyonun yöneticisi olur.
+func (h *OrganizationHandler) Create(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.CreateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	org, err := h.organizationService.Create(ctx, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusCreated, org, i18n.Get(lang, "organization_created"))
+}
+
+// UpdateCurrent, aktif organizasyonun adını değiştirir.
+func (h *OrganizationHandler) UpdateCurrent(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.UpdateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	org, err := h.organizationService.UpdateCurrent(ctx, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, org, i18n.Get(lang, "organization_updated"))
+}
+
+// InviteMember, mevcut bir kullanıcıyı aktif organizasyona davet eder. Kullanıcı daveti kabul
+// ettiğinde organizasyonun üyesi olur.
+func (h *OrganizationHandler) InviteMember(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.InviteOrganizationMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.organizationService.InviteMember(ctx, &req); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusCreated, nil, i18n.Get(lang, "member_invited"))
+}
+
+// CancelInvitation, aktif organizasyonun bir kullanıcıya gönderdiği daveti geri alır.
+func (h *OrganizationHandler) CancelInvitation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	userID, err := c.ParamsInt("userId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.organizationService.CancelInvitation(ctx, userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "invitation_canceled"))
+}
+
+// GetMyInvitations, oturum açmış kullanıcının bekleyen organizasyon davetlerini listeler.
+func (h *OrganizationHandler) GetMyInvitations(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	invitations, err := h.organizationService.ListMyInvitations(ctx)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, invitations, i18n.Get(lang, "invitations_retrieved"))
+}
+
+// AcceptInvitation, oturum açmış kullanıcının bir organizasyon davetini kabul eder.
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	organizationID, err := c.ParamsInt("organizationId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.organizationService.AcceptInvitation(ctx, organizationID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "invitation_accepted"))
+}
+
+// DeclineInvitation, oturum açmış kullanıcının bir organizasyon davetini reddeder.
+func (h *OrganizationHandler) DeclineInvitation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	organizationID, err := c.ParamsInt("organizationId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.organizationService.DeclineInvitation(ctx, organizationID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "invitation_declined"))
+}
+
+// RemoveMember, kullanıcıyı aktif organizasyondan çıkarır.
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	userID, err := c.ParamsInt("userId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.organizationService.RemoveMember(ctx, userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "member_removed"))
+}
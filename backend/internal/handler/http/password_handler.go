@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+)
+
+// ForgotPassword, şifre sıfırlama e-postası gönderilmesini ister. Hesapların tespit
+// edilememesi için e-posta adresi kayıtlı olsun ya da olmasın aynı yanıt döner.
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.ForgotPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.ForgotPassword(ctx, req.Email); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "password_reset_requested"))
+}
+
+// ResetPassword, e-postadaki token ile yeni şifreyi belirler.
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.ResetPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil || req.Token == "" || req.Password == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.userService.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "password_reset_successful"))
+}
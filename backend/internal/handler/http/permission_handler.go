@@ -0,0 +1,233 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// PermissionHandler, yetki registry'si, roller ve kullanıcı yetkileri ile ilgili HTTP isteklerini karşılar.
+type PermissionHandler struct {
+	permService service.IPermissionService
+}
+
+func NewPermissionHandler(permService service.IPermissionService) *PermissionHandler {
+	return &PermissionHandler{permService: permService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *PermissionHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return web.NotFound(c, i18n.Get(lang, "permission_assignment_not_found"))
+	case errors.Is(err, apperrors.ErrNoOrganization):
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	case errors.Is(err, apperrors.ErrRoleNotFound):
+		return web.NotFound(c, i18n.Get(lang, "role_not_found"))
+	case errors.Is(err, apperrors.ErrRoleExists):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "role_exists"))
+	case errors.Is(err, apperrors.ErrValidation):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "role_invalid"))
+	case errors.Is(err, apperrors.ErrInvalidPermission):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_permission"))
+	case errors.Is(err, apperrors.ErrSystemRole):
+		return web.Forbidden(c, i18n.Get(lang, "system_role_immutable"))
+	case errors.Is(err, apperrors.ErrRoleHasChildren):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "role_has_children"))
+	case errors.Is(err, apperrors.ErrPermissionDelegation):
+		return web.Forbidden(c, i18n.Get(lang, "permission_delegation_denied"))
+	case errors.Is(err, apperrors.ErrAPIKeyNotAllowed):
+		return web.Forbidden(c, i18n.Get(lang, "api_key_not_allowed"))
+	case errors.Is(err, apperrors.ErrImpersonationBlocked):
+		return web.Forbidden(c, i18n.Get(lang, "impersonation_not_allowed"))
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return web.Unauthorized(c)
+	default:
+		log.Printf("Unhandled error in PermissionHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetAll, tanımlı tüm yetkileri listeler. Yönetim arayüzleri rol ve API anahtarı
+// formlarını bu liste ile doldurur.
+func (h *PermissionHandler) GetAll(c *fiber.Ctx) error {
+	lang := c.Locals("lang").(string)
+	return web.Success(c, fiber.StatusOK, h.permService.ListPermissions(), i18n.Get(lang, "permissions_retrieved"))
+}
+
+// GetMine, oturumdaki kullanıcının yetkilerini döner. Arayüz menü öğelerini bu listeye göre gizler.
+func (h *PermissionHandler) GetMine(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	resp, err := h.permService.GetMyPermissions(ctx, authUser)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, resp, i18n.Get(lang, "effective_permissions_retrieved"))
+}
+
+// GetUserAccess, kullanıcıya atanmış rolleri ve doğrudan verilmiş yetkileri listeler.
+func (h *PermissionHandler) GetUserAccess(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	resp, err := h.permService.GetUserAccess(ctx, id)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, resp, i18n.Get(lang, "user_permissions_retrieved"))
+}
+
+// GetUserEffective, kullanıcının rolleri, kalıtım ve doğrudan yetkiler sonucunda sahip olduğu yetkileri döner.
+func (h *PermissionHandler) GetUserEffective(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	resp, err := h.permService.GetEffectivePermissions(ctx, id)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, resp, i18n.Get(lang, "effective_permissions_retrieved"))
+}
+
+// GetUserHistory, kullanıcının yetkilerinde yapılan ve kullanıcının yaptığı değişiklikleri listeler.
+func (h *PermissionHandler) GetUserHistory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	entries, err := h.permService.ListAuditLog(ctx, id)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, entries, i18n.Get(lang, "permission_history_retrieved"))
+}
+
+// AssignRole, kullanıcıya rol atar.
+func (h *PermissionHandler) AssignRole(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.BodyParser(&req); err != nil || req.RoleID <= 0 {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.permService.AssignRole(ctx, id, req.RoleID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "role_assigned"))
+}
+
+// UnassignRole, kullanıcıdan rolü kaldırır.
+func (h *PermissionHandler) UnassignRole(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+	roleID, err := c.ParamsInt("roleId")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.permService.UnassignRole(ctx, id, roleID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "role_unassigned"))
+}
+
+// GrantPermission, kullanıcıya doğrudan yetki verir.
+func (h *PermissionHandler) GrantPermission(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	var req dto.GrantPermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.permService.GrantPermission(ctx, id, req.Permission); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "permission_granted"))
+}
+
+// RevokePermission, kullanıcıya doğrudan verilen yetkiyi geri alır. Yetki path'te
+// URL-encoded olarak gönderilebilir (örn. "user%3Aselect").
+func (h *PermissionHandler) RevokePermission(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+	perm, err := url.PathUnescape(c.Params("permission"))
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.permService.RevokePermission(ctx, id, perm); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "permission_revoked"))
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+	"ths-erp.com/internal/service"
+)
+
+// PolicyHandler, yetkilendirme politikalarının yönetimi ve yetki kararlarının açıklanması ile
+// ilgili HTTP isteklerini karşılar.
+type PolicyHandler struct {
+	policyService service.IPolicyService
+}
+
+func NewPolicyHandler(policyService service.IPolicyService) *PolicyHandler {
+	return &PolicyHandler{policyService: policyService}
+}
+
+// handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
+func (h *PolicyHandler) handleError(c *fiber.Ctx, err error) error {
+	lang := c.Locals("lang").(string)
+	switch {
+	case errors.Is(err, apperrors.ErrPolicyNotFound):
+		return web.NotFound(c, i18n.Get(lang, "policy_not_found"))
+	case errors.Is(err, apperrors.ErrNotFound):
+		return web.NotFound(c, i18n.Get(lang, "user_not_found"))
+	case errors.Is(err, apperrors.ErrPolicyExists):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "policy_exists"))
+	case errors.Is(err, apperrors.ErrInvalidPolicy):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "policy_invalid"))
+	case errors.Is(err, apperrors.ErrInvalidPermission):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_permission"))
+	case errors.Is(err, apperrors.ErrPermissionDelegation):
+		return web.Forbidden(c, i18n.Get(lang, "permission_delegation_denied"))
+	case errors.Is(err, apperrors.ErrAPIKeyNotAllowed):
+		return web.Forbidden(c, i18n.Get(lang, "api_key_not_allowed"))
+	case errors.Is(err, apperrors.ErrImpersonationBlocked):
+		return web.Forbidden(c, i18n.Get(lang, "impersonation_not_allowed"))
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return web.Unauthorized(c)
+	default:
+		log.Printf("Unhandled error in PolicyHandler: %v", err)
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
+	}
+}
+
+// GetPolicies, dosya ve veritabanı politikalarını listeler.
+func (h *PolicyHandler) GetPolicies(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	policies, err := h.policyService.ListPolicies(ctx)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, policies, i18n.Get(lang, "policies_retrieved"))
+}
+
+// CreatePolicy, veritabanına yeni bir politika ekler ve tüm instance'larda yükler.
+func (h *PolicyHandler) CreatePolicy(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	p, err := h.policyService.CreatePolicy(ctx, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusCreated, p, i18n.Get(lang, "policy_created"))
+}
+
+// UpdatePolicy, veritabanı politikasını günceller.
+func (h *PolicyHandler) UpdatePolicy(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	var req dto.PolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	p, err := h.policyService.UpdatePolicy(ctx, id, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, p, i18n.Get(lang, "policy_updated"))
+}
+
+// DeletePolicy, veritabanı politikasını siler.
+func (h *PolicyHandler) DeletePolicy(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.policyService.DeletePolicy(ctx, id); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "policy_deleted"))
+}
+
+// Reload, politikaları dosyalardan ve veritabanından yeniden yükler (örn. politika dosyaları
+// değiştirildikten sonra).
+func (h *PolicyHandler) Reload(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	if err := h.policyService.Reload(ctx); err != nil {
+		log.Printf("Policy reload error: %v", err)
+		return web.CustomError(c, fiber.StatusUnprocessableEntity, i18n.Get(lang, "policy_reload_failed"))
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "policies_reloaded"))
+}
+
+// Explain, bir kullanıcının bir yetki için alacağı kararı gerekçesi ile döner. İstekteki
+// politikalar kaydedilmez; sadece bu değerlendirmede uygulanır (dry-run).
+func (h *PolicyHandler) Explain(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.ExplainPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	decision, err := h.policyService.Explain(ctx, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, decision, i18n.Get(lang, "policy_explained"))
+}
@@ -2,9 +2,11 @@ package http
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/apperrors"
 	"ths-erp.com/internal/platform/i18n"
 	"ths-erp.com/internal/platform/web"
 	"ths-erp.com/internal/service"
@@ -32,6 +34,9 @@ func (h *ReportHandler) RequestReport(c *fiber.Ctx) error {
 
 	reportType := "monthly_user_registrations" // Bu normalde request'ten gelir
 	report, err := h.reportService.RequestReport(ctx, reportType, reqBody)
+	if errors.Is(err, apperrors.ErrNoOrganization) {
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	}
 	if err != nil {
 		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "database_error"))
 	}
@@ -57,3 +62,21 @@ func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
 
 	return web.Success(c, fiber.StatusOK, report)
 }
+
+// GetReports, kullanıcının görebileceği raporları listeler. report:read_all yetkisi olanlar tüm raporları görür.
+func (h *ReportHandler) GetReports(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 3*time.Second)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+
+	reports, err := h.reportService.ListReports(ctx)
+	if errors.Is(err, apperrors.ErrNoOrganization) {
+		return web.Forbidden(c, i18n.Get(lang, "organization_required"))
+	}
+	if err != nil {
+		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "database_error"))
+	}
+
+	return web.Success(c, fiber.StatusOK, reports, i18n.Get(lang, "reports_retrieved"))
+}
@@ -7,58 +7,147 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/gorm"
 	"ths-erp.com/internal/handler/http/middleware"
+	"ths-erp.com/internal/permission"
 	"ths-erp.com/internal/platform/cache"
 	"ths-erp.com/internal/platform/queue"
 	"ths-erp.com/internal/service"
 )
 
-func SetupRoutes(app *fiber.App, db *gorm.DB, permService service.IPermissionService, queueClient *queue.RabbitMQClient, redisClient *redis.Client) {
+func SetupRoutes(app *fiber.App, db *gorm.DB, userService service.IUserService, permService service.IPermissionService, policyService service.IPolicyService, organizationService service.IOrganizationService, tokenService service.ITokenService, apiKeyService service.IAPIKeyService, oidcService service.IOIDCService, impersonationService service.IImpersonationService, queueClient *queue.RabbitMQClient, redisClient *redis.Client) {
 	uowFactory := service.NewUnitOfWorkFactory(db)
 	appCache := cache.NewRedisCache(redisClient)
 
 	// Initialize services
-	userService := service.NewUserService(uowFactory, &service.UserMapper{}, queueClient)
 	countryService := service.NewCountryService(uowFactory, appCache)
 	languageService := service.NewLanguageService(uowFactory, appCache)
 	unitService := service.NewUnitService(uowFactory)
-	reportService := service.NewReportService(uowFactory, queueClient)
+	reportService := service.NewReportService(uowFactory, queueClient, permService)
+	auditService := service.NewAuditService(uowFactory)
 
 	// Initialize handlers
-	userHandler := NewUserHandler(userService, permService, &service.UserMapper{})
+	userHandler := NewUserHandler(userService, permService, tokenService, &service.UserMapper{})
 	countryHandler := NewCountryHandler(countryService)
 	languageHandler := NewLanguageHandler(languageService)
 	unitHandler := NewUnitHandler(unitService)
 	reportHandler := NewReportHandler(reportService)
+	apiKeyHandler := NewAPIKeyHandler(apiKeyService)
+	oidcHandler := NewOIDCHandler(oidcService, tokenService)
+	impersonationHandler := NewImpersonationHandler(impersonationService)
+	permissionHandler := NewPermissionHandler(permService)
+	policyHandler := NewPolicyHandler(policyService)
+	organizationHandler := NewOrganizationHandler(organizationService, tokenService)
+	auditHandler := NewAuditHandler(auditService)
 
 	api := app.Group("/api")
 	v1 := api.Group("/v1")
 
 	// Public routes
 	v1.Post("/login", userHandler.Login)
+	v1.Post("/login/2fa", userHandler.Login2FA)
+	v1.Post("/token/refresh", userHandler.RefreshToken)
+	v1.Post("/password/forgot", userHandler.ForgotPassword)
+	v1.Post("/password/reset", userHandler.ResetPassword)
+	v1.Post("/email/verify", userHandler.VerifyEmail)
+	v1.Post("/email/verify/resend", userHandler.ResendVerificationEmail)
+	v1.Get("/auth/oidc/providers", oidcHandler.GetProviders)
+	v1.Get("/auth/oidc/:provider/login", oidcHandler.Login)
+	v1.Get("/auth/oidc/:provider/callback", oidcHandler.Callback)
 	v1.Get("/countries", countryHandler.GetAll)
 	v1.Get("/languages", languageHandler.GetAll)
 	v1.Get("/units", unitHandler.GetUnits)
 
-	v1.Use(middleware.AuthMiddleware)
+	v1.Use(middleware.AuthMiddleware(tokenService, apiKeyService))
+	v1.Use(middleware.ImpersonationAudit(impersonationService))
+	// Hesap ve kimlik bilgisi yönetimi API anahtarları ile yapılamaz.
+	interactive := middleware.InteractiveOnly()
 
-	userHandler.Setup2FARoutes(v1)
+	v1.Post("/logout", interactive, userHandler.Logout)
+	v1.Post("/logout-all", interactive, userHandler.LogoutAll)
+
+	v1.Get("/me", userHandler.GetMe)
+	v1.Patch("/me", interactive, userHandler.UpdateMe)
+	v1.Post("/me/password", interactive, userHandler.ChangePassword)
+	v1.Get("/me/sessions", interactive, userHandler.GetMySessions)
+	v1.Delete("/me/sessions/:sid", interactive, userHandler.RevokeMySession)
+	v1.Get("/me/permissions", permissionHandler.GetMine)
+	v1.Get("/me/api-keys", interactive, apiKeyHandler.GetMyKeys)
+	v1.Post("/me/api-keys", interactive, apiKeyHandler.CreateMyKey)
+	v1.Delete("/me/api-keys/:keyId", interactive, apiKeyHandler.RevokeMyKey)
+	v1.Get("/me/organizations", organizationHandler.GetMine)
+	v1.Post("/me/organization", interactive, organizationHandler.Switch)
+	v1.Get("/me/invitations", interactive, organizationHandler.GetMyInvitations)
+	v1.Post("/me/invitations/:organizationId", interactive, organizationHandler.AcceptInvitation)
+	v1.Delete("/me/invitations/:organizationId", interactive, organizationHandler.DeclineInvitation)
+
+	userHandler.Setup2FARoutes(v1, interactive)
+
+	v1.Post("/impersonation/end", impersonationHandler.End)
+	v1.Get("/permissions", permissionHandler.GetAll)
+
+	roleRoutes := v1.Group("/roles")
+	roleRoutes.Get("/", middleware.PermissionMiddleware(permService, permission.RoleSelect), permissionHandler.GetRoles)
+	roleRoutes.Get("/:id", middleware.PermissionMiddleware(permService, permission.RoleSelect), permissionHandler.GetRole)
+	roleRoutes.Post("/", interactive, middleware.PermissionMiddleware(permService, permission.RoleAdd), permissionHandler.CreateRole)
+	roleRoutes.Put("/:id", interactive, middleware.PermissionMiddleware(permService, permission.RoleUpdate), permissionHandler.UpdateRole)
+	roleRoutes.Delete("/:id", interactive, middleware.PermissionMiddleware(permService, permission.RoleDelete), permissionHandler.DeleteRole)
+
+	policyRoutes := v1.Group("/policies")
+	policyRoutes.Get("/", middleware.PermissionMiddleware(permService, permission.PolicySelect), policyHandler.GetPolicies)
+	policyRoutes.Post("/explain", middleware.PermissionMiddleware(permService, permission.PolicySelect), policyHandler.Explain)
+	policyRoutes.Post("/reload", interactive, middleware.PermissionMiddleware(permService, permission.PolicyUpdate), policyHandler.Reload)
+	policyRoutes.Post("/", interactive, middleware.PermissionMiddleware(permService, permission.PolicyAdd), policyHandler.CreatePolicy)
+	policyRoutes.Put("/:id", interactive, middleware.PermissionMiddleware(permService, permission.PolicyUpdate), policyHandler.UpdatePolicy)
+	policyRoutes.Delete("/:id", interactive, middleware.PermissionMiddleware(permService, permission.PolicyDelete), policyHandler.DeletePolicy)
+
+	// Üyelik işlemleri access token'daki aktif organizasyonda yapılır.
+	organizationRoutes := v1.Group("/organizations")
+	organizationRoutes.Post("/", interactive, middleware.PermissionMiddleware(permService, permission.OrganizationAdd), organizationHandler.Create)
+	organizationRoutes.Put("/current", interactive, middleware.PermissionMiddleware(permService, permission.OrganizationUpdate), organizationHandler.UpdateCurrent)
+	organizationRoutes.Post("/current/invitations", interactive, middleware.PermissionMiddleware(permService, permission.OrganizationUpdate), organizationHandler.InviteMember)
+	organizationRoutes.Delete("/current/invitations/:userId", interactive, middleware.PermissionMiddleware(permService, permission.OrganizationUpdate), organizationHandler.CancelInvitation)
+	organizationRoutes.Delete("/current/members/:userId", interactive, middleware.PermissionMiddleware(permService, permission.OrganizationUpdate), organizationHandler.RemoveMember)
+
+	auditRoutes := v1.Group("/audit-events")
+	auditRoutes.Get("/", middleware.PermissionMiddleware(permService, permission.AuditSelect), auditHandler.GetEvents)
+	auditRoutes.Get("/export", middleware.PermissionMiddleware(permService, permission.AuditSelect), auditHandler.Export)
 
 	countryRoutes := v1.Group("/countries")
 	countryRoutes.Get("/:code", countryHandler.GetByCode)
-	countryRoutes.Post("/", middleware.PermissionMiddleware(permService, "country", "write"), countryHandler.Create)
-	countryRoutes.Put("/:code", middleware.PermissionMiddleware(permService, "country", "write"), countryHandler.Update)
-	countryRoutes.Delete("/:code", middleware.PermissionMiddleware(permService, "country", "delete"), countryHandler.Delete)
+	countryRoutes.Post("/", middleware.PermissionMiddleware(permService, permission.CountryAdd), countryHandler.Create)
+	countryRoutes.Put("/:code", middleware.PermissionMiddleware(permService, permission.CountryUpdate), countryHandler.Update)
+	countryRoutes.Delete("/:code", middleware.PermissionMiddleware(permService, permission.CountryDelete), countryHandler.Delete)
 
+	// /users/:id route'larında hedef kullanıcı politikalara resource.id olarak verilir
+	// (örn. kullanıcıların sadece kendi kayıtlarını güncelleyebilmesi).
+	userParams := middleware.ParamAttributes("id")
 	userRoutes := v1.Group("/users")
-	userRoutes.Get("/", middleware.PermissionMiddleware(permService, "user", "read"), userHandler.GetAll)
-	userRoutes.Get("/:id", middleware.PermissionMiddleware(permService, "user", "read"), userHandler.Get)
-	userRoutes.Post("/", middleware.PermissionMiddleware(permService, "user", "write"), userHandler.Create)
-	userRoutes.Put("/:id", middleware.PermissionMiddleware(permService, "user", "write"), userHandler.Update)
-	userRoutes.Delete("/:id", middleware.PermissionMiddleware(permService, "user", "delete"), userHandler.Delete)
+	userRoutes.Get("/", middleware.PermissionMiddleware(permService, permission.UserSelect), userHandler.GetAll)
+	userRoutes.Get("/:id", middleware.PermissionMiddleware(permService, permission.UserSelect, userParams), userHandler.Get)
+	userRoutes.Post("/", middleware.PermissionMiddleware(permService, permission.UserAdd), userHandler.Create)
+	userRoutes.Put("/:id", middleware.PermissionMiddleware(permService, permission.UserUpdate, userParams), userHandler.Update)
+	userRoutes.Delete("/:id", middleware.PermissionMiddleware(permService, permission.UserDelete, userParams), userHandler.Delete)
+	userRoutes.Get("/:id/lock", middleware.PermissionMiddleware(permService, permission.UserSelect, userParams), userHandler.GetLockStatus)
+	userRoutes.Delete("/:id/lock", middleware.PermissionMiddleware(permService, permission.UserUpdate, userParams), userHandler.Unlock)
+	userRoutes.Get("/:id/sessions", middleware.PermissionMiddleware(permService, permission.UserSelect, userParams), userHandler.GetUserSessions)
+	userRoutes.Delete("/:id/sessions/:sid", middleware.PermissionMiddleware(permService, permission.UserUpdate, userParams), userHandler.RevokeUserSession)
+	userRoutes.Get("/:id/api-keys", interactive, middleware.PermissionMiddleware(permService, permission.UserSelect, userParams), apiKeyHandler.GetUserKeys)
+	userRoutes.Post("/:id/api-keys", interactive, middleware.PermissionMiddleware(permService, permission.UserUpdate, userParams), apiKeyHandler.CreateUserKey)
+	userRoutes.Delete("/:id/api-keys/:keyId", interactive, middleware.PermissionMiddleware(permService, permission.UserUpdate, userParams), apiKeyHandler.RevokeUserKey)
+	userRoutes.Post("/:id/impersonate", interactive, middleware.PermissionMiddleware(permService, permission.UserSpecial, userParams), impersonationHandler.Start)
+	userRoutes.Get("/:id/impersonations", middleware.PermissionMiddleware(permService, permission.UserSpecial, userParams), impersonationHandler.GetUserImpersonations)
+	userRoutes.Get("/:id/permissions", middleware.PermissionMiddleware(permService, permission.RoleSelect), permissionHandler.GetUserAccess)
+	userRoutes.Get("/:id/permissions/effective", middleware.PermissionMiddleware(permService, permission.RoleSelect), permissionHandler.GetUserEffective)
+	userRoutes.Get("/:id/permissions/history", middleware.PermissionMiddleware(permService, permission.RoleSelect), permissionHandler.GetUserHistory)
+	userRoutes.Post("/:id/permissions", interactive, middleware.PermissionMiddleware(permService, permission.RoleAssign), permissionHandler.GrantPermission)
+	userRoutes.Delete("/:id/permissions/:permission", interactive, middleware.PermissionMiddleware(permService, permission.RoleAssign), permissionHandler.RevokePermission)
+	userRoutes.Post("/:id/roles", interactive, middleware.PermissionMiddleware(permService, permission.RoleAssign), permissionHandler.AssignRole)
+	userRoutes.Delete("/:id/roles/:roleId", interactive, middleware.PermissionMiddleware(permService, permission.RoleAssign), permissionHandler.UnassignRole)
 
 	reportRoutes := v1.Group("/reports")
+	reportRoutes.Get("/", reportHandler.GetReports)
 	reportRoutes.Post("/", reportHandler.RequestReport)
 	reportRoutes.Get("/:id", reportHandler.GetReport)
 
 	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+	app.Get("/.well-known/jwks.json", JWKS)
 }
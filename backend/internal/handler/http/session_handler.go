@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/web"
+)
+
+// GetMySessions, oturum açmış kullanıcının açık oturumlarını (cihazlarını) listeler.
+func (h *UserHandler) GetMySessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	sessions, err := h.tokenService.ListSessions(ctx, authUser.UserID, authUser.SessionID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, sessions, i18n.Get(lang, "sessions_retrieved"))
+}
+
+// RevokeMySession, oturum açmış kullanıcının oturumlarından birini sonlandırır.
+// Mevcut oturumun sonlandırılması çıkış yapmakla aynıdır.
+func (h *UserHandler) RevokeMySession(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	authUser, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	sessionID := c.Params("sid")
+	if sessionID == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.tokenService.RevokeSession(ctx, authUser.UserID, sessionID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "session_revoked"))
+}
+
+// GetUserSessions, bir kullanıcının açık oturumlarını yöneticiye listeler.
+func (h *UserHandler) GetUserSessions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	// Yönetici kendi oturumlarını listeliyorsa mevcut oturumu yine işaretlenir.
+	var currentSessionID string
+	if authUser, err := auth.GetUserFromContext(c.UserContext()); err == nil && authUser.UserID == id {
+		currentSessionID = authUser.SessionID
+	}
+
+	sessions, err := h.tokenService.ListSessions(ctx, id, currentSessionID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, sessions, i18n.Get(lang, "sessions_retrieved"))
+}
+
+// RevokeUserSession, yöneticinin bir kullanıcının oturumlarından birini sonlandırmasını sağlar.
+func (h *UserHandler) RevokeUserSession(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	sessionID := c.Params("sid")
+	if sessionID == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	if err := h.tokenService.RevokeSession(ctx, id, sessionID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "session_revoked"))
+}
@@ -12,6 +12,7 @@ import (
 	"ths-erp.com/internal/domain"
 	"ths-erp.com/internal/dto"
 	"ths-erp.com/internal/platform/i18n"
+	"ths-erp.com/internal/platform/password"
 	"ths-erp.com/internal/platform/web"
 	"ths-erp.com/internal/service"
 )
@@ -19,21 +20,26 @@ import (
 const handlerTimeout = 3 * time.Second
 
 type UserHandler struct {
-	userService service.IUserService
-	userMapper  service.IMapper[*domain.User, *dto.UserResponse]
+	userService  service.IUserService
+	tokenService service.ITokenService
+	userMapper   service.IMapper[*domain.User, *dto.UserResponse]
 }
 
-func NewUserHandler(userService service.IUserService, permService service.IPermissionService, mapper service.IMapper[*domain.User, *dto.UserResponse]) *UserHandler {
+func NewUserHandler(userService service.IUserService, permService service.IPermissionService, tokenService service.ITokenService, mapper service.IMapper[*domain.User, *dto.UserResponse]) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		userMapper:  mapper,
+		userService:  userService,
+		tokenService: tokenService,
+		userMapper:   mapper,
 	}
 }
 
 // handleError, servis katmanından gelen hataları uygun HTTP yanıtlarına dönüştürür.
 func (h *UserHandler) handleError(c *fiber.Ctx, err error) error {
 	lang := c.Locals("lang").(string)
+	var policyErr *password.PolicyError
 	switch {
+	case errors.As(err, &policyErr):
+		return web.ValidationErrors(c, policyErr.Violations)
 	case errors.Is(err, apperrors.ErrNotFound):
 		return web.NotFound(c, i18n.Get(lang, "user_not_found"))
 	case errors.Is(err, apperrors.ErrInvalidCredentials):
@@ -42,8 +48,32 @@ func (h *UserHandler) handleError(c *fiber.Ctx, err error) error {
 		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
 	case errors.Is(err, apperrors.ErrEmailExists):
 		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "email_exists"))
+	case errors.Is(err, apperrors.ErrUserInOtherOrgs):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "user_in_other_organizations"))
 	case errors.Is(err, apperrors.ErrInvalid2FACode):
 		return web.Unauthorized(c, i18n.Get(lang, "invalid_2fa_code"))
+	case errors.Is(err, apperrors.Err2FASetupNotCompleted):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "2fa_not_enabled"))
+	case errors.Is(err, apperrors.Err2FAAlreadyEnabled):
+		return web.CustomError(c, fiber.StatusConflict, i18n.Get(lang, "2fa_already_enabled"))
+	case errors.Is(err, apperrors.ErrInvalidChallenge):
+		return web.Unauthorized(c, i18n.Get(lang, "invalid_2fa_challenge"))
+	case errors.Is(err, apperrors.ErrInvalidRefreshToken), errors.Is(err, apperrors.ErrRefreshTokenReused):
+		return web.Unauthorized(c, i18n.Get(lang, "invalid_refresh_token"))
+	case errors.Is(err, apperrors.ErrInvalidResetToken):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_reset_token"))
+	case errors.Is(err, apperrors.ErrInvalidVerifyToken):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_verification_token"))
+	case errors.Is(err, apperrors.ErrEmailNotVerified):
+		return web.CustomError(c, fiber.StatusForbidden, i18n.Get(lang, "email_not_verified"))
+	case errors.Is(err, apperrors.ErrWeakPassword):
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "weak_password"))
+	case errors.Is(err, apperrors.ErrAccountLocked):
+		return web.CustomError(c, fiber.StatusTooManyRequests, i18n.Get(lang, "account_locked"))
+	case errors.Is(err, apperrors.ErrSessionNotFound):
+		return web.NotFound(c, i18n.Get(lang, "session_not_found"))
+	case errors.Is(err, apperrors.ErrTooManyAttempts):
+		return web.CustomError(c, fiber.StatusTooManyRequests, i18n.Get(lang, "too_many_attempts"))
 	default:
 		log.Printf("Unhandled error in UserHandler: %v", err)
 		return web.CustomError(c, fiber.StatusInternalServerError, i18n.Get(lang, "internal_server_error"))
@@ -67,24 +97,24 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 	}
 
 	if user.TwoFactorEnabled {
-		return web.Success(c, fiber.StatusOK, fiber.Map{"userId": user.ID, "twoFactorRequired": true}, "2FA required")
+		challenge, err := h.tokenService.IssueChallenge(ctx, user)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		return web.Success(c, fiber.StatusOK, challenge, i18n.Get(lang, "2fa_required"))
 	}
 
-	token, err := auth.GenerateJWT(user.ID, user.Email)
+	loginResponse, err := h.tokenService.IssueTokens(ctx, user)
 	if err != nil {
-		log.Printf("Error generating JWT for user %d: %v", user.ID, err)
+		log.Printf("Error issuing tokens for user %d: %v", user.ID, err)
 		return web.CustomError(c, fiber.StatusInternalServerError, "Could not process login request")
 	}
 
-	userResponse := h.userMapper.ToResponse(user)
-	loginResponse := dto.LoginResponse{
-		Token: token,
-		User:  userResponse,
-	}
-
 	return web.Success(c, fiber.StatusOK, loginResponse, "Login successful")
 }
 
+// Login2FA, Login'in döndürdüğü challenge token'ı bir TOTP veya kurtarma koduyla
+// birlikte alarak girişi tamamlar.
 func (h *UserHandler) Login2FA(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
 	defer cancel()
@@ -92,32 +122,74 @@ func (h *UserHandler) Login2FA(c *fiber.Ctx) error {
 	lang := c.Locals("lang").(string)
 	var req dto.Login2FARequest
 
-	if err := c.BodyParser(&req); err != nil {
+	if err := c.BodyParser(&req); err != nil || req.ChallengeToken == "" || req.Code == "" {
 		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
 	}
 
-	valid, err := h.userService.Verify2FA(ctx, req.UserID, req.Code)
-	if err != nil || !valid {
+	loginResponse, err := h.userService.Login2FA(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	user, err := h.userService.GetUser(ctx, req.UserID)
+	return web.Success(c, fiber.StatusOK, loginResponse, i18n.Get(lang, "login_successful"))
+}
+
+// RefreshToken, geçerli bir refresh token karşılığında yeni bir token çifti üretir.
+// Her kullanımda refresh token rotasyona uğrar; eski token bir daha kullanılamaz.
+func (h *UserHandler) RefreshToken(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	var req dto.RefreshTokenRequest
+
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return web.CustomError(c, fiber.StatusBadRequest, i18n.Get(lang, "invalid_request"))
+	}
+
+	loginResponse, err := h.tokenService.Refresh(ctx, req.RefreshToken)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	token, err := auth.GenerateJWT(user.ID, user.Email)
+	return web.Success(c, fiber.StatusOK, loginResponse, i18n.Get(lang, "token_refreshed"))
+}
+
+// Logout, mevcut oturumu sonlandırır: access token iptal listesine eklenir ve
+// oturumun refresh token'ları iptal edilir.
+func (h *UserHandler) Logout(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	user, err := auth.GetUserFromContext(c.UserContext())
 	if err != nil {
-		log.Printf("Error generating JWT for user %d: %v", user.ID, err)
-		return web.CustomError(c, fiber.StatusInternalServerError, "Could not process login request")
+		return web.Unauthorized(c)
 	}
 
-	loginResponse := dto.LoginResponse{
-		Token: token,
-		User:  user,
+	if err := h.tokenService.Logout(ctx, user); err != nil {
+		return h.handleError(c, err)
 	}
 
-	return web.Success(c, fiber.StatusOK, loginResponse, "Login successful")
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "logout_successful"))
+}
+
+// LogoutAll, kullanıcının tüm cihazlardaki oturumlarını sonlandırır.
+func (h *UserHandler) LogoutAll(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), handlerTimeout)
+	defer cancel()
+
+	lang := c.Locals("lang").(string)
+	user, err := auth.GetUserFromContext(c.UserContext())
+	if err != nil {
+		return web.Unauthorized(c)
+	}
+
+	if err := h.tokenService.RevokeAllForUser(ctx, user.UserID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return web.Success(c, fiber.StatusOK, nil, i18n.Get(lang, "logout_all_successful"))
 }
 
 func (h *UserHandler) Get(c *fiber.Ctx) error {
@@ -0,0 +1,64 @@
+package permission
+
+// Standart işlemler. Kaynaklar ihtiyaç duydukları işlemleri tanımlar; özel işlemler
+// (örn. impersonation) "special" veya kaynağa özgü bir ad ile tanımlanır.
+const (
+	ActionSelect  = "select"
+	ActionAdd     = "add"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionSpecial = "special"
+	ActionAssign  = "assign"
+	// ActionReadAll, sahiplik kısıtlaması olan kaynaklarda (bkz. repository.DataScope) başka
+	// kullanıcılara ait kayıtları da okuma yetkisidir.
+	ActionReadAll = "read_all"
+)
+
+// Kullanıcılar
+var (
+	UserSelect  = declare("user", ActionSelect, "Kullanıcıları, oturumlarını ve API anahtarlarını görüntüleme")
+	UserAdd     = declare("user", ActionAdd, "Kullanıcı ve servis hesabı oluşturma")
+	UserUpdate  = declare("user", ActionUpdate, "Kullanıcıları güncelleme, kilit açma, oturum ve API anahtarı yönetimi")
+	UserDelete  = declare("user", ActionDelete, "Kullanıcı silme")
+	UserSpecial = declare("user", ActionSpecial, "Kullanıcı adına işlem yapma (impersonation)")
+)
+
+// Roller ve yetki atamaları
+var (
+	RoleSelect = declare("role", ActionSelect, "Rolleri ve kullanıcıların yetkilerini görüntüleme")
+	RoleAdd    = declare("role", ActionAdd, "Rol oluşturma")
+	RoleUpdate = declare("role", ActionUpdate, "Rol ve rol yetkilerini güncelleme")
+	RoleDelete = declare("role", ActionDelete, "Rol silme")
+	RoleAssign = declare("role", ActionAssign, "Kullanıcılara rol atama ve doğrudan yetki verme")
+)
+
+// Yetkilendirme politikaları (bkz. internal/policy)
+var (
+	PolicySelect = declare("policy", ActionSelect, "Politikaları görüntüleme ve yetki kararlarını açıklama (explain)")
+	PolicyAdd    = declare("policy", ActionAdd, "Politika oluşturma")
+	PolicyUpdate = declare("policy", ActionUpdate, "Politika güncelleme ve politikaları yeniden yükleme")
+	PolicyDelete = declare("policy", ActionDelete, "Politika silme")
+)
+
+// Organizasyonlar (kiracılar)
+var (
+	OrganizationAdd    = declare("organization", ActionAdd, "Yeni organizasyon oluşturma")
+	OrganizationUpdate = declare("organization", ActionUpdate, "Aktif organizasyonu güncelleme ve üyelerini yönetme")
+)
+
+// Denetim kayıtları
+var (
+	AuditSelect = declare("audit", ActionSelect, "Denetim kayıtlarını görüntüleme ve CSV olarak dışa aktarma")
+)
+
+// Raporlar
+var (
+	ReportReadAll = declare("report", ActionReadAll, "Tüm kullanıcıların raporlarını görüntüleme")
+)
+
+// Ülkeler
+var (
+	CountryAdd    = declare("country", ActionAdd, "Ülke ekleme")
+	CountryUpdate = declare("country", ActionUpdate, "Ülke güncelleme")
+	CountryDelete = declare("country", ActionDelete, "Ülke silme")
+)
@@ -0,0 +1,97 @@
+// Package permission, uygulamada kullanılan tüm "kaynak:işlem" yetkilerinin tek kaynağıdır.
+// REST route'ları, GraphQL resolver'ları, servisler, roller ve API anahtarı kapsamları
+// sadece burada tanımlanmış yetkileri kullanabilir.
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ths-erp.com/internal/domain"
+)
+
+// ErrUndeclared, registry'de tanımlı olmayan bir yetki kullanıldığında döner.
+var ErrUndeclared = errors.New("permission: undeclared permission")
+
+// Permission, bir kaynak üzerindeki tek bir işlemdir.
+type Permission struct {
+	Resource string
+	Action   string
+}
+
+// String, yetkiyi roller ve API anahtarlarında kullanılan "kaynak:işlem" biçiminde döner.
+func (p Permission) String() string {
+	return domain.PermissionName(p.Resource, p.Action)
+}
+
+// Definition, bir yetkinin yönetim arayüzlerinde gösterilen tanımıdır.
+type Definition struct {
+	Permission
+	Description string
+}
+
+var (
+	definitions []Definition
+	declared    = map[Permission]bool{}
+	resources   = map[string]bool{}
+)
+
+// declare, bir yetkiyi registry'ye ekler. Sadece bu paketteki değişken tanımlarında kullanılır;
+// böylece yetki listesi derleme zamanında sabittir.
+func declare(resource, action, description string) Permission {
+	p := Permission{Resource: resource, Action: action}
+	if declared[p] {
+		panic(fmt.Sprintf("permission: %s declared twice", p))
+	}
+	if !domain.ValidPermission(p.String()) || strings.Contains(p.String(), "*") {
+		panic(fmt.Sprintf("permission: invalid permission %q", p))
+	}
+	declared[p] = true
+	resources[resource] = true
+	definitions = append(definitions, Definition{Permission: p, Description: description})
+	return p
+}
+
+// All, tanımlı tüm yetkileri tanımlanma sırasıyla döner.
+func All() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+// IsDeclared, yetkinin registry'de tanımlı olup olmadığını söyler.
+func IsDeclared(p Permission) bool {
+	return declared[p]
+}
+
+// Validate, yetki tanımlı değilse ErrUndeclared döner.
+func Validate(p Permission) error {
+	if !declared[p] {
+		return fmt.Errorf("%w: %s", ErrUndeclared, p)
+	}
+	return nil
+}
+
+// MustBeDeclared, route ve resolver tanımlanırken çağrılır; tanımsız bir yetki uygulamanın
+// açılmasını engeller.
+func MustBeDeclared(p Permission) Permission {
+	if err := Validate(p); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ValidGrant, bir role veya API anahtarına verilebilecek yetkiyi doğrular: "*", tanımlı bir
+// kaynak için "kaynak:*" veya tanımlı bir "kaynak:işlem".
+func ValidGrant(grant string) bool {
+	if grant == domain.PermissionWildcard {
+		return true
+	}
+	if !domain.ValidPermission(grant) {
+		return false
+	}
+	resource, action, _ := strings.Cut(grant, ":")
+	if action == "*" {
+		return resources[resource]
+	}
+	return declared[Permission{Resource: resource, Action: action}]
+}
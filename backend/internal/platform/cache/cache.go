@@ -7,8 +7,20 @@ import (
 
 type ICache interface {
 	Get(key string) (interface{}, bool)
+	// Lookup, Get gibidir ancak anahtarın olmamasını (found=false, err=nil) önbelleğe ulaşılamamasından
+	// (err != nil) ayırır. Hata durumunda kapalı kalması gereken güvenlik kontrolleri bunu kullanır.
+	Lookup(key string) (value interface{}, found bool, err error)
 	Set(key string, value interface{}, duration time.Duration)
+	// Store, Set gibidir ancak yazılamadığında hata döner. Yazılmaması güvenlik açığı doğuran
+	// kayıtlar (örn. token iptalleri) bunu kullanır.
+	Store(key string, value interface{}, duration time.Duration) error
+	// Add, anahtar yoksa yazar ve yazıp yazmadığını döner (Redis SETNX). Veritabanından doldurulan
+	// değerlerin arada yazılmış daha yeni bir değeri ezmemesi için kullanılır.
+	Add(key string, value interface{}, duration time.Duration) bool
 	Delete(key string)
+	// Take, anahtarı okur ve aynı işlemde siler. Tek kullanımlık değerlerin (örn. OIDC state)
+	// eşzamanlı iki istek tarafından birlikte okunmasını engeller.
+	Take(key string) (interface{}, bool)
 }
 
 type InMemoryCache struct {
@@ -34,13 +46,18 @@ func (c *InMemoryCache) Get(key string) (interface{}, bool) {
 	defer c.mu.RUnlock()
 
 	it, found := c.items[key]
-	if !found || time.Now().UnixNano() > it.expiration {
+	if !found || (it.expiration > 0 && time.Now().UnixNano() > it.expiration) {
 		return nil, false
 	}
 
 	return it.value, true
 }
 
+func (c *InMemoryCache) Lookup(key string) (interface{}, bool, error) {
+	val, found := c.Get(key)
+	return val, found, nil
+}
+
 func (c *InMemoryCache) Set(key string, value interface{}, duration time.Duration) {
 	var expiration int64
 	if duration > 0 {
@@ -56,6 +73,30 @@ func (c *InMemoryCache) Set(key string, value interface{}, duration time.Duratio
 	}
 }
 
+func (c *InMemoryCache) Store(key string, value interface{}, duration time.Duration) error {
+	c.Set(key, value, duration)
+	return nil
+}
+
+func (c *InMemoryCache) Add(key string, value interface{}, duration time.Duration) bool {
+	var expiration int64
+	if duration > 0 {
+		expiration = time.Now().Add(duration).UnixNano()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if it, found := c.items[key]; found && (it.expiration == 0 || time.Now().UnixNano() <= it.expiration) {
+		return false
+	}
+	c.items[key] = item{
+		value:      value,
+		expiration: expiration,
+	}
+	return true
+}
+
 func (c *InMemoryCache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -63,6 +104,21 @@ func (c *InMemoryCache) Delete(key string) {
 	delete(c.items, key)
 }
 
+func (c *InMemoryCache) Take(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, found := c.items[key]
+	if !found {
+		return nil, false
+	}
+	delete(c.items, key)
+	if it.expiration > 0 && time.Now().UnixNano() > it.expiration {
+		return nil, false
+	}
+	return it.value, true
+}
+
 func (c *InMemoryCache) janitor(interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// IPubSub, uygulama instance'ları arasında mesaj yayınlamak için kullanılır
+// (örn. her instance'ın yerel önbelleğini geçersiz kılmak).
+type IPubSub interface {
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe, kanala abone olur ve gelen her mesaj için handler'ı ayrı bir goroutine'de
+	// sırayla çağırır. Abonelik ctx iptal edilene kadar sürer.
+	Subscribe(ctx context.Context, channel string, handler func(message string))
+}
+
+// RedisPubSub, IPubSub'ın Redis pub/sub implementasyonudur. Bağlantı koparsa
+// go-redis aboneliği otomatik olarak yeniler.
+type RedisPubSub struct {
+	client *redis.Client
+}
+
+// NewRedisPubSub creates a new RedisPubSub instance.
+func NewRedisPubSub(client *redis.Client) IPubSub {
+	return &RedisPubSub{client: client}
+}
+
+func (p *RedisPubSub) Publish(ctx context.Context, channel, message string) error {
+	return p.client.Publish(ctx, channel, message).Err()
+}
+
+func (p *RedisPubSub) Subscribe(ctx context.Context, channel string, handler func(message string)) {
+	sub := p.client.Subscribe(ctx, channel)
+	go func() {
+		defer sub.Close()
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					log.Printf("WARN: Redis subscription to %q closed", channel)
+					return
+				}
+				handler(msg.Payload)
+			}
+		}
+	}()
+}
@@ -33,6 +33,17 @@ func (c *RedisCache) Get(key string) (interface{}, bool) {
 	return val, true
 }
 
+// Lookup retrieves an item from the cache and reports connection errors instead of treating them as a miss.
+func (c *RedisCache) Lookup(key string) (interface{}, bool, error) {
+	val, err := c.client.Get(c.ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
 // Set adds an item to the cache for a specified duration.
 func (c *RedisCache) Set(key string, value interface{}, duration time.Duration) {
 	// Redis client handles non-byte values, but byte slices are common.
@@ -42,7 +53,31 @@ func (c *RedisCache) Set(key string, value interface{}, duration time.Duration)
 	}
 }
 
+// Store adds an item to the cache and reports errors instead of ignoring them.
+func (c *RedisCache) Store(key string, value interface{}, duration time.Duration) error {
+	return c.client.Set(c.ctx, key, value, duration).Err()
+}
+
+// Add stores an item only if the key does not exist yet (SETNX).
+func (c *RedisCache) Add(key string, value interface{}, duration time.Duration) bool {
+	ok, err := c.client.SetNX(c.ctx, key, value, duration).Result()
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
 // Delete removes an item from the cache.
 func (c *RedisCache) Delete(key string) {
 	c.client.Del(c.ctx, key)
 }
+
+// Take retrieves and removes an item atomically (GETDEL, Redis 6.2+).
+func (c *RedisCache) Take(key string) (interface{}, bool) {
+	val, err := c.client.GetDel(c.ctx, key).Bytes()
+	if err != nil {
+		// redis.Nil veya bağlantı hatası; iki durumda da değer kullanılamaz.
+		return nil, false
+	}
+	return val, true
+}
@@ -7,6 +7,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"ths-erp.com/internal/config"
+	"ths-erp.com/internal/tenant"
 )
 
 func Connect(cfg *config.Config) (*gorm.DB, error) {
@@ -23,6 +24,11 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Kiracıya ait tabloların sorguları aktif organizasyonla sınırlanır (bkz. internal/tenant).
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
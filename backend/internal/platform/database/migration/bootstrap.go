@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/password"
+	"ths-erp.com/internal/tenant"
+)
+
+var (
+	// ErrAdminExists, varsayılan organizasyonda sistem yöneticisi rolüne sahip bir kullanıcı varken
+	// CreateAdmin çağrıldığında döner. Sonraki yöneticiler uygulama üzerinden atanır.
+	ErrAdminExists = errors.New("an administrator already exists")
+	// ErrEmailTaken, CreateAdmin'e verilen e-posta adresi başka bir kullanıcıya aitse döner.
+	ErrEmailTaken = errors.New("a user with this email already exists")
+)
+
+// CreateAdmin, kurulumun ilk yöneticisini oluşturur: e-postası doğrulanmış bir kullanıcı ekler,
+// varsayılan organizasyona üye yapar ve sistem yöneticisi rolünü atar. Şifre, uygulamanın şifre
+// politikasını karşılamalıdır. Varsayılan organizasyonda zaten bir yönetici varsa ErrAdminExists döner.
+func CreateAdmin(db *gorm.DB, name, email, plainPassword string) error {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+	if name == "" || !govalidator.IsEmail(email) {
+		return errors.New("a name and a valid email address are required")
+	}
+	if err := password.Validate(plainPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := password.Hash(plainPassword)
+	if err != nil {
+		return fmt.Errorf("could not hash password: %w", err)
+	}
+
+	return withLock(db, func(tx *gorm.DB) error {
+		org, err := findDefaultOrganization(tx)
+		if err != nil {
+			return err
+		}
+		orgDB := tx.WithContext(tenant.WithOrganization(tx.Statement.Context, org.ID))
+		adminRole, err := seedAdminRole(orgDB)
+		if err != nil {
+			return err
+		}
+
+		var admins int64
+		if err := orgDB.Model(&domain.UserRole{}).Where("role_id = ?", adminRole.ID).Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins > 0 {
+			return ErrAdminExists
+		}
+		var existing int64
+		if err := tx.Model(&domain.User{}).Where("email = ?", email).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrEmailTaken
+		}
+
+		verifiedAt := time.Now()
+		admin := &domain.User{
+			Name:            name,
+			Email:           email,
+			PasswordHash:    hashedPassword,
+			EmailVerifiedAt: &verifiedAt,
+		}
+		if err := tx.Create(admin).Error; err != nil {
+			return fmt.Errorf("could not create admin user: %w", err)
+		}
+		if err := tx.Create(&domain.OrganizationMember{OrganizationID: org.ID, UserID: admin.ID}).Error; err != nil {
+			return err
+		}
+		if err := orgDB.Create(&domain.UserRole{UserID: admin.ID, RoleID: adminRole.ID}).Error; err != nil {
+			return fmt.Errorf("could not assign admin role: %w", err)
+		}
+		log.Printf("✓ Administrator %s created", email)
+		return nil
+	})
+}
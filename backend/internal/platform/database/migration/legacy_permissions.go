@@ -0,0 +1,92 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+)
+
+// migrateLegacyPermissions, user_permissions tablosundaki kullanıcı ve kaynak başına boolean
+// yetkileri rollere taşır. Aynı yetki kümesine sahip kullanıcılar tek bir "legacy-<özet>" rolünde
+// toplanır; böylece 50 muhasebeci için 50 × N satır yerine tek bir rol oluşur. Taşınan satırlar
+// aynı transaction içinde silinir, bu nedenle fonksiyon tekrar çalıştırıldığında aynı satırları iki kez taşımaz.
+func migrateLegacyPermissions(db *gorm.DB) error {
+	var rows []domain.UserPermission
+	if err := db.Order("user_id, resource").Find(&rows).Error; err != nil {
+		return fmt.Errorf("could not read legacy user permissions: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	rowIDs := make([]int, 0, len(rows))
+	permissionsByUser := map[int][]string{}
+	for _, row := range rows {
+		rowIDs = append(rowIDs, row.ID)
+		for action, granted := range map[string]bool{
+			"select":  row.CanSelect,
+			"add":     row.CanAdd,
+			"update":  row.CanUpdate,
+			"delete":  row.CanDelete,
+			"special": row.CanSpecial,
+		} {
+			if granted {
+				permissionsByUser[row.UserID] = append(permissionsByUser[row.UserID], domain.PermissionName(row.Resource, action))
+			}
+		}
+	}
+
+	// Yetki kümesi -> kullanıcılar
+	usersBySet := map[string][]int{}
+	for userID, permissions := range permissionsByUser {
+		sort.Strings(permissions)
+		key := strings.Join(permissions, ",")
+		usersBySet[key] = append(usersBySet[key], userID)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for key, userIDs := range usersBySet {
+			sum := sha256.Sum256([]byte(key))
+			role := domain.Role{
+				Name:        "legacy-" + hex.EncodeToString(sum[:4]),
+				Description: "Migrated from user_permissions",
+			}
+			if err := tx.Where(domain.Role{Name: role.Name}).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			permissions := strings.Split(key, ",")
+			rolePermissions := make([]domain.RolePermission, 0, len(permissions))
+			for _, permission := range permissions {
+				rolePermissions = append(rolePermissions, domain.RolePermission{RoleID: role.ID, Permission: permission})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rolePermissions).Error; err != nil {
+				return err
+			}
+
+			userRoles := make([]domain.UserRole, 0, len(userIDs))
+			for _, userID := range userIDs {
+				userRoles = append(userRoles, domain.UserRole{UserID: userID, RoleID: role.ID})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&userRoles).Error; err != nil {
+				return err
+			}
+			log.Printf("✓ Legacy permissions of %d user(s) migrated to role %s", len(userIDs), role.Name)
+		}
+
+		// Hiç yetki vermeyen satırlar da dahil okunan tüm eski kayıtlar taşınmış sayılır.
+		return tx.Where("id IN ?", rowIDs).Delete(&domain.UserPermission{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("could not migrate legacy user permissions: %w", err)
+	}
+	log.Println("✓ Legacy user permissions migrated to roles")
+	return nil
+}
@@ -1,68 +1,278 @@
 package migration
 
 import (
+	"fmt"
 	"log"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/password"
+	"ths-erp.com/internal/tenant"
 )
 
-func Migrate(db *gorm.DB) {
-	dropTables(db)
-	autoMigrate(db)
-	seedData(db)
+// migrations, şemanın sıralı geçmişidir. Yeni migration'lar listenin sonuna eklenir.
+var migrations = []Migration{
+	{Version: 1, Name: "initial_schema", Up: createSchema, Down: dropSchema},
+	{Version: 2, Name: "audit_events_append_only", Up: protectAuditEvents, Down: unprotectAuditEvents},
+	{Version: 3, Name: "default_organization", Up: moveToDefaultOrganization, Down: keepData},
+	{Version: 4, Name: "reference_data", Up: seedReferenceData, Down: keepData},
+	{Version: 5, Name: "organization_invitations", Up: createOrganizationInvitations, Down: dropOrganizationInvitations},
+	{Version: 6, Name: "disable_legacy_demo_accounts", Up: disableLegacyDemoAccounts, Down: keepData},
 }
 
-func dropTables(db *gorm.DB) {
-	if err := db.Migrator().DropTable(
-		&domain.User{},
-		&domain.UserPermission{},
-		&domain.Report{},
-		&domain.Country{},
-		&domain.CountryTranslation{},
-		&domain.Language{},
-		&domain.LanguageTranslation{},
-	); err != nil {
-		log.Fatalf("Could not drop tables: %v", err)
+// legacyDemoEmails ve legacyDemoPassword, eski sürümlerin her veritabanına eklediği örnek hesaplardır.
+var legacyDemoEmails = []string{"admin@example.com", "test@example.com"}
+
+const legacyDemoPassword = "password"
+
+// disabledPasswordHash, hiçbir özet algoritmasının tanımadığı değerdir; bu özete sahip bir hesaba
+// şifre ile giriş yapılamaz, şifre sıfırlama ile yeni şifre belirlenmesi gerekir.
+const disabledPasswordHash = "!disabled"
+
+// createOrganizationInvitations, organizasyon davetleri tablosunu ekler.
+func createOrganizationInvitations(tx *gorm.DB) error {
+	return execAll(tx, []string{
+		`CREATE TABLE IF NOT EXISTS "organization_invitations" (
+			"id" bigserial,
+			"organization_id" bigint,
+			"user_id" bigint,
+			"invited_by_id" bigint,
+			"expires_at" timestamptz NOT NULL,
+			"created_at" timestamptz,
+			PRIMARY KEY ("id"),
+			CONSTRAINT "fk_organization_invitations_organization" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id")
+		)`,
+		`CREATE INDEX IF NOT EXISTS "idx_organization_invitations_user_id" ON "organization_invitations" ("user_id")`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS "idx_organization_invitations_org_user" ON "organization_invitations" ("organization_id","user_id")`,
+	})
+}
+
+func dropOrganizationInvitations(tx *gorm.DB) error {
+	return tx.Exec(`DROP TABLE IF EXISTS "organization_invitations"`).Error
+}
+
+// disableLegacyDemoAccounts, eski sürümlerden kalan ve hâlâ "password" şifresini kullanan örnek
+// hesapların şifresini geçersiz kılar, açık oturumlarını ve yenileme token'larını iptal eder.
+// Hesaplar silinmez; sahipleri şifre sıfırlama ile yeni bir şifre belirleyebilir. Şifresi
+// değiştirilmiş hesaplara dokunulmaz.
+func disableLegacyDemoAccounts(tx *gorm.DB) error {
+	var users []domain.User
+	if err := tx.Where("email IN ?", legacyDemoEmails).Find(&users).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, user := range users {
+		if ok, _, err := password.Verify(legacyDemoPassword, user.PasswordHash); err != nil || !ok {
+			continue
+		}
+		if err := tx.Model(&domain.User{}).Where("id = ?", user.ID).Updates(map[string]any{
+			"password_hash":      disabledPasswordHash,
+			"tokens_valid_after": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		log.Printf("✓ Demo account %s disabled; a password reset is required", user.Email)
 	}
-	log.Println("✓ Tables dropped")
+	return nil
+}
+
+// keepData, veri migration'larının Down adımıdır: oluşturulan veya taşınan kayıtlar silinmez.
+func keepData(*gorm.DB) error {
+	return nil
 }
 
-func autoMigrate(db *gorm.DB) {
-	if err := db.AutoMigrate(
-		&domain.User{},
-		&domain.UserPermission{},
+// protectAuditEvents, audit_events tablosunu sadece eklemeye açık hale getirir: kayıtların
+// güncellenmesi veya silinmesi, uygulamanın veritabanı kullanıcısı ile de olsa reddedilir.
+func protectAuditEvents(tx *gorm.DB) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events`,
+		`CREATE TRIGGER audit_events_append_only
+			BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`,
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unprotectAuditEvents(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable("audit_events") {
+		return tx.Exec("DROP FUNCTION IF EXISTS audit_events_append_only()").Error
+	}
+	if err := tx.Exec("DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events").Error; err != nil {
+		return err
+	}
+	return tx.Exec("DROP FUNCTION IF EXISTS audit_events_append_only()").Error
+}
+
+// moveToDefaultOrganization, varsayılan organizasyonu oluşturur, organizasyonu olmayan kullanıcıları
+// ve kayıtları ona taşır ve eski user_permissions yetkilerini varsayılan organizasyonun rollerine çevirir.
+func moveToDefaultOrganization(tx *gorm.DB) error {
+	org, err := ensureDefaultOrganization(tx)
+	if err != nil {
+		return err
+	}
+	if err := backfillOrganization(tx, org); err != nil {
+		return err
+	}
+	return migrateLegacyPermissions(tx.WithContext(tenant.WithOrganization(tx.Statement.Context, org.ID)))
+}
+
+// ensureDefaultOrganization, çok kiracılı yapıdan önceki kullanıcıların ve kayıtların taşındığı
+// varsayılan organizasyonu oluşturur.
+func ensureDefaultOrganization(tx *gorm.DB) (*domain.Organization, error) {
+	org := domain.Organization{Slug: domain.DefaultOrganizationSlug}
+	if err := tx.Where(domain.Organization{Slug: org.Slug}).Attrs(domain.Organization{Name: "Default"}).FirstOrCreate(&org).Error; err != nil {
+		return nil, fmt.Errorf("could not create default organization: %w", err)
+	}
+	return &org, nil
+}
+
+// backfillOrganization, hiçbir organizasyonun üyesi olmayan kullanıcıları ve organizasyonu olmayan
+// (organization_id = 0) kiracı kayıtlarını varsayılan organizasyona taşır. Taşınacak kayıt
+// kalmadığında hiçbir şey yapmaz.
+func backfillOrganization(tx *gorm.DB, org *domain.Organization) error {
+	if err := tx.Exec(`INSERT INTO organization_members (organization_id, user_id, created_at)
+		SELECT ?, u.id, NOW() FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM organization_members m WHERE m.user_id = u.id)`, org.ID).Error; err != nil {
+		return fmt.Errorf("could not move existing users to the default organization: %w", err)
+	}
+	for _, model := range []interface{}{
+		&domain.Role{},
+		&domain.UserRole{},
+		&domain.UserGrant{},
+		&domain.PermissionAuditLog{},
+		&domain.Policy{},
 		&domain.Report{},
-		&domain.Country{},
-		&domain.CountryTranslation{},
-		&domain.Language{},
-		&domain.LanguageTranslation{},
-		&domain.Unit{},
-		&domain.UnitTranslation{},
-	); err != nil {
-		log.Fatalf("Could not migrate database: %v", err)
+		&domain.APIKey{},
+		&domain.UserSession{},
+	} {
+		if err := tx.Model(model).Where(tenant.Column+" = ?", 0).Update(tenant.Column, org.ID).Error; err != nil {
+			return fmt.Errorf("could not move existing data to the default organization: %w", err)
+		}
+	}
+	return nil
+}
+
+// seedReferenceData, bütün ortamlarda gereken ortak verileri (diller, ülkeler, birimler) ve
+// varsayılan organizasyonun sistem yöneticisi rolünü oluşturur. Her tablo sadece boşsa doldurulur;
+// böylece sürümlü migration'lardan önce oluşturulmuş veritabanlarındaki veriler tekrarlanmaz.
+// Kullanıcı oluşturulmaz: ilk yönetici CreateAdmin ile, geliştirme ortamının örnek kullanıcıları
+// SeedDemoData ile eklenir.
+func seedReferenceData(tx *gorm.DB) error {
+	for _, seed := range []struct {
+		model interface{}
+		fn    func(*gorm.DB) error
+	}{
+		{&domain.Language{}, seedLanguages},
+		{&domain.Country{}, seedCountries},
+		{&domain.Unit{}, seedUnits},
+	} {
+		var count int64
+		if err := tx.Model(seed.model).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := seed.fn(tx); err != nil {
+			return err
+		}
+	}
+
+	org, err := findDefaultOrganization(tx)
+	if err != nil {
+		return err
+	}
+	if _, err := seedAdminRole(tx.WithContext(tenant.WithOrganization(tx.Statement.Context, org.ID))); err != nil {
+		return err
+	}
+	log.Println("✓ Reference data seeded")
+	return nil
+}
+
+// SeedDemoData, geliştirme ortamı için örnek kullanıcıları (admin@example.com ve test@example.com)
+// verilen şifre ile varsayılan organizasyona ekler ve örnek yöneticiye sistem yöneticisi rolünü
+// atar. Veritabanında hiç kullanıcı yoksa çalışır. Şifre, uygulamanın şifre politikasını
+// karşılamalıdır. Bilinen şifreli bir yönetici oluşturduğu için sadece açıkça istendiğinde
+// (SEED_DEMO_DATA=true) çağrılmalıdır.
+func SeedDemoData(db *gorm.DB, plainPassword string) error {
+	if err := password.Validate(plainPassword); err != nil {
+		return fmt.Errorf("demo password: %w", err)
+	}
+	hashedPassword, err := password.Hash(plainPassword)
+	if err != nil {
+		return fmt.Errorf("could not hash demo password: %w", err)
 	}
-	log.Println("✓ Database migrated")
+
+	return withLock(db, func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		org, err := findDefaultOrganization(tx)
+		if err != nil {
+			return err
+		}
+		admin, err := seedUsers(tx, org, hashedPassword)
+		if err != nil {
+			return err
+		}
+		orgDB := tx.WithContext(tenant.WithOrganization(tx.Statement.Context, org.ID))
+		adminRole, err := seedAdminRole(orgDB)
+		if err != nil {
+			return err
+		}
+		if err := orgDB.Create(&domain.UserRole{UserID: admin.ID, RoleID: adminRole.ID}).Error; err != nil {
+			return fmt.Errorf("could not assign admin role: %w", err)
+		}
+		log.Println("✓ Demo data seeded")
+		return nil
+	})
 }
 
-func seedData(db *gorm.DB) {
-	seedLanguages(db)
-	seedCountries(db)
-	seedUnits(db)
-	seedUsers(db)
-	log.Println("✓ Data seeded")
+func findDefaultOrganization(tx *gorm.DB) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := tx.Where("slug = ?", domain.DefaultOrganizationSlug).First(&org).Error; err != nil {
+		return nil, fmt.Errorf("could not find default organization: %w", err)
+	}
+	return &org, nil
 }
 
-func seedLanguages(db *gorm.DB) {
+func seedLanguages(db *gorm.DB) error {
 	languages := []domain.Language{
 		{Code: "en", IsActive: true},
 		{Code: "tr", IsActive: true},
 	}
-	db.Create(&languages)
+	if err := db.Create(&languages).Error; err != nil {
+		return fmt.Errorf("could not seed languages: %w", err)
+	}
+	return nil
 }
 
-func seedCountries(db *gorm.DB) {
+func seedCountries(db *gorm.DB) error {
 	countries := []domain.Country{
 		{Code: "AD", Translations: []domain.CountryTranslation{{LanguageCode: "en", Name: "Andorra"}, {LanguageCode: "tr", Name: "Andorra"}}},
 		{Code: "AE", Translations: []domain.CountryTranslation{{LanguageCode: "en", Name: "United Arab Emirates"}, {LanguageCode: "tr", Name: "Birleşik Arap Emirlikleri"}}},
@@ -314,11 +524,12 @@ func seedCountries(db *gorm.DB) {
 		{Code: "ZW", Translations: []domain.CountryTranslation{{LanguageCode: "en", Name: "Zimbabwe"}, {LanguageCode: "tr", Name: "Zimbabve"}}},
 	}
 	if err := db.Create(&countries).Error; err != nil {
-		log.Fatalf("Could not seed countries: %v", err)
+		return fmt.Errorf("could not seed countries: %w", err)
 	}
+	return nil
 }
 
-func seedUnits(db *gorm.DB) {
+func seedUnits(db *gorm.DB) error {
 	units := []domain.Unit{
 		{
 			Code: "C62",
@@ -371,23 +582,53 @@ func seedUnits(db *gorm.DB) {
 		},
 	}
 	if err := db.Create(&units).Error; err != nil {
-		log.Printf("could not seed units: %v", err)
+		return fmt.Errorf("could not seed units: %w", err)
 	}
+	return nil
 }
 
-func seedUsers(db *gorm.DB) {
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+// seedUsers, örnek kullanıcıları verilen şifre özeti ile oluşturur, varsayılan organizasyona üye
+// yapar ve örnek yöneticiyi döner.
+func seedUsers(db *gorm.DB, org *domain.Organization, hashedPassword string) (*domain.User, error) {
+	verifiedAt := time.Now()
 	users := []domain.User{
 		{
-			Name:         "Admin User",
-			Email:        "admin@example.com",
-			PasswordHash: string(hashedPassword),
+			Name:            "Admin User",
+			Email:           "admin@example.com",
+			PasswordHash:    hashedPassword,
+			EmailVerifiedAt: &verifiedAt,
 		},
 		{
-			Name:         "Test User",
-			Email:        "test@example.com",
-			PasswordHash: string(hashedPassword),
+			Name:            "Test User",
+			Email:           "test@example.com",
+			PasswordHash:    hashedPassword,
+			EmailVerifiedAt: &verifiedAt,
 		},
 	}
-	db.Create(&users)
+	if err := db.Create(&users).Error; err != nil {
+		return nil, fmt.Errorf("could not seed users: %w", err)
+	}
+
+	members := make([]domain.OrganizationMember, 0, len(users))
+	for _, user := range users {
+		members = append(members, domain.OrganizationMember{OrganizationID: org.ID, UserID: user.ID})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error; err != nil {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+// seedAdminRole, db'nin organizasyonunda sistem yöneticisi rolünü oluşturur; rol zaten varsa onu döner.
+func seedAdminRole(db *gorm.DB) (*domain.Role, error) {
+	adminRole := domain.Role{
+		Name:        domain.SystemAdminRole,
+		Description: "System administrator with every permission",
+		IsSystem:    true,
+		Permissions: []domain.RolePermission{{Permission: domain.PermissionWildcard}},
+	}
+	if err := db.Where(domain.Role{Name: adminRole.Name}).FirstOrCreate(&adminRole).Error; err != nil {
+		return nil, fmt.Errorf("could not seed admin role: %w", err)
+	}
+	return &adminRole, nil
 }
@@ -0,0 +1,211 @@
+// Package migration, veritabanı şemasını sıralı ve sürümlü migration'lar ile yönetir. Uygulanan
+// sürümler schema_migrations tablosunda tutulur; her açılışta sadece henüz uygulanmamış
+// migration'lar çalışır ve mevcut veriye dokunulmaz.
+//
+// Yeni bir şema değişikliği için migrations listesinin sonuna bir sonraki sürüm numarasıyla yeni
+// bir Migration eklenir; uygulanmış bir migration sonradan değiştirilmez. Şema değişiklikleri domain
+// modellerinden (AutoMigrate) değil sabit DDL ile yazılır; böylece bir migration, modeller
+// değiştikten sonra da ilk uygulandığı şemayı üretir. Sürümlü migration'lardan önce oluşturulmuş
+// veritabanları için ifadeler IF NOT EXISTS ile yazılır.
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/tenant"
+)
+
+// lockID, migration'ları çalıştıran süreçlerin aldığı PostgreSQL advisory lock anahtarıdır.
+// API ve worker aynı anda açılırsa biri diğerinin migration'larını bitirmesini bekler.
+const lockID int64 = 7_468_735_001
+
+// ErrIrreversible, Down adımı olmayan bir migration geri alınmak istendiğinde döner.
+var ErrIrreversible = errors.New("migration is irreversible")
+
+// Migration, şemada yapılan tek bir sürümlü değişikliktir. Up ve Down, diğer bekleyen
+// migration'larla birlikte aynı transaction'da çalışır; hata durumunda hiçbiri uygulanmaz.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // nil ise migration geri alınamaz
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// schemaMigration, schema_migrations tablosundaki uygulanmış bir migration kaydıdır.
+type schemaMigration struct {
+	Version   int       `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;size:128;not null"`
+	AppliedAt time.Time `gorm:"column:applied_at;not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status, bir migration'ın uygulanıp uygulanmadığını gösterir.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrate, henüz uygulanmamış tüm migration'ları sırayla uygular. Hiçbir tabloyu silmez.
+func Migrate(db *gorm.DB) error {
+	return withLock(db, func(tx *gorm.DB) error {
+		return applyPending(tx)
+	})
+}
+
+// Rollback, en son uygulanan steps adet migration'ı yeni olandan eskiye doğru geri alır.
+func Rollback(db *gorm.DB, steps int) error {
+	return withLock(db, func(tx *gorm.DB) error {
+		applied, err := appliedVersions(tx)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return fmt.Errorf("%s: %w", m, ErrIrreversible)
+			}
+			if err := m.Down(tx); err != nil {
+				return fmt.Errorf("%s down: %w", m, err)
+			}
+			if err := tx.Delete(&schemaMigration{}, "version = ?", m.Version).Error; err != nil {
+				return err
+			}
+			log.Printf("✓ Migration %s rolled back", m)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Reset, bütün migration'ları yeni olandan eskiye doğru geri alıp şemayı baştan oluşturur. Down
+// adımları kayıtlı sürümlerden bağımsız çalıştırılır; sürümlü migration'lardan önce oluşturulmuş
+// tablolar da silinir. Bütün veri kaybolur; sadece geliştirme ortamında kullanılmalıdır
+// (bkz. DB_RESET_ON_START).
+func Reset(db *gorm.DB) error {
+	return withLock(db, func(tx *gorm.DB) error {
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if m.Down == nil {
+				return fmt.Errorf("%s: %w", m, ErrIrreversible)
+			}
+			if err := m.Down(tx); err != nil {
+				return fmt.Errorf("%s down: %w", m, err)
+			}
+		}
+		if err := tx.Migrator().DropTable(&schemaMigration{}); err != nil {
+			return err
+		}
+		log.Println("✓ Tables dropped")
+		if err := tx.AutoMigrate(&schemaMigration{}); err != nil {
+			return err
+		}
+		return applyPending(tx)
+	})
+}
+
+// Statuses, tanımlı tüm migration'ları uygulanma zamanları ile birlikte döner.
+func Statuses(db *gorm.DB) ([]Status, error) {
+	var statuses []Status
+	err := withLock(db, func(tx *gorm.DB) error {
+		applied, err := appliedVersions(tx)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			s := Status{Version: m.Version, Name: m.Name}
+			if record, ok := applied[m.Version]; ok {
+				s.AppliedAt = &record.AppliedAt
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock, fn'i advisory lock alınmış tek bir transaction'da çalıştırır. Lock transaction ile
+// birlikte bırakılır. Migration'lar bütün organizasyonlarda çalışan bir sistem süreci olduğu için
+// kiracı kısıtlaması kaldırılır; kiracı tablolarına yazan adımlar organizasyonu açıkça belirtir.
+func withLock(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if err := validate(migrations); err != nil {
+		return err
+	}
+	db = db.WithContext(tenant.WithoutScope(context.Background()))
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return fmt.Errorf("could not acquire migration lock: %w", err)
+		}
+		if err := tx.AutoMigrate(&schemaMigration{}); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+func applyPending(tx *gorm.DB) error {
+	applied, err := appliedVersions(tx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := m.Up(tx); err != nil {
+			return fmt.Errorf("%s: %w", m, err)
+		}
+		if err := tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+		log.Printf("✓ Migration %s applied", m)
+		pending++
+	}
+	if pending == 0 {
+		log.Println("✓ Database schema is up to date")
+	}
+	return nil
+}
+
+func appliedVersions(tx *gorm.DB) (map[int]schemaMigration, error) {
+	var records []schemaMigration
+	if err := tx.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// validate, migration sürümlerinin pozitif ve kesin artan sırada olduğunu doğrular.
+func validate(list []Migration) error {
+	last := 0
+	for _, m := range list {
+		if m.Version <= last {
+			return fmt.Errorf("migration %s is out of order", m)
+		}
+		if m.Up == nil {
+			return fmt.Errorf("migration %s has no up step", m)
+		}
+		last = m.Version
+	}
+	return nil
+}
@@ -22,6 +22,22 @@ func Get(lang, key string) string {
 	return localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: key})
 }
 
+// GetWithData, şablon değişkenleri ({{.Field}} gibi) içeren bir mesajı yerelleştirir.
+func GetWithData(lang, key string, data map[string]interface{}) string {
+	localizer := i18n.NewLocalizer(bundle, lang)
+	return localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: key, TemplateData: data})
+}
+
+// Lookup, GetWithData gibi çalışır ancak anahtar tanımlı değilse panik yerine false döner.
+func Lookup(lang, key string, data map[string]interface{}) (string, bool) {
+	localizer := i18n.NewLocalizer(bundle, lang)
+	msg, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: key, TemplateData: data})
+	if err != nil {
+		return "", false
+	}
+	return msg, true
+}
+
 func Middleware(c *fiber.Ctx) error {
 	lang := c.Query("lang")
 	if lang == "" {
@@ -15,6 +15,14 @@ type Metrics struct {
 	PermissionChecksTotal *prometheus.CounterVec
 	ValidationErrorsTotal *prometheus.CounterVec
 	DatabaseErrorsTotal   prometheus.Counter
+	AuthFailuresTotal     *prometheus.CounterVec
+	AuthLockoutsTotal     *prometheus.CounterVec
+	LoginAttemptsTotal    *prometheus.CounterVec
+	APIKeyAuthTotal       *prometheus.CounterVec
+	OIDCLoginsTotal       *prometheus.CounterVec
+	// Yetki önbelleği: result = "hit_local", "hit_redis" veya "miss"
+	PermissionCacheRequestsTotal      *prometheus.CounterVec
+	PermissionCacheInvalidationsTotal *prometheus.CounterVec
 }
 
 var M *Metrics
@@ -51,6 +59,34 @@ func Init() {
 		DatabaseErrorsTotal: prometheus.NewCounter(
 			prometheus.CounterOpts{Name: "database_errors_total", Help: "Total database errors"},
 		),
+		AuthFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "auth_failures_total", Help: "Total failed authentication attempts tracked by rate limiters"},
+			[]string{"scope"},
+		),
+		AuthLockoutsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "auth_lockouts_total", Help: "Total lockouts triggered by repeated authentication failures"},
+			[]string{"scope"},
+		),
+		LoginAttemptsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "login_attempts_total", Help: "Total password login attempts by result"},
+			[]string{"result"},
+		),
+		APIKeyAuthTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "api_key_auth_total", Help: "Total API key authentications by result"},
+			[]string{"result"},
+		),
+		OIDCLoginsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "oidc_logins_total", Help: "Total OIDC logins by provider and result"},
+			[]string{"provider", "result"},
+		),
+		PermissionCacheRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "permission_cache_requests_total", Help: "Total permission cache lookups by result"},
+			[]string{"result"},
+		),
+		PermissionCacheInvalidationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{Name: "permission_cache_invalidations_total", Help: "Total permission cache invalidations by scope and origin"},
+			[]string{"scope", "origin"},
+		),
 	}
 
 	prometheus.MustRegister(M.HttpRequestsTotal)
@@ -61,6 +97,13 @@ func Init() {
 	prometheus.MustRegister(M.PermissionChecksTotal)
 	prometheus.MustRegister(M.ValidationErrorsTotal)
 	prometheus.MustRegister(M.DatabaseErrorsTotal)
+	prometheus.MustRegister(M.AuthFailuresTotal)
+	prometheus.MustRegister(M.AuthLockoutsTotal)
+	prometheus.MustRegister(M.LoginAttemptsTotal)
+	prometheus.MustRegister(M.APIKeyAuthTotal)
+	prometheus.MustRegister(M.OIDCLoginsTotal)
+	prometheus.MustRegister(M.PermissionCacheRequestsTotal)
+	prometheus.MustRegister(M.PermissionCacheInvalidationsTotal)
 
 	log.Println("✓ Prometheus metrics initialized")
 }
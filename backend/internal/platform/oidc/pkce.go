@@ -0,0 +1,37 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// randomString, URL'de güvenle taşınabilen n byte'lık rastgele bir değer üretir.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// NewState, CSRF koruması için tek kullanımlık "state" değeri üretir.
+func NewState() (string, error) {
+	return randomString(32)
+}
+
+// NewNonce, ID token'ın bu login isteğine ait olduğunu doğrulamak için "nonce" üretir.
+func NewNonce() (string, error) {
+	return randomString(32)
+}
+
+// NewCodeVerifier, RFC 7636'ya uygun (43 karakter) bir PKCE code verifier üretir.
+func NewCodeVerifier() (string, error) {
+	return randomString(32)
+}
+
+// CodeChallengeS256, code verifier'ın S256 yöntemiyle hesaplanan code challenge'ıdır.
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,270 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidIDToken, ID token'ın imzası, issuer'ı, audience'ı, süresi veya nonce'u
+// doğrulanamadığında döner.
+var ErrInvalidIDToken = errors.New("oidc: invalid id token")
+
+// idTokenLeeway, sağlayıcı ile aramızdaki saat farkı için tanınan paydır.
+const idTokenLeeway = time.Minute
+
+// ProviderConfig, tek bir kimlik sağlayıcının (IdP) ayarlarıdır.
+type ProviderConfig struct {
+	Name         string // URL'de kullanılan kısa ad (örn. "corp")
+	IssuerURL    string // Discovery için kullanılan issuer; ID token'daki "iss" ile birebir eşleşmelidir
+	ClientID     string
+	ClientSecret string   // Public client'larda boş olabilir; PKCE her durumda kullanılır
+	RedirectURL  string   // Sağlayıcıya kayıtlı callback adresi
+	Scopes       []string // Boşsa "openid email profile"
+	AutoCreate   bool     // Eşleşen kullanıcı yoksa just-in-time kullanıcı oluşturulur
+}
+
+// Metadata, "/.well-known/openid-configuration" belgesinin kullandığımız alanlarıdır.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// IDTokenClaims, ID token'dan kullanıcıyı eşleştirmek için okunan claim'lerdir.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string       `json:"nonce"`
+	AuthorizedBy  string       `json:"azp"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+}
+
+// flexibleBool, bazı sağlayıcıların email_verified'ı "true" string'i olarak göndermesini tolere eder.
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// Provider, bir OIDC sağlayıcısı ile authorization code + PKCE akışını yürütür.
+// Metadata ilk kullanımda keşfedilir; böylece sağlayıcıya erişilemese bile uygulama açılır.
+type Provider struct {
+	cfg        ProviderConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     *remoteKeySet
+}
+
+// NewProvider, yeni bir sağlayıcı oluşturur. httpClient nil ise zaman aşımlı varsayılan istemci kullanılır.
+func NewProvider(cfg ProviderConfig, httpClient *http.Client) (*Provider, error) {
+	if cfg.Name == "" || cfg.IssuerURL == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc: provider %q requires issuer, client id and redirect url", cfg.Name)
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, httpClient: httpClient}, nil
+}
+
+// Name, sağlayıcının kısa adını döner.
+func (p *Provider) Name() string { return p.cfg.Name }
+
+// AutoCreate, eşleşen kullanıcı yoksa just-in-time kullanıcı oluşturulup oluşturulmayacağını döner.
+func (p *Provider) AutoCreate() bool { return p.cfg.AutoCreate }
+
+// Discover, sağlayıcının metadata'sını indirir ve önbelleğe alır.
+func (p *Provider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	var md Metadata
+	if err := getJSON(ctx, p.httpClient, wellKnown, &md); err != nil {
+		return nil, fmt.Errorf("oidc: discovery failed for %q: %w", p.cfg.Name, err)
+	}
+	// Spec gereği belgedeki issuer, yapılandırılan issuer ile birebir aynı olmalıdır.
+	if md.Issuer != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("oidc: issuer mismatch for %q: expected %q, got %q", p.cfg.Name, p.cfg.IssuerURL, md.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: incomplete discovery document for %q", p.cfg.Name)
+	}
+
+	p.metadata = &md
+	p.keys = newRemoteKeySet(md.JWKSURI, p.httpClient)
+	return p.metadata, nil
+}
+
+// AuthCodeURL, kullanıcının yönlendirileceği authorization endpoint adresini üretir.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	md, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallengeS256(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return md.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange, authorization code'u token endpoint'inde ID token ile değiştirir ve
+// ID token'ı doğrulayarak claim'lerini döner.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IDTokenClaims, error) {
+	md, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("oidc: could not decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.Error != "" {
+		return nil, fmt.Errorf("oidc: token endpoint returned %d: %s %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrInvalidIDToken)
+	}
+
+	return p.VerifyIDToken(ctx, tokenResp.IDToken, nonce)
+}
+
+// VerifyIDToken, ID token'ın imzasını sağlayıcının JWKS'i ile, claim'lerini ise
+// OIDC Core 3.1.3.7'ye göre (iss, aud, azp, exp, iat, nonce) doğrular.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDTokenClaims, error) {
+	md, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.keys.key(ctx, kid)
+		},
+		// "none" ve HMAC algoritmaları kabul edilmez; client secret ile imzalanmış token'lara güvenilmez.
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(md.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: azp does not match client id", ErrInvalidIDToken)
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// Registry, yapılandırılmış sağlayıcıları adlarına göre tutar.
+type Registry struct {
+	providers map[string]*Provider
+	names     []string
+}
+
+// NewRegistry, yapılandırmalardan sağlayıcıları oluşturur.
+func NewRegistry(configs []ProviderConfig, httpClient *http.Client) (*Registry, error) {
+	r := &Registry{providers: make(map[string]*Provider, len(configs))}
+	for _, cfg := range configs {
+		if _, exists := r.providers[cfg.Name]; exists {
+			return nil, fmt.Errorf("oidc: duplicate provider %q", cfg.Name)
+		}
+		provider, err := NewProvider(cfg, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		r.providers[cfg.Name] = provider
+		r.names = append(r.names, cfg.Name)
+	}
+	return r, nil
+}
+
+// Get, ada göre sağlayıcıyı döner.
+func (r *Registry) Get(name string) (*Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names, yapılandırılmış sağlayıcıların adlarını yapılandırma sırasıyla döner.
+func (r *Registry) Names() []string {
+	return append([]string(nil), r.names...)
+}
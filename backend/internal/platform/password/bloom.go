@@ -0,0 +1,64 @@
+package password
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter, sızdırılmış şifre listesinin bellekte kompakt tutulması için kullanılır.
+// Listede olmayan bir şifre düşük bir olasılıkla (falsePositiveRate) listede sanılabilir;
+// listedeki bir şifre ise asla kaçırılmaz.
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64 // bit sayısı
+	hashes uint64 // hash fonksiyonu sayısı
+}
+
+// newBloomFilter, n eleman ve p yanlış pozitif oranı için optimum boyutta bir filtre oluşturur.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: k,
+	}
+}
+
+// locations, Kirsch-Mitzenmacher yöntemiyle tek bir 128-bit hash'ten k bit konumu türetir.
+func (f *bloomFilter) locations(value string, fn func(uint64)) {
+	h := fnv.New128a()
+	h.Write([]byte(value))
+	sum := h.Sum(nil)
+
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[8+i])
+	}
+	for i := uint64(0); i < f.hashes; i++ {
+		fn((h1 + i*h2) % f.m)
+	}
+}
+
+func (f *bloomFilter) Add(value string) {
+	f.locations(value, func(loc uint64) {
+		f.bits[loc/64] |= 1 << (loc % 64)
+	})
+}
+
+func (f *bloomFilter) Contains(value string) bool {
+	found := true
+	f.locations(value, func(loc uint64) {
+		if f.bits[loc/64]&(1<<(loc%64)) == 0 {
+			found = false
+		}
+	})
+	return found
+}
@@ -0,0 +1,293 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Desteklenen algoritmalar. Değerler PASSWORD_HASH_ALGORITHM ile seçilir.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("password: unknown hash algorithm")
+	ErrMalformedHash    = errors.New("password: malformed hash")
+)
+
+// IAlgorithm, tek bir şifre özetleme algoritmasıdır. Üretilen özetler algoritmayı ve
+// parametrelerini kendi içinde taşır (PHC / modular crypt formatı).
+type IAlgorithm interface {
+	Name() string
+	// Identifies, özetin bu algoritma ile üretilip üretilmediğini söyler.
+	Identifies(encoded string) bool
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	// Outdated, özetin güncel parametrelerden farklı parametrelerle üretildiğini söyler.
+	Outdated(encoded string) bool
+}
+
+// IHasher, yeni şifreleri tercih edilen algoritma ile özetler; doğrulamada ise
+// kayıtlı tüm algoritmaları tanır.
+type IHasher interface {
+	Hash(password string) (string, error)
+	// Verify, şifre doğruysa true döner. needsRehash, özetin eski bir algoritma veya
+	// parametre ile üretildiğini ve yeniden özetlenip kaydedilmesi gerektiğini bildirir.
+	Verify(password, encoded string) (ok bool, needsRehash bool, err error)
+}
+
+// Argon2idParams, argon2id maliyet parametreleridir.
+type Argon2idParams struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// HasherConfig, uygulamanın kullanacağı hasher'ı tanımlar.
+type HasherConfig struct {
+	Algorithm  string // Yeni özetler için kullanılacak algoritma
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+// DefaultHasherConfig, ortam değişkeni verilmediğinde kullanılan ayarlardır (RFC 9106'daki
+// düşük bellekli öneriye yakın, 64 MiB).
+func DefaultHasherConfig() HasherConfig {
+	return HasherConfig{
+		Algorithm: AlgorithmArgon2id,
+		Argon2id: Argon2idParams{
+			Memory:      64 * 1024,
+			Iterations:  3,
+			Parallelism: 2,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		BcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+// Hasher, IHasher'ın varsayılan uygulamasıdır.
+type Hasher struct {
+	preferred  IAlgorithm
+	algorithms []IAlgorithm
+}
+
+var hasher IHasher = mustDefaultHasher()
+
+// dummyHash, VerifyDummy'nin kullandığı özettir. Güncel hasher'ın maliyetiyle üretilmesi için
+// ilk kullanımda oluşturulur.
+var (
+	dummyMu   sync.Mutex
+	dummyHash string
+)
+
+// SetHasher, uygulama genelinde kullanılacak hasher'ı ayarlar.
+func SetHasher(h IHasher) {
+	hasher = h
+	dummyMu.Lock()
+	dummyHash = ""
+	dummyMu.Unlock()
+}
+
+// Hash, şifreyi uygulama genelindeki hasher ile özetler.
+func Hash(password string) (string, error) {
+	return hasher.Hash(password)
+}
+
+// Verify, şifreyi uygulama genelindeki hasher ile doğrular.
+func Verify(password, encoded string) (bool, bool, error) {
+	return hasher.Verify(password, encoded)
+}
+
+// VerifyDummy, şifreyi hiçbir hesaba ait olmayan sabit bir özete karşı doğrular ve sonucu atar.
+// Kullanıcı bulunamadığında çağrılır; böylece yanıt süresi gerçek bir doğrulamayla aynı olur ve
+// e-postanın kayıtlı olup olmadığı süre farkından anlaşılamaz.
+func VerifyDummy(password string) {
+	dummyMu.Lock()
+	if dummyHash == "" {
+		if h, err := hasher.Hash("ths-erp-dummy-password"); err == nil {
+			dummyHash = h
+		}
+	}
+	encoded := dummyHash
+	dummyMu.Unlock()
+
+	_, _, _ = hasher.Verify(password, encoded)
+}
+
+func mustDefaultHasher() IHasher {
+	h, err := NewHasher(DefaultHasherConfig())
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// NewHasher, cfg.Algorithm'ı tercih eden ve diğer desteklenen algoritmaları sadece
+// doğrulama için kullanan bir hasher oluşturur.
+func NewHasher(cfg HasherConfig) (*Hasher, error) {
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("password: bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	p := cfg.Argon2id
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength == 0 || p.KeyLength == 0 {
+		return nil, errors.New("password: argon2id parameters must be positive")
+	}
+
+	algorithms := []IAlgorithm{
+		&argon2idAlgorithm{params: p},
+		&bcryptAlgorithm{cost: cfg.BcryptCost},
+	}
+	for _, a := range algorithms {
+		if a.Name() == cfg.Algorithm {
+			return &Hasher{preferred: a, algorithms: algorithms}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, cfg.Algorithm)
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+func (h *Hasher) Verify(password, encoded string) (bool, bool, error) {
+	for _, a := range h.algorithms {
+		if !a.Identifies(encoded) {
+			continue
+		}
+		ok, err := a.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, a != h.preferred || a.Outdated(encoded), nil
+	}
+	return false, false, ErrUnknownAlgorithm
+}
+
+// argon2idAlgorithm, özetleri "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>" biçiminde saklar.
+type argon2idAlgorithm struct {
+	params Argon2idParams
+}
+
+type argon2idHash struct {
+	version int
+	params  Argon2idParams
+	salt    []byte
+	key     []byte
+}
+
+func (a *argon2idAlgorithm) Name() string { return AlgorithmArgon2id }
+
+func (a *argon2idAlgorithm) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (a *argon2idAlgorithm) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.params.Memory, a.params.Iterations, a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a *argon2idAlgorithm) Verify(password, encoded string) (bool, error) {
+	h, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	if h.version != argon2.Version {
+		return false, ErrMalformedHash
+	}
+	// Doğrulama, özette kayıtlı parametrelerle yapılır; güncel ayarlar değişmiş olabilir.
+	key := argon2.IDKey([]byte(password), h.salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return subtle.ConstantTimeCompare(key, h.key) == 1, nil
+}
+
+func (a *argon2idAlgorithm) Outdated(encoded string) bool {
+	h, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return h.version != argon2.Version || h.params != a.params
+}
+
+func decodeArgon2id(encoded string) (*argon2idHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return nil, ErrMalformedHash
+	}
+
+	h := &argon2idHash{}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &h.version); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.params.Memory, &h.params.Iterations, &h.params.Parallelism); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if h.params.Iterations == 0 || h.params.Parallelism == 0 {
+		return nil, ErrMalformedHash
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrMalformedHash
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, ErrMalformedHash
+	}
+	h.params.SaltLength = uint32(len(h.salt))
+	h.params.KeyLength = uint32(len(h.key))
+	return h, nil
+}
+
+// bcryptAlgorithm, mevcut "$2a$"/"$2b$"/"$2y$" özetlerini doğrulamak için tutulur.
+type bcryptAlgorithm struct {
+	cost int
+}
+
+func (a *bcryptAlgorithm) Name() string { return AlgorithmBcrypt }
+
+func (a *bcryptAlgorithm) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (a *bcryptAlgorithm) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), a.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (a *bcryptAlgorithm) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *bcryptAlgorithm) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != a.cost
+}
@@ -0,0 +1,196 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"ths-erp.com/internal/apperrors"
+)
+
+//go:embed common_passwords.txt
+var bundledList []byte
+
+// falsePositiveRate, bloom filtresinin güçlü bir şifreyi yanlışlıkla reddetme olasılığıdır.
+const falsePositiveRate = 0.0001
+
+// Politika kuralları. Değerler, web.ValidationErrors tarafından "validation_<kural>"
+// i18n anahtarlarıyla yerelleştirilir.
+const (
+	RuleMinLength = "min_length"
+	RuleMaxBytes  = "max_bytes"
+	RuleUpper     = "uppercase"
+	RuleLower     = "lowercase"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleBreached  = "breached"
+)
+
+// Policy, yeni belirlenen şifrelerin uyması gereken kuralları tanımlar.
+type Policy struct {
+	MinLength     int    // Karakter (rune) cinsinden en kısa uzunluk
+	MaxBytes      int    // Byte cinsinden en uzun uzunluk (bcrypt 72 byte'tan sonrasını yok sayar)
+	RequireUpper  bool   // En az bir büyük harf
+	RequireLower  bool   // En az bir küçük harf
+	RequireDigit  bool   // En az bir rakam
+	RequireSymbol bool   // En az bir harf/rakam dışı karakter
+	BlocklistFile string // Gömülü listeye eklenecek, satır başına bir şifre içeren dosya
+}
+
+// Violation, tek bir politika ihlalidir. Field/Tag/Param metotları web.FieldError
+// arayüzünü karşılar; böylece validator hatalarıyla aynı şekilde yerelleştirilir.
+type Violation struct {
+	Rule  string
+	Value string
+}
+
+func (v Violation) Field() string { return "password" }
+func (v Violation) Tag() string   { return v.Rule }
+func (v Violation) Param() string { return v.Value }
+
+// PolicyError, şifrenin bir veya daha fazla kuralı ihlal ettiğini bildirir.
+// errors.Is ile apperrors.ErrWeakPassword olarak da yakalanabilir.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return fmt.Sprintf("%s: %s", apperrors.ErrWeakPassword.Error(), strings.Join(rules, ", "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return apperrors.ErrWeakPassword
+}
+
+// Checker, politikayı ve sızdırılmış şifre filtresini bir arada tutar.
+type Checker struct {
+	policy   Policy
+	breached *bloomFilter
+}
+
+var checker = mustDefaultChecker()
+
+// SetChecker, uygulama genelinde kullanılacak şifre denetleyicisini ayarlar.
+func SetChecker(c *Checker) {
+	checker = c
+}
+
+// Validate, şifreyi uygulama genelindeki politikaya göre kontrol eder.
+func Validate(password string) error {
+	return checker.Validate(password)
+}
+
+// DefaultPolicy, ortam değişkeni verilmediğinde kullanılan politikadır.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:    8,
+		MaxBytes:     72,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+}
+
+func mustDefaultChecker() *Checker {
+	c, err := NewChecker(DefaultPolicy())
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewChecker, gömülü listeyi ve varsa BlocklistFile'ı okuyarak bir denetleyici oluşturur.
+func NewChecker(policy Policy) (*Checker, error) {
+	entries := readList(bytes.NewReader(bundledList))
+
+	if policy.BlocklistFile != "" {
+		f, err := os.Open(policy.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not open password blocklist: %w", err)
+		}
+		defer f.Close()
+		entries = append(entries, readList(f)...)
+		log.Printf("✓ Password blocklist loaded from %s", policy.BlocklistFile)
+	}
+
+	filter := newBloomFilter(len(entries), falsePositiveRate)
+	for _, entry := range entries {
+		filter.Add(entry)
+	}
+
+	return &Checker{policy: policy, breached: filter}, nil
+}
+
+// readList, satır başına bir şifre içeren listeyi okur; boş ve '#' ile başlayan satırlar atlanır.
+func readList(r io.Reader) []string {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, strings.ToLower(line))
+	}
+	return entries
+}
+
+// Validate, şifrenin tüm kuralları karşılayıp karşılamadığını kontrol eder ve
+// ihlal edilen tüm kuralları tek bir PolicyError içinde döner.
+func (c *Checker) Validate(password string) error {
+	var violations []Violation
+
+	if utf8.RuneCountInString(password) < c.policy.MinLength {
+		violations = append(violations, Violation{Rule: RuleMinLength, Value: strconv.Itoa(c.policy.MinLength)})
+	}
+	if c.policy.MaxBytes > 0 && len(password) > c.policy.MaxBytes {
+		violations = append(violations, Violation{Rule: RuleMaxBytes, Value: strconv.Itoa(c.policy.MaxBytes)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if c.policy.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{Rule: RuleUpper})
+	}
+	if c.policy.RequireLower && !hasLower {
+		violations = append(violations, Violation{Rule: RuleLower})
+	}
+	if c.policy.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Rule: RuleDigit})
+	}
+	if c.policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Rule: RuleSymbol})
+	}
+
+	if c.breached.Contains(strings.ToLower(password)) {
+		violations = append(violations, Violation{Rule: RuleBreached})
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
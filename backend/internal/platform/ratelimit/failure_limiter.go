@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+// IFailureLimiter, bir anahtar (örn. kullanıcı ID'si) için başarısız denemeleri sayar
+// ve eşik aşıldığında üstel olarak artan süreli kilit uygular.
+type IFailureLimiter interface {
+	// Check, anahtar kilitliyse kalan kilit süresini döner; kilitli değilse 0 döner.
+	Check(ctx context.Context, key string) (time.Duration, error)
+	// RegisterFailure, başarısız bir denemeyi kaydeder. Bu deneme kilidi tetiklediyse kilit süresini döner.
+	RegisterFailure(ctx context.Context, key string) (time.Duration, error)
+	// Reset, başarılı bir denemeden sonra sayaçları sıfırlar.
+	Reset(ctx context.Context, key string) error
+}
+
+// FailureLimiterConfig, limiter davranışını belirler.
+type FailureLimiterConfig struct {
+	Scope       string        // Redis anahtar öneki ve metrik etiketi, örn. "2fa"
+	MaxAttempts int           // Kilitlenmeden önce izin verilen art arda hatalı deneme sayısı
+	Window      time.Duration // Hatalı denemelerin sayıldığı süre
+	BaseLockout time.Duration // İlk kilit süresi; sonraki her kilitte iki katına çıkar
+	MaxLockout  time.Duration // Kilit süresinin üst sınırı
+}
+
+// RedisFailureLimiter, IFailureLimiter'ın Redis implementasyonudur.
+// Sayaçlar Redis'te tutulduğu için limit tüm API instance'ları arasında paylaşılır.
+type RedisFailureLimiter struct {
+	client *redis.Client
+	cfg    FailureLimiterConfig
+}
+
+func NewRedisFailureLimiter(client *redis.Client, cfg FailureLimiterConfig) IFailureLimiter {
+	return &RedisFailureLimiter{client: client, cfg: cfg}
+}
+
+func (l *RedisFailureLimiter) failuresKey(key string) string {
+	return fmt.Sprintf("ratelimit:%s:failures:%s", l.cfg.Scope, key)
+}
+
+func (l *RedisFailureLimiter) lockKey(key string) string {
+	return fmt.Sprintf("ratelimit:%s:lock:%s", l.cfg.Scope, key)
+}
+
+func (l *RedisFailureLimiter) levelKey(key string) string {
+	return fmt.Sprintf("ratelimit:%s:level:%s", l.cfg.Scope, key)
+}
+
+func (l *RedisFailureLimiter) Check(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := l.client.PTTL(ctx, l.lockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (l *RedisFailureLimiter) RegisterFailure(ctx context.Context, key string) (time.Duration, error) {
+	metrics.M.AuthFailuresTotal.WithLabelValues(l.cfg.Scope).Inc()
+
+	pipe := l.client.TxPipeline()
+	incr := pipe.Incr(ctx, l.failuresKey(key))
+	pipe.PExpire(ctx, l.failuresKey(key), l.cfg.Window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	if incr.Val() < int64(l.cfg.MaxAttempts) {
+		return 0, nil
+	}
+
+	// Kilit seviyesi, art arda kaç kez kilitlenildiğini tutar ve süreyi üstel olarak artırır.
+	// Seviye, en uzun kilit süresi boyunca yeni bir kilit olmazsa sıfırlanır.
+	level, err := l.client.Incr(ctx, l.levelKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	lockout := l.lockoutFor(level)
+
+	pipe = l.client.TxPipeline()
+	pipe.PExpire(ctx, l.levelKey(key), lockout+l.cfg.MaxLockout)
+	pipe.Set(ctx, l.lockKey(key), level, lockout)
+	pipe.Del(ctx, l.failuresKey(key))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	metrics.M.AuthLockoutsTotal.WithLabelValues(l.cfg.Scope).Inc()
+	return lockout, nil
+}
+
+func (l *RedisFailureLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, l.failuresKey(key), l.levelKey(key)).Err()
+}
+
+// lockoutFor, verilen kilit seviyesi için BaseLockout * 2^(level-1) süresini hesaplar.
+func (l *RedisFailureLimiter) lockoutFor(level int64) time.Duration {
+	multiplier := math.Pow(2, float64(level-1))
+	lockout := time.Duration(float64(l.cfg.BaseLockout) * multiplier)
+	if lockout <= 0 || lockout > l.cfg.MaxLockout {
+		return l.cfg.MaxLockout
+	}
+	return lockout
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+// ISlidingWindowLimiter, bir anahtar için son Window süresi içindeki olayları sayar.
+type ISlidingWindowLimiter interface {
+	// Exceeded, pencere içindeki olay sayısı limite ulaştıysa bir sonraki denemeye kadar beklenmesi gereken süreyi döner.
+	Exceeded(ctx context.Context, key string) (time.Duration, error)
+	// Hit, anahtar için yeni bir olay kaydeder.
+	Hit(ctx context.Context, key string) error
+	// Reset, anahtara ait tüm olayları siler.
+	Reset(ctx context.Context, key string) error
+}
+
+// SlidingWindowConfig, limiter davranışını belirler.
+type SlidingWindowConfig struct {
+	Scope  string        // Redis anahtar öneki ve metrik etiketi, örn. "login_ip"
+	Limit  int           // Pencere içinde izin verilen olay sayısı
+	Window time.Duration // Kayan pencerenin uzunluğu
+}
+
+// RedisSlidingWindowLimiter, olayları zaman damgası skoruyla bir Redis sorted set'inde tutar.
+// Sabit pencerelerden farklı olarak pencere sınırında limitin iki katı kadar denemeye izin vermez.
+type RedisSlidingWindowLimiter struct {
+	client *redis.Client
+	cfg    SlidingWindowConfig
+}
+
+func NewRedisSlidingWindowLimiter(client *redis.Client, cfg SlidingWindowConfig) ISlidingWindowLimiter {
+	return &RedisSlidingWindowLimiter{client: client, cfg: cfg}
+}
+
+func (l *RedisSlidingWindowLimiter) key(key string) string {
+	return fmt.Sprintf("ratelimit:%s:window:%s", l.cfg.Scope, key)
+}
+
+func (l *RedisSlidingWindowLimiter) Exceeded(ctx context.Context, key string) (time.Duration, error) {
+	now := time.Now()
+	k := l.key(key)
+
+	pipe := l.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, k, "-inf", strconv.FormatInt(now.Add(-l.cfg.Window).UnixNano(), 10))
+	count := pipe.ZCard(ctx, k)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	excess := count.Val() - int64(l.cfg.Limit)
+	if excess < 0 {
+		return 0, nil
+	}
+
+	// Sayının limitin altına inmesi için en eski (excess+1) olayın pencereden çıkması gerekir.
+	blocking, err := l.client.ZRangeWithScores(ctx, k, excess, excess).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(blocking) == 0 {
+		return 0, nil
+	}
+
+	retryAfter := time.Unix(0, int64(blocking[0].Score)).Add(l.cfg.Window).Sub(now)
+	if retryAfter <= 0 {
+		return 0, nil
+	}
+	return retryAfter, nil
+}
+
+func (l *RedisSlidingWindowLimiter) Hit(ctx context.Context, key string) error {
+	metrics.M.AuthFailuresTotal.WithLabelValues(l.cfg.Scope).Inc()
+
+	now := time.Now()
+	k := l.key(key)
+
+	pipe := l.client.TxPipeline()
+	pipe.ZAdd(ctx, k, &redis.Z{Score: float64(now.UnixNano()), Member: uuid.NewString()})
+	pipe.PExpire(ctx, k, l.cfg.Window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (l *RedisSlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, l.key(key)).Err()
+}
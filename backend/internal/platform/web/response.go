@@ -6,6 +6,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/i18n"
 )
 
 type Response struct {
@@ -153,11 +154,30 @@ func Respond(c *fiber.Ctx, statusCode int, data interface{}, messages ...string)
 	})
 }
 
-func ValidationErrors(c *fiber.Ctx, errs validator.ValidationErrors) error {
+// FieldError, tek bir alan doğrulama hatasını tanımlar. validator.FieldError ve
+// şifre politikası ihlalleri bu arayüzü karşılar.
+type FieldError interface {
+	Field() string
+	Tag() string
+	Param() string
+}
+
+// ValidationErrors, alan hatalarını isteğin diline göre yerelleştirerek döner.
+// Mesajlar "validation_<tag>" anahtarından okunur; anahtar yoksa genel "validation_failed" mesajı kullanılır.
+func ValidationErrors[T FieldError](c *fiber.Ctx, errs []T) error {
+	lang, _ := c.Locals("lang").(string)
+	if lang == "" {
+		lang = "tr"
+	}
+
 	var messages []string
 	for _, err := range errs {
-		// Customize this part to create more user-friendly messages
-		messages = append(messages, "Field '"+err.Field()+"' failed on the '"+err.Tag()+"' tag.")
+		data := map[string]interface{}{"Field": err.Field(), "Tag": err.Tag(), "Param": err.Param()}
+		msg, ok := i18n.Lookup(lang, "validation_"+err.Tag(), data)
+		if !ok {
+			msg = i18n.GetWithData(lang, "validation_failed", data)
+		}
+		messages = append(messages, msg)
 	}
 	return c.Status(fiber.StatusBadRequest).JSON(Response{
 		Success:  false,
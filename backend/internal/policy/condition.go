@@ -0,0 +1,234 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Attributes, özne, kaynak veya ortam özniteliklerini tutar. Değerler JSON'dan gelebileceği için
+// sayılar, metinler, boolean'lar, listeler ve iç içe map'ler desteklenir.
+type Attributes map[string]interface{}
+
+// Request, bir yetkilendirme sorusudur: özne, işlem ("kaynak:işlem") ve kaynak üzerinde.
+// OrganizationID, isteğin yapıldığı organizasyondur; başka organizasyonların politikaları uygulanmaz.
+type Request struct {
+	OrganizationID int        `json:"organizationId,omitempty"`
+	Subject        Attributes `json:"subject"`
+	Action         string     `json:"action"`
+	Resource       Attributes `json:"resource"`
+	Env            Attributes `json:"env"`
+}
+
+// lookup, "subject.id" gibi bir öznitelik yolunun değerini döner. İç içe map'ler noktalarla gezilir.
+func (r *Request) lookup(path string) (interface{}, bool) {
+	if path == "action" {
+		return r.Action, true
+	}
+	root, rest, _ := strings.Cut(path, ".")
+	var attrs Attributes
+	switch root {
+	case "subject":
+		attrs = r.Subject
+	case "resource":
+		attrs = r.Resource
+	case "env":
+		attrs = r.Env
+	default:
+		return nil, false
+	}
+
+	var current interface{} = map[string]interface{}(attrs)
+	for _, part := range strings.Split(rest, ".") {
+		var m map[string]interface{}
+		switch v := current.(type) {
+		case map[string]interface{}:
+			m = v
+		case Attributes:
+			m = v
+		default:
+			return nil, false
+		}
+		value, ok := m[part]
+		if !ok || value == nil {
+			return nil, false
+		}
+		current = value
+	}
+	return current, true
+}
+
+// evaluate, koşulu değerlendirir. trace nil değilse her karşılaştırmanın sonucu okunabilir bir
+// satır olarak eklenir (explain için).
+func (c *Condition) evaluate(req *Request, trace *[]string) bool {
+	switch {
+	case len(c.All) > 0:
+		for i := range c.All {
+			if !c.All[i].evaluate(req, trace) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for i := range c.Any {
+			if c.Any[i].evaluate(req, trace) {
+				return true
+			}
+		}
+		return false
+	case c.Not != nil:
+		result := !c.Not.evaluate(req, trace)
+		if trace != nil {
+			*trace = append(*trace, fmt.Sprintf("not: %t", result))
+		}
+		return result
+	}
+
+	result, detail := c.compare(req)
+	if trace != nil {
+		*trace = append(*trace, fmt.Sprintf("%s: %t", detail, result))
+	}
+	return result
+}
+
+// compare, tek bir karşılaştırmayı yapar. Tanımlı olmayan öznitelikler karşılaştırmayı her zaman
+// başarısız kılar (exists hariç); böylece eksik bir öznitelik bir allow politikasını tetiklemez.
+func (c *Condition) compare(req *Request) (bool, string) {
+	actual, found := req.lookup(c.Attr)
+
+	if c.Op == OpExists {
+		want := true
+		if b, ok := c.Value.(bool); ok {
+			want = b
+		}
+		return found == want, fmt.Sprintf("%s exists (%t)", c.Attr, found)
+	}
+
+	expected, expectedLabel := c.Value, formatValue(c.Value)
+	if c.Ref != "" {
+		var ok bool
+		expected, ok = req.lookup(c.Ref)
+		if !ok {
+			return false, fmt.Sprintf("%s %s %s: %s is not set", c.Attr, c.Op, c.Ref, c.Ref)
+		}
+		expectedLabel = fmt.Sprintf("%s (%s)", c.Ref, formatValue(expected))
+	}
+	if !found {
+		return false, fmt.Sprintf("%s %s %s: %s is not set", c.Attr, c.Op, expectedLabel, c.Attr)
+	}
+
+	detail := fmt.Sprintf("%s (%s) %s %s", c.Attr, formatValue(actual), c.Op, expectedLabel)
+	switch c.Op {
+	case OpEq:
+		return equal(actual, expected), detail
+	case OpNe:
+		return !equal(actual, expected), detail
+	case OpLt, OpLte, OpGt, OpGte:
+		cmp, ok := order(actual, expected)
+		if !ok {
+			return false, detail + ": values are not comparable"
+		}
+		switch c.Op {
+		case OpLt:
+			return cmp < 0, detail
+		case OpLte:
+			return cmp <= 0, detail
+		case OpGt:
+			return cmp > 0, detail
+		default:
+			return cmp >= 0, detail
+		}
+	case OpIn:
+		return contains(expected, actual), detail
+	case OpContains:
+		return contains(actual, expected), detail
+	}
+	return false, detail + ": unknown operator"
+}
+
+// contains, liste içinde değeri veya metin içinde alt metni arar.
+func contains(collection, item interface{}) bool {
+	if s, ok := collection.(string); ok {
+		sub, ok := item.(string)
+		return ok && strings.Contains(s, sub)
+	}
+	v := reflect.ValueOf(collection)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	for i := 0; i < v.Len(); i++ {
+		if equal(v.Index(i).Interface(), item) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal, sayıları türlerinden bağımsız karşılaştırır (JSON'dan float64, route'tan metin gelebilir).
+func equal(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// order, iki sayıyı veya iki metni karşılaştırır.
+func order(a, b interface{}) (int, bool) {
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+	x, okA := a.(string)
+	y, okB := b.(string)
+	if !okA || !okB {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return strconv.Quote(x)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
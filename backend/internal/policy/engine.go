@@ -0,0 +1,187 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	_ "time/tzdata" // POLICY_TIMEZONE, işletim sisteminde zoneinfo olmasa da (alpine imajı) çözülebilsin
+
+	"ths-erp.com/internal/domain"
+)
+
+// Karar kaynakları (Decision.DecidedBy)
+const (
+	DecidedByRBAC    = "rbac"
+	DecidedByDefault = "default"
+	decidedByPolicy  = "policy:"
+)
+
+// Decision, bir yetkilendirme isteğinin sonucudur. Trace sadece explain istendiğinde doldurulur.
+type Decision struct {
+	Allowed   bool          `json:"allowed"`
+	DecidedBy string        `json:"decidedBy"` // "policy:<ad>", "rbac" veya "default"
+	Reason    string        `json:"reason"`
+	RBAC      bool          `json:"rbac"` // Rol/doğrudan yetkiler işlemi kapsıyor mu
+	Trace     []PolicyTrace `json:"trace,omitempty"`
+	Request   *Request      `json:"request,omitempty"` // Değerlendirilen öznitelikler (sadece explain)
+}
+
+// PolicyTrace, explain çıktısında işleme uygulanan bir politikanın değerlendirmesini gösterir.
+type PolicyTrace struct {
+	Policy     string   `json:"policy"`
+	Source     string   `json:"source"`
+	Effect     Effect   `json:"effect"`
+	Matched    bool     `json:"matched"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// Engine, yüklenmiş politikaları tutar ve istekleri değerlendirir. Politikalar çalışma sırasında
+// Load ile atomik olarak değiştirilebilir.
+type Engine struct {
+	location *time.Location
+
+	mu       sync.RWMutex
+	policies []Policy
+}
+
+// NewEngine, boş bir politika motoru oluşturur. location, env.hour ve env.weekday gibi ortam
+// özniteliklerinin hesaplandığı saat dilimidir (örn. mesai saatleri kuralları için).
+func NewEngine(location *time.Location) *Engine {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Engine{location: location}
+}
+
+// Load, politikaları doğrular ve mevcut kümenin yerine koyar. Geçersiz bir politika varsa mevcut
+// küme değişmez; yarım yüklenmiş bir küme erişimi beklenmedik şekilde açabilir veya kapatabilir.
+// Politika adları bir organizasyonda tekildir; farklı organizasyonlar aynı adı kullanabilir, ancak
+// tüm organizasyonlarda geçerli bir politikanın adı başka hiçbir politikada kullanılamaz.
+func (e *Engine) Load(policies []Policy) error {
+	seen := make(map[string][]Policy, len(policies))
+	loaded := make([]Policy, 0, len(policies))
+	for i := range policies {
+		p := policies[i]
+		if err := Validate(&p); err != nil {
+			return err
+		}
+		for _, other := range seen[p.Name] {
+			if p.OrganizationID == 0 || other.OrganizationID == 0 || p.OrganizationID == other.OrganizationID {
+				return fmt.Errorf("%w: duplicate policy name %q (%s, %s)", ErrInvalidPolicy, p.Name, other.Source, p.Source)
+			}
+		}
+		seen[p.Name] = append(seen[p.Name], p)
+		loaded = append(loaded, p)
+	}
+	sort.SliceStable(loaded, func(i, j int) bool { return loaded[i].Name < loaded[j].Name })
+
+	e.mu.Lock()
+	e.policies = loaded
+	e.mu.Unlock()
+	return nil
+}
+
+// Policies, yüklü politikaların bir kopyasını döner.
+func (e *Engine) Policies() []Policy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Policy(nil), e.policies...)
+}
+
+// Env, değerlendirme anındaki ortam özniteliklerini döner: env.time (RFC3339), env.date
+// (YYYY-MM-DD), env.hour (0-23), env.minute ve env.weekday ("monday" ... "sunday").
+// overrides ile değerler eklenebilir veya değiştirilebilir (örn. env.ip ya da dry-run için env.hour).
+func (e *Engine) Env(now time.Time, overrides Attributes) Attributes {
+	local := now.In(e.location)
+	env := Attributes{
+		"time":    local.Format(time.RFC3339),
+		"date":    local.Format("2006-01-02"),
+		"hour":    local.Hour(),
+		"minute":  local.Minute(),
+		"weekday": strings.ToLower(local.Weekday().String()),
+	}
+	for k, v := range overrides {
+		env[k] = v
+	}
+	return env
+}
+
+// Evaluate, isteği yüklü politikalar (ve varsa extra ile verilen ek politikalar) ile değerlendirir.
+// granted, öznenin rollerinden ve doğrudan yetkilerinden gelen yetki kümesidir. Karar sırası için
+// paket açıklamasına bakınız. extra, kaydedilmeden önce bir politikanın etkisini görmek (dry-run)
+// için kullanılır ve doğrulanmış olmalıdır.
+func (e *Engine) Evaluate(req *Request, granted []string, extra []Policy, explain bool) *Decision {
+	e.mu.RLock()
+	policies := e.policies
+	e.mu.RUnlock()
+	if len(extra) > 0 {
+		policies = append(append([]Policy(nil), policies...), extra...)
+	}
+
+	resource, action, _ := strings.Cut(req.Action, ":")
+	decision := &Decision{RBAC: domain.PermissionGranted(granted, resource, action)}
+	if explain {
+		decision.Request = req
+	}
+
+	var allowedBy, deniedBy *Policy
+	for i := range policies {
+		p := &policies[i]
+		if p.OrganizationID != 0 && p.OrganizationID != req.OrganizationID {
+			continue
+		}
+		if !domain.PermissionGranted(p.Actions, resource, action) {
+			continue
+		}
+		// Karar verildikten sonra sadece explain için değerlendirmeye devam edilir.
+		if !explain && (deniedBy != nil || (p.Effect == EffectAllow && (decision.RBAC || allowedBy != nil))) {
+			continue
+		}
+
+		var trace *[]string
+		var conditions []string
+		if explain {
+			trace = &conditions
+		}
+		matched := p.Condition == nil || p.Condition.evaluate(req, trace)
+		if explain {
+			decision.Trace = append(decision.Trace, PolicyTrace{
+				Policy:     p.Name,
+				Source:     p.Source,
+				Effect:     p.Effect,
+				Matched:    matched,
+				Conditions: conditions,
+			})
+		}
+		if !matched {
+			continue
+		}
+		if p.Effect == EffectDeny && deniedBy == nil {
+			deniedBy = p
+		}
+		if p.Effect == EffectAllow && allowedBy == nil {
+			allowedBy = p
+		}
+	}
+
+	switch {
+	case deniedBy != nil:
+		decision.DecidedBy = decidedByPolicy + deniedBy.Name
+		decision.Reason = fmt.Sprintf("denied by policy %q", deniedBy.Name)
+	case decision.RBAC:
+		decision.Allowed = true
+		decision.DecidedBy = DecidedByRBAC
+		decision.Reason = fmt.Sprintf("%s is granted by the subject's roles or direct grants", req.Action)
+	case allowedBy != nil:
+		decision.Allowed = true
+		decision.DecidedBy = decidedByPolicy + allowedBy.Name
+		decision.Reason = fmt.Sprintf("allowed by policy %q", allowedBy.Name)
+	default:
+		decision.DecidedBy = DecidedByDefault
+		decision.Reason = fmt.Sprintf("%s is not granted by any role, direct grant or policy", req.Action)
+	}
+	return decision
+}
@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadDir, dizindeki *.json dosyalarından politikaları okur. Her dosya tek bir politika veya bir
+// politika listesi içerebilir. Alt dizinler okunmaz (örn. policies/examples). Politikalar burada
+// sadece okunur; doğrulama Engine.Load sırasında yapılır.
+func LoadDir(dir string) ([]Policy, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var policies []Policy
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for i := range parsed {
+			parsed[i].Source = SourceFile + ":" + name
+		}
+		policies = append(policies, parsed...)
+	}
+	return policies, nil
+}
+
+// Parse, JSON bir politikayı veya politika listesini okur. Bilinmeyen alanlar reddedilir; yanlış
+// yazılmış bir alan (örn. "conditon") koşulsuz bir politikaya dönüşmemelidir.
+func Parse(data []byte) ([]Policy, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var policies []Policy
+		if err := decodeStrict(data, &policies); err != nil {
+			return nil, err
+		}
+		return policies, nil
+	}
+
+	var p Policy
+	if err := decodeStrict(data, &p); err != nil {
+		return nil, err
+	}
+	return []Policy{p}, nil
+}
+
+func decodeStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	return nil
+}
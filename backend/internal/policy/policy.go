@@ -0,0 +1,191 @@
+// Package policy, rol tabanlı yetkilerin (RBAC) yanında öznitelik tabanlı (ABAC) yetkilendirme
+// sağlar. Politikalar bildirimseldir: hangi yetkiler (işlemler) için geçerli oldukları, etkileri
+// (allow/deny) ve özne, kaynak ve ortam öznitelikleri üzerinde bir koşul tanımlarlar.
+//
+//	{
+//	  "name": "managers-approve-small-orders",
+//	  "effect": "allow",
+//	  "actions": ["order:approve"],
+//	  "condition": {"all": [
+//	    {"attr": "subject.roles", "op": "contains", "value": "manager"},
+//	    {"attr": "resource.amount", "op": "lt", "value": 10000}
+//	  ]}
+//	}
+//
+// Karar sırası (deny-overrides):
+//  1. Koşulu sağlanan bir deny politikası varsa erişim reddedilir (rol yetkisi olsa bile).
+//  2. Kullanıcının rollerinden veya doğrudan yetkilerinden gelen yetki işlemi kapsıyorsa izin verilir.
+//  3. Koşulu sağlanan bir allow politikası varsa izin verilir.
+//  4. Aksi halde erişim reddedilir.
+//
+// Organizasyona ait politikalar (OrganizationID) sadece o organizasyondaki isteklere uygulanır;
+// OrganizationID'si olmayan politikalar tüm organizasyonlarda geçerlidir.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ths-erp.com/internal/permission"
+)
+
+// Effect, politikanın koşulu sağlandığında verdiği karardır.
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Politika kaynakları
+const (
+	SourceFile     = "file"
+	SourceDatabase = "db"
+	SourceDryRun   = "dry-run"
+)
+
+// ErrInvalidPolicy, politika veya koşulu geçersiz olduğunda döner.
+var ErrInvalidPolicy = errors.New("policy: invalid policy")
+
+// Policy, bildirimsel bir yetkilendirme kuralıdır.
+type Policy struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Effect      Effect     `json:"effect"`
+	Actions     []string   `json:"actions"`             // "kaynak:işlem", "kaynak:*" veya "*"
+	Condition   *Condition `json:"condition,omitempty"` // Boşsa politika her istekte geçerlidir
+	// OrganizationID, politikanın geçerli olduğu organizasyondur; 0 ise tüm organizasyonlarda geçerlidir.
+	OrganizationID int    `json:"organizationId,omitempty"`
+	Source         string `json:"source,omitempty"` // Yükleyen tarafından doldurulur ("file:<dosya>" veya "db")
+}
+
+// Condition, öznitelikler üzerinde bir koşuldur. Bir koşul ya bir birleştiricidir (all, any, not)
+// ya da bir karşılaştırmadır (attr + op + value/ref).
+type Condition struct {
+	All []Condition `json:"all,omitempty"`
+	Any []Condition `json:"any,omitempty"`
+	Not *Condition  `json:"not,omitempty"`
+
+	// Attr, "subject.", "resource.", "env." ile başlayan öznitelik yoludur (örn. "resource.amount").
+	// "action" istenen "kaynak:işlem" değerini verir.
+	Attr  string      `json:"attr,omitempty"`
+	Op    string      `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	Ref   string      `json:"ref,omitempty"` // Sabit değer yerine başka bir öznitelik ile karşılaştırma (örn. "subject.id")
+}
+
+// Desteklenen karşılaştırma operatörleri
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpIn       = "in"       // Öznitelik, value listesindeki değerlerden biridir
+	OpContains = "contains" // Liste (veya metin) özniteliği value'yu içerir
+	OpExists   = "exists"   // Öznitelik tanımlıdır
+)
+
+var validOps = map[string]bool{
+	OpEq: true, OpNe: true, OpLt: true, OpLte: true, OpGt: true, OpGte: true,
+	OpIn: true, OpContains: true, OpExists: true,
+}
+
+// Validate, politikanın yüklenebilir olduğunu doğrular. İşlemler yetki registry'sine göre kontrol
+// edilir; böylece yazım hatası olan bir politika sessizce hiçbir isteğe uygulanmamış olmaz.
+func Validate(p *Policy) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPolicy)
+	}
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("%w: %s: effect must be %q or %q", ErrInvalidPolicy, p.Name, EffectAllow, EffectDeny)
+	}
+	if len(p.Actions) == 0 {
+		return fmt.Errorf("%w: %s: at least one action is required", ErrInvalidPolicy, p.Name)
+	}
+	for _, action := range p.Actions {
+		if !permission.ValidGrant(action) {
+			return fmt.Errorf("%w: %s: undeclared action %q", ErrInvalidPolicy, p.Name, action)
+		}
+	}
+	if p.Condition != nil {
+		if err := p.Condition.validate(); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidPolicy, p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Condition) validate() error {
+	kinds := 0
+	if len(c.All) > 0 {
+		kinds++
+	}
+	if len(c.Any) > 0 {
+		kinds++
+	}
+	if c.Not != nil {
+		kinds++
+	}
+	if c.Attr != "" {
+		kinds++
+	}
+	if kinds != 1 {
+		return errors.New("a condition must have exactly one of all, any, not or attr")
+	}
+
+	for i := range c.All {
+		if err := c.All[i].validate(); err != nil {
+			return err
+		}
+	}
+	for i := range c.Any {
+		if err := c.Any[i].validate(); err != nil {
+			return err
+		}
+	}
+	if c.Not != nil {
+		return c.Not.validate()
+	}
+	if c.Attr == "" {
+		return nil
+	}
+
+	if !validPath(c.Attr) {
+		return fmt.Errorf("invalid attribute %q", c.Attr)
+	}
+	if !validOps[c.Op] {
+		return fmt.Errorf("unknown operator %q", c.Op)
+	}
+	if c.Op == OpExists {
+		return nil
+	}
+	if c.Ref != "" {
+		if !validPath(c.Ref) {
+			return fmt.Errorf("invalid reference %q", c.Ref)
+		}
+		return nil
+	}
+	if c.Value == nil {
+		return fmt.Errorf("operator %q on %q requires a value or ref", c.Op, c.Attr)
+	}
+	return nil
+}
+
+func validPath(path string) bool {
+	if path == "action" {
+		return true
+	}
+	root, rest, ok := strings.Cut(path, ".")
+	if !ok || rest == "" {
+		return false
+	}
+	switch root {
+	case "subject", "resource", "env":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	FindByID(ctx context.Context, id int) (*domain.APIKey, error)
+	FindByUser(ctx context.Context, userID int) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id int) error
+	TouchLastUsed(ctx context.Context, id int, usedAt time.Time) error
+}
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) IAPIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(key)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "api_keys").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "api_keys", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "api_keys", "success").Inc()
+	return nil
+}
+
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	return r.findOne(ctx, "key_hash = ?", keyHash)
+}
+
+func (r *APIKeyRepository) FindByID(ctx context.Context, id int) (*domain.APIKey, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.APIKey, error) {
+	start := time.Now()
+	var key domain.APIKey
+	result := r.db.WithContext(ctx).Where(query, arg).First(&key)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "api_keys").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "api_keys", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "api_keys", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "api_keys", "success").Inc()
+	return &key, nil
+}
+
+// FindByUser, kullanıcının iptal edilmemiş anahtarlarını yeniden eskiye sıralı döner.
+// Süresi dolmuş anahtarlar, kullanıcının yenilemesi gerektiğini görebilmesi için listelenir.
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID int) ([]domain.APIKey, error) {
+	start := time.Now()
+	var keys []domain.APIKey
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&keys)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "api_keys").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "api_keys", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "api_keys", "success").Inc()
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "api_keys").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "api_keys", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "api_keys", "success").Inc()
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int, usedAt time.Time) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "api_keys").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "api_keys", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "api_keys", "success").Inc()
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+// IAuditEventRepository, denetim kayıtlarını yazar ve sorgular. Tablo sadece eklemeye açık
+// olduğundan güncelleme ve silme işlemi yoktur.
+type IAuditEventRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	Find(ctx context.Context, filter domain.AuditEventFilter, pagination *domain.Pagination) ([]domain.AuditEvent, *domain.Pagination, error)
+	// FindInBatches, filtreye uyan tüm kayıtları eskiden yeniye batchSize'lık gruplar halinde fn'e verir.
+	FindInBatches(ctx context.Context, filter domain.AuditEventFilter, batchSize int, fn func([]domain.AuditEvent) error) error
+}
+
+type AuditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) IAuditEventRepository {
+	return &AuditEventRepository{db: db}
+}
+
+func (r *AuditEventRepository) Create(ctx context.Context, event *domain.AuditEvent) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(event)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "audit_events").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "audit_events", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "audit_events", "success").Inc()
+	return nil
+}
+
+func (r *AuditEventRepository) Find(ctx context.Context, filter domain.AuditEventFilter, pagination *domain.Pagination) ([]domain.AuditEvent, *domain.Pagination, error) {
+	start := time.Now()
+	var events []domain.AuditEvent
+	var totalRecords int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.AuditEvent{}).Scopes(auditEventFilter(filter)).Count(&totalRecords).Error; err != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "audit_events", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, nil, err
+	}
+
+	pagination.TotalRecords = totalRecords
+	pagination.TotalPages = int(totalRecords) / pagination.GetLimit()
+	if int(totalRecords)%pagination.GetLimit() > 0 {
+		pagination.TotalPages++
+	}
+
+	result := r.db.WithContext(ctx).
+		Scopes(auditEventFilter(filter)).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.GetLimit()).
+		Order(pagination.GetSort()).
+		Find(&events)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "audit_events").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "audit_events", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "audit_events", "success").Inc()
+	return events, pagination, nil
+}
+
+func (r *AuditEventRepository) FindInBatches(ctx context.Context, filter domain.AuditEventFilter, batchSize int, fn func([]domain.AuditEvent) error) error {
+	start := time.Now()
+	var batch []domain.AuditEvent
+	result := r.db.WithContext(ctx).
+		Scopes(auditEventFilter(filter)).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		})
+	metrics.M.DbQueryDuration.WithLabelValues("select", "audit_events").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "audit_events", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "audit_events", "success").Inc()
+	return nil
+}
+
+// auditEventFilter, filtredeki dolu alanları WHERE koşuluna çevirir. Organizasyon filtresi her zaman
+// uygulanır; böylece bir organizasyonun denetçisi diğer organizasyonların kayıtlarını göremez.
+func auditEventFilter(filter domain.AuditEventFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("organization_id = ?", filter.OrganizationID)
+		if filter.ActorID != 0 {
+			db = db.Where("actor_id = ?", filter.ActorID)
+		}
+		if filter.EntityType != "" {
+			db = db.Where("entity_type = ?", filter.EntityType)
+		}
+		if filter.EntityID != "" {
+			db = db.Where("entity_id = ?", filter.EntityID)
+		}
+		if filter.Action != "" {
+			db = db.Where("action = ?", filter.Action)
+		}
+		if filter.RequestID != "" {
+			db = db.Where("request_id = ?", filter.RequestID)
+		}
+		if filter.From != nil {
+			db = db.Where("created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("created_at < ?", *filter.To)
+		}
+		return db
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DataScope, bir okuma sorgusunun hangi satırları görebileceğini tanımlar. Sahiplik sütunu olan
+// varlıkların (örn. reports.requested_by) repository'leri okuma metodlarında DataScope alır ve
+// sorguya Apply ile uygular; böylece kısıtlama her sorguda aynı yerde ve aynı şekilde uygulanır.
+//
+// Sıfır değeri hiçbir satırı göstermez; kapsam her zaman OwnedBy veya Unrestricted ile oluşturulmalıdır.
+type DataScope struct {
+	ownerID      int
+	unrestricted bool
+}
+
+// OwnedBy, sorguyu kullanıcının sahip olduğu satırlarla sınırlar.
+func OwnedBy(userID int) DataScope {
+	return DataScope{ownerID: userID}
+}
+
+// Unrestricted, kısıtlama uygulamaz. Sistem süreçleri (örn. worker) ve "kaynak:read_all"
+// yetkisine sahip kullanıcılar için kullanılır.
+func Unrestricted() DataScope {
+	return DataScope{unrestricted: true}
+}
+
+// IsUnrestricted, kapsamın tüm satırları gösterip göstermediğini döner.
+func (s DataScope) IsUnrestricted() bool {
+	return s.unrestricted
+}
+
+// Apply, kapsamı verilen sahiplik sütunu üzerinden GORM scope'u olarak döner:
+//
+//	r.db.WithContext(ctx).Scopes(scope.Apply("requested_by")).First(&report, id)
+func (s DataScope) Apply(ownerColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s.unrestricted {
+			return db
+		}
+		return db.Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: ownerColumn},
+			Value:  s.ownerID,
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IEmailVerificationTokenRepository interface {
+	Create(ctx context.Context, token *domain.EmailVerificationToken) (*domain.EmailVerificationToken, error)
+	FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error)
+	InvalidateAllForUser(ctx context.Context, userID int) error
+	InvalidateForEmail(ctx context.Context, userID int, email string) error
+}
+
+type EmailVerificationTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationTokenRepository(db *gorm.DB) IEmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepository{db: db}
+}
+
+func (r *EmailVerificationTokenRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) (*domain.EmailVerificationToken, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(token)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "email_verification_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "email_verification_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "email_verification_tokens", "success").Inc()
+	return token, nil
+}
+
+// FindByHashForUpdate, token satırını kilitleyerek getirir; böylece aynı token
+// eş zamanlı iki istekte kullanılamaz.
+func (r *EmailVerificationTokenRepository) FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	start := time.Now()
+	var token domain.EmailVerificationToken
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "email_verification_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "email_verification_tokens", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "email_verification_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "email_verification_tokens", "success").Inc()
+	return &token, nil
+}
+
+// InvalidateAllForUser, kullanıcının henüz kullanılmamış tüm doğrulama token'larını kullanılmış olarak işaretler.
+func (r *EmailVerificationTokenRepository) InvalidateAllForUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "email_verification_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "email_verification_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "email_verification_tokens", "success").Inc()
+	return nil
+}
+
+// InvalidateForEmail, kullanıcının sadece verilen adres için üretilmiş ve henüz kullanılmamış
+// doğrulama token'larını kullanılmış olarak işaretler; diğer adreslerin token'ları geçerli kalır.
+func (r *EmailVerificationTokenRepository) InvalidateForEmail(ctx context.Context, userID int, email string) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.EmailVerificationToken{}).
+		Where("user_id = ? AND email = ? AND used_at IS NULL", userID, email).
+		Update("used_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "email_verification_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "email_verification_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "email_verification_tokens", "success").Inc()
+	return nil
+}
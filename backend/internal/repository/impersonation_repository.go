@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IImpersonationRepository interface {
+	Create(ctx context.Context, session *domain.ImpersonationSession) error
+	FindByID(ctx context.Context, id int) (*domain.ImpersonationSession, error)
+	FindByUser(ctx context.Context, userID int) ([]domain.ImpersonationSession, error)
+	End(ctx context.Context, id int, endedAt time.Time) (bool, error)
+	RecordRequest(ctx context.Context, request *domain.ImpersonationRequest) error
+}
+
+type ImpersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) IImpersonationRepository {
+	return &ImpersonationRepository{db: db}
+}
+
+func (r *ImpersonationRepository) Create(ctx context.Context, session *domain.ImpersonationSession) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(session)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "impersonation_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "impersonation_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "impersonation_sessions", "success").Inc()
+	return nil
+}
+
+func (r *ImpersonationRepository) FindByID(ctx context.Context, id int) (*domain.ImpersonationSession, error) {
+	start := time.Now()
+	var session domain.ImpersonationSession
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&session)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "impersonation_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "impersonation_sessions", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "impersonation_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "impersonation_sessions", "success").Inc()
+	return &session, nil
+}
+
+// FindByUser, kullanıcının adına yapılan veya kullanıcının yaptığı impersonation'ları yeniden eskiye döner.
+func (r *ImpersonationRepository) FindByUser(ctx context.Context, userID int) ([]domain.ImpersonationSession, error) {
+	start := time.Now()
+	var sessions []domain.ImpersonationSession
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? OR impersonator_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&sessions)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "impersonation_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "impersonation_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "impersonation_sessions", "success").Inc()
+	return sessions, nil
+}
+
+// End, henüz sonlandırılmamış impersonation'ı sonlandırır. Kayıt zaten sonlandırılmışsa false döner.
+func (r *ImpersonationRepository) End(ctx context.Context, id int, endedAt time.Time) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.ImpersonationSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", endedAt)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "impersonation_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "impersonation_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "impersonation_sessions", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ImpersonationRepository) RecordRequest(ctx context.Context, request *domain.ImpersonationRequest) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(request)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "impersonation_requests").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "impersonation_requests", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "impersonation_requests", "success").Inc()
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type ILoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *domain.LoginAttempt) error
+	FindRecentByUser(ctx context.Context, userID int, limit int) ([]domain.LoginAttempt, error)
+}
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) ILoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(attempt)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "login_attempts").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "login_attempts", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "login_attempts", "success").Inc()
+	return nil
+}
+
+// FindRecentByUser, kullanıcının en yeni login denemelerini yeniden eskiye sıralı döner.
+func (r *LoginAttemptRepository) FindRecentByUser(ctx context.Context, userID int, limit int) ([]domain.LoginAttempt, error) {
+	start := time.Now()
+	var attempts []domain.LoginAttempt
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&attempts)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "login_attempts").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "login_attempts", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "login_attempts", "success").Inc()
+	return attempts, nil
+}
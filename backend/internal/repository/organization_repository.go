@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IOrganizationRepository interface {
+	FindByID(ctx context.Context, id int) (*domain.Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*domain.Organization, error)
+	// FindByUser, kullanıcının üyesi olduğu organizasyonları en son kullanılandan başlayarak döner.
+	FindByUser(ctx context.Context, userID int) ([]domain.Organization, error)
+	Create(ctx context.Context, org *domain.Organization) error
+	Update(ctx context.Context, org *domain.Organization) error
+	IsMember(ctx context.Context, organizationID, userID int) (bool, error)
+	CountMemberships(ctx context.Context, userID int) (int64, error)
+	// AddMember, kullanıcıyı organizasyona ekler. Kullanıcı zaten üyeyse false döner.
+	AddMember(ctx context.Context, organizationID, userID int) (bool, error)
+	// RemoveMember, üyeliği siler. Kullanıcı üye değilse false döner.
+	RemoveMember(ctx context.Context, organizationID, userID int) (bool, error)
+	// TouchMember, organizasyonun kullanıcı tarafından son kullanılma zamanını günceller.
+	TouchMember(ctx context.Context, organizationID, userID int, usedAt time.Time) error
+	// SaveInvitation, daveti oluşturur. Kullanıcı zaten davet edilmişse davet yenilenir.
+	SaveInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error
+	FindInvitation(ctx context.Context, organizationID, userID int) (*domain.OrganizationInvitation, error)
+	// FindInvitationsByUser, kullanıcının süresi dolmamış davetlerini organizasyonları ile birlikte döner.
+	FindInvitationsByUser(ctx context.Context, userID int) ([]domain.OrganizationInvitation, error)
+	// DeleteInvitation, daveti siler. Davet yoksa false döner.
+	DeleteInvitation(ctx context.Context, organizationID, userID int) (bool, error)
+}
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) IOrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+func (r *OrganizationRepository) FindByID(ctx context.Context, id int) (*domain.Organization, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *OrganizationRepository) FindBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	return r.findOne(ctx, "slug = ?", slug)
+}
+
+func (r *OrganizationRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.Organization, error) {
+	start := time.Now()
+	var org domain.Organization
+	result := r.db.WithContext(ctx).Where(query, arg).First(&org)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organizations").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organizations", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organizations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organizations", "success").Inc()
+	return &org, nil
+}
+
+func (r *OrganizationRepository) FindByUser(ctx context.Context, userID int) ([]domain.Organization, error) {
+	start := time.Now()
+	var orgs []domain.Organization
+	result := r.db.WithContext(ctx).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organization_members.last_used_at DESC NULLS LAST, organizations.id").
+		Find(&orgs)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "success").Inc()
+	return orgs, nil
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(org)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "organizations").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "organizations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "organizations", "success").Inc()
+	return nil
+}
+
+// Update, organizasyonun adını günceller. Kısa ad (slug) değiştirilemez.
+func (r *OrganizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	start := time.Now()
+	org.UpdatedAt = start
+	result := r.db.WithContext(ctx).Model(&domain.Organization{}).Where("id = ?", org.ID).
+		Updates(map[string]interface{}{
+			"name":       org.Name,
+			"updated_at": org.UpdatedAt,
+		})
+	metrics.M.DbQueryDuration.WithLabelValues("update", "organizations").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "organizations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "organizations", "success").Inc()
+	return nil
+}
+
+func (r *OrganizationRepository) IsMember(ctx context.Context, organizationID, userID int) (bool, error) {
+	start := time.Now()
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Count(&count)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "success").Inc()
+	return count > 0, nil
+}
+
+func (r *OrganizationRepository) CountMemberships(ctx context.Context, userID int) (int64, error) {
+	start := time.Now()
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).Where("user_id = ?", userID).Count(&count)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return 0, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_members", "success").Inc()
+	return count, nil
+}
+
+func (r *OrganizationRepository) AddMember(ctx context.Context, organizationID, userID int) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.OrganizationMember{OrganizationID: organizationID, UserID: userID})
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "organization_members", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID int) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&domain.OrganizationMember{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "organization_members", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+func (r *OrganizationRepository) TouchMember(ctx context.Context, organizationID, userID int, usedAt time.Time) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("last_used_at", usedAt)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "organization_members").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "organization_members", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "organization_members", "success").Inc()
+	return nil
+}
+
+func (r *OrganizationRepository) SaveInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"invited_by_id", "expires_at", "created_at"}),
+	}).Create(invitation)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "organization_invitations").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "organization_invitations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "organization_invitations", "success").Inc()
+	return nil
+}
+
+func (r *OrganizationRepository) FindInvitation(ctx context.Context, organizationID, userID int) (*domain.OrganizationInvitation, error) {
+	start := time.Now()
+	var invitation domain.OrganizationInvitation
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&invitation)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organization_invitations").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_invitations", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_invitations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_invitations", "success").Inc()
+	return &invitation, nil
+}
+
+func (r *OrganizationRepository) FindInvitationsByUser(ctx context.Context, userID int) ([]domain.OrganizationInvitation, error) {
+	start := time.Now()
+	var invitations []domain.OrganizationInvitation
+	result := r.db.WithContext(ctx).Preload("Organization").
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "organization_invitations").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_invitations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "organization_invitations", "success").Inc()
+	return invitations, nil
+}
+
+func (r *OrganizationRepository) DeleteInvitation(ctx context.Context, organizationID, userID int) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&domain.OrganizationInvitation{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "organization_invitations").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "organization_invitations", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "organization_invitations", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
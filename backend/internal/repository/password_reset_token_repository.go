@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IPasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error)
+	FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	InvalidateAllForUser(ctx context.Context, userID int) error
+}
+
+type PasswordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) IPasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{db: db}
+}
+
+func (r *PasswordResetTokenRepository) Create(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(token)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "password_reset_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "password_reset_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "password_reset_tokens", "success").Inc()
+	return token, nil
+}
+
+// FindByHashForUpdate, token satırını kilitleyerek getirir; böylece aynı token
+// eş zamanlı iki istekte kullanılamaz.
+func (r *PasswordResetTokenRepository) FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	start := time.Now()
+	var token domain.PasswordResetToken
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "password_reset_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "password_reset_tokens", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "password_reset_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "password_reset_tokens", "success").Inc()
+	return &token, nil
+}
+
+// InvalidateAllForUser, kullanıcının henüz kullanılmamış tüm sıfırlama token'larını kullanılmış olarak işaretler.
+func (r *PasswordResetTokenRepository) InvalidateAllForUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "password_reset_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "password_reset_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "password_reset_tokens", "success").Inc()
+	return nil
+}
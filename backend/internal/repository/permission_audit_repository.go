@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IPermissionAuditRepository interface {
+	Create(ctx context.Context, entry *domain.PermissionAuditLog) error
+	FindByUser(ctx context.Context, userID int, limit int) ([]domain.PermissionAuditLog, error)
+}
+
+type PermissionAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionAuditRepository(db *gorm.DB) IPermissionAuditRepository {
+	return &PermissionAuditRepository{db: db}
+}
+
+func (r *PermissionAuditRepository) Create(ctx context.Context, entry *domain.PermissionAuditLog) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(entry)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "permission_audit_logs").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "permission_audit_logs", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "permission_audit_logs", "success").Inc()
+	return nil
+}
+
+// FindByUser, kullanıcının yetkilerinde yapılan ve kullanıcının yaptığı değişiklikleri yeniden eskiye döner.
+func (r *PermissionAuditRepository) FindByUser(ctx context.Context, userID int, limit int) ([]domain.PermissionAuditLog, error) {
+	start := time.Now()
+	var entries []domain.PermissionAuditLog
+	result := r.db.WithContext(ctx).
+		Where("target_user_id = ? OR actor_id = ?", userID, userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "permission_audit_logs").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "permission_audit_logs", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "permission_audit_logs", "success").Inc()
+	return entries, nil
+}
@@ -6,13 +6,19 @@ import (
 
 	"ths-erp.com/internal/domain"
 	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/tenant"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IPermissionRepository interface {
-	GetUserPermission(ctx context.Context, userID int, resource string) (*domain.UserPermission, error)
-	CheckPermission(ctx context.Context, userID int, resource, action string) (bool, error)
+	GetEffectivePermissions(ctx context.Context, userID int) ([]string, error)
+	FindUserGrants(ctx context.Context, userID int) ([]domain.UserGrant, error)
+	Grant(ctx context.Context, grant *domain.UserGrant) (bool, error)
+	Revoke(ctx context.Context, userID int, permission string) (bool, error)
+	// RevokeAll, kullanıcıya aktif organizasyonda doğrudan verilen tüm yetkileri geri alır.
+	RevokeAll(ctx context.Context, userID int) error
 }
 
 type PermissionRepository struct {
@@ -23,59 +29,115 @@ func NewPermissionRepository(db *gorm.DB) IPermissionRepository {
 	return &PermissionRepository{db: db}
 }
 
-func (r *PermissionRepository) GetUserPermission(ctx context.Context, userID int, resource string) (*domain.UserPermission, error) {
-	start := time.Now()
-	var perm domain.UserPermission
+// effectivePermissionsQuery, kullanıcının aktif organizasyondaki rollerini üst rolleri ile birlikte
+// (kalıtım) dolaşır ve bu rollere verilmiş yetkileri kullanıcıya doğrudan verilen yetkilerle birleştirir.
+// UNION tekrar eden satırları elediği için hatalı tanımlanmış döngüsel kalıtım sonsuz döngüye girmez.
+// Üst roller rol ile aynı organizasyonda olduğundan kalıtım adımında ayrıca filtre gerekmez.
+const effectivePermissionsQuery = `
+WITH RECURSIVE role_tree AS (
+	SELECT r.id, r.parent_id
+	FROM roles r
+	JOIN user_roles ur ON ur.role_id = r.id
+	WHERE ur.user_id = ? AND ur.organization_id = ?
+	UNION
+	SELECT p.id, p.parent_id
+	FROM roles p
+	JOIN role_tree t ON p.id = t.parent_id
+)
+SELECT rp.permission
+FROM role_permissions rp
+JOIN role_tree t ON rp.role_id = t.id
+UNION
+SELECT ug.permission
+FROM user_grants ug
+WHERE ug.user_id = ? AND ug.organization_id = ?
+ORDER BY permission`
+
+// GetEffectivePermissions, kullanıcının aktif organizasyondaki rolleri, devralınan roller ve doğrudan
+// verilen yetkiler üzerinden sahip olduğu tüm "kaynak:işlem" yetkilerini döner.
+func (r *PermissionRepository) GetEffectivePermissions(ctx context.Context, userID int) ([]string, error) {
+	db := r.db.WithContext(ctx)
+	organizationID, err := tenant.RequireOrganization(db)
+	if err != nil {
+		return nil, err
+	}
 
-	result := r.db.WithContext(ctx).Where("user_id = ? AND resource = ?", userID, resource).First(&perm)
-	duration := time.Since(start).Seconds()
+	start := time.Now()
+	var permissions []string
 
-	metrics.M.DbQueryDuration.WithLabelValues("select", "user_permissions").Observe(duration)
+	result := db.Raw(effectivePermissionsQuery, userID, organizationID, userID, organizationID).Scan(&permissions)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "role_permissions").Observe(time.Since(start).Seconds())
 
-	if result.Error == gorm.ErrRecordNotFound {
-		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_permissions", "not_found").Inc()
-		return nil, nil // Hata değil, sadece kayıt yok
-	}
 	if result.Error != nil {
-		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_permissions", "error").Inc()
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "role_permissions", "error").Inc()
 		metrics.M.DatabaseErrorsTotal.Inc()
 		return nil, result.Error
 	}
 
-	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_permissions", "success").Inc()
-	return &perm, nil
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "role_permissions", "success").Inc()
+	return permissions, nil
 }
 
-func (r *PermissionRepository) CheckPermission(ctx context.Context, userID int, resource, action string) (bool, error) {
-	perm, err := r.GetUserPermission(ctx, userID, resource)
-	if err != nil {
-		return false, err
+// FindUserGrants, kullanıcıya doğrudan verilen yetkileri döner.
+func (r *PermissionRepository) FindUserGrants(ctx context.Context, userID int) ([]domain.UserGrant, error) {
+	start := time.Now()
+	var grants []domain.UserGrant
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("permission").Find(&grants)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_grants").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_grants", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
 	}
 
-	if perm == nil {
-		metrics.M.PermissionChecksTotal.WithLabelValues(resource, action, "false").Inc()
-		return false, nil
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_grants", "success").Inc()
+	return grants, nil
+}
+
+// Grant, kullanıcıya doğrudan yetki verir. Yetki zaten verilmişse false döner.
+func (r *PermissionRepository) Grant(ctx context.Context, grant *domain.UserGrant) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(grant)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "user_grants").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_grants", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
 	}
 
-	allowed := false
-	switch action {
-	case "select":
-		allowed = perm.CanSelect
-	case "add":
-		allowed = perm.CanAdd
-	case "update":
-		allowed = perm.CanUpdate
-	case "delete":
-		allowed = perm.CanDelete
-	case "special":
-		allowed = perm.CanSpecial
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_grants", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+// Revoke, kullanıcıya doğrudan verilen yetkiyi geri alır. Böyle bir yetki yoksa false döner.
+func (r *PermissionRepository) Revoke(ctx context.Context, userID int, permission string) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Where("user_id = ? AND permission = ?", userID, permission).Delete(&domain.UserGrant{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "user_grants").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_grants", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
 	}
 
-	allowedStr := "false"
-	if allowed {
-		allowedStr = "true"
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_grants", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PermissionRepository) RevokeAll(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.UserGrant{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "user_grants").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_grants", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
 	}
-	metrics.M.PermissionChecksTotal.WithLabelValues(resource, action, allowedStr).Inc()
 
-	return allowed, nil
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_grants", "success").Inc()
+	return nil
 }
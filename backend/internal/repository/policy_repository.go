@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IPolicyRepository interface {
+	FindAll(ctx context.Context) ([]domain.Policy, error)
+	// FindEnabled, politika motoruna yüklenecek aktif politikaları döner.
+	FindEnabled(ctx context.Context) ([]domain.Policy, error)
+	FindByID(ctx context.Context, id int) (*domain.Policy, error)
+	FindByName(ctx context.Context, name string) (*domain.Policy, error)
+	Create(ctx context.Context, p *domain.Policy) error
+	Update(ctx context.Context, p *domain.Policy) error
+	Delete(ctx context.Context, id int) error
+}
+
+type PolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) IPolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+func (r *PolicyRepository) FindAll(ctx context.Context) ([]domain.Policy, error) {
+	return r.find(ctx, r.db.WithContext(ctx))
+}
+
+func (r *PolicyRepository) FindEnabled(ctx context.Context) ([]domain.Policy, error) {
+	return r.find(ctx, r.db.WithContext(ctx).Where("enabled = ?", true))
+}
+
+func (r *PolicyRepository) find(ctx context.Context, query *gorm.DB) ([]domain.Policy, error) {
+	start := time.Now()
+	var policies []domain.Policy
+	result := query.Order("name").Find(&policies)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "policies").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "policies", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "policies", "success").Inc()
+	return policies, nil
+}
+
+func (r *PolicyRepository) FindByID(ctx context.Context, id int) (*domain.Policy, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *PolicyRepository) FindByName(ctx context.Context, name string) (*domain.Policy, error) {
+	return r.findOne(ctx, "name = ?", name)
+}
+
+func (r *PolicyRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.Policy, error) {
+	start := time.Now()
+	var p domain.Policy
+	result := r.db.WithContext(ctx).Where(query, arg).First(&p)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "policies").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "policies", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "policies", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "policies", "success").Inc()
+	return &p, nil
+}
+
+func (r *PolicyRepository) Create(ctx context.Context, p *domain.Policy) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(p)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "policies").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "policies", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "policies", "success").Inc()
+	return nil
+}
+
+// Update, politikanın adını, dokümanını ve durumunu günceller.
+func (r *PolicyRepository) Update(ctx context.Context, p *domain.Policy) error {
+	start := time.Now()
+	p.UpdatedAt = start
+	result := r.db.WithContext(ctx).Model(&domain.Policy{}).Where("id = ?", p.ID).
+		Select("name", "document", "enabled", "updated_by", "updated_at").
+		Updates(map[string]interface{}{
+			"name":       p.Name,
+			"document":   p.Document,
+			"enabled":    p.Enabled,
+			"updated_by": p.UpdatedBy,
+			"updated_at": p.UpdatedAt,
+		})
+	metrics.M.DbQueryDuration.WithLabelValues("update", "policies").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "policies", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "policies", "success").Inc()
+	return nil
+}
+
+func (r *PolicyRepository) Delete(ctx context.Context, id int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Delete(&domain.Policy{}, "id = ?", id)
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "policies").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "policies", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "policies", "success").Inc()
+	return nil
+}
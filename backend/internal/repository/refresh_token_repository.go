@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IRefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error)
+	FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	Update(ctx context.Context, token *domain.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+	FindActiveFamilies(ctx context.Context, userID int) ([]string, error)
+}
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) IRefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(token)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "refresh_tokens", "success").Inc()
+	return token, nil
+}
+
+// FindByHashForUpdate, token satırını "SELECT ... FOR UPDATE" ile kilitleyerek getirir.
+// Böylece aynı refresh token ile eş zamanlı yapılan iki istek aynı anda rotasyon yapamaz.
+func (r *RefreshTokenRepository) FindByHashForUpdate(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	start := time.Now()
+	var token domain.RefreshToken
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "refresh_tokens", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "refresh_tokens", "success").Inc()
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) Update(ctx context.Context, token *domain.RefreshToken) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Save(token)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "success").Inc()
+	return nil
+}
+
+// RevokeFamily, bir ailedeki henüz iptal edilmemiş tüm refresh token'ları iptal eder.
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "success").Inc()
+	return nil
+}
+
+// RevokeAllForUser, kullanıcının tüm ailelerdeki aktif refresh token'larını iptal eder.
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "refresh_tokens", "success").Inc()
+	return nil
+}
+
+// FindActiveFamilies, kullanıcının henüz iptal edilmemiş token'ı bulunan ailelerini (oturumlarını) döner.
+func (r *RefreshTokenRepository) FindActiveFamilies(ctx context.Context, userID int) ([]string, error) {
+	start := time.Now()
+	var families []string
+	result := r.db.WithContext(ctx).
+		Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Distinct().
+		Pluck("family_id", &families)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "refresh_tokens").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "refresh_tokens", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "refresh_tokens", "success").Inc()
+	return families, nil
+}
@@ -9,7 +9,8 @@ import (
 
 type IReportRepository interface {
 	Create(ctx context.Context, report *domain.Report) (*domain.Report, error)
-	GetByID(ctx context.Context, id int) (*domain.Report, error)
+	GetByID(ctx context.Context, scope DataScope, id int) (*domain.Report, error)
+	FindAll(ctx context.Context, scope DataScope) ([]domain.Report, error)
 	Update(ctx context.Context, report *domain.Report) error
 }
 
@@ -28,14 +29,28 @@ func (r *ReportRepository) Create(ctx context.Context, report *domain.Report) (*
 	return report, nil
 }
 
-func (r *ReportRepository) GetByID(ctx context.Context, id int) (*domain.Report, error) {
+// reportOwnerColumn, raporun sahibini tutan sütundur.
+const reportOwnerColumn = "requested_by"
+
+// GetByID, raporu döner. Rapor kapsam dışındaysa gorm.ErrRecordNotFound döner; böylece
+// başka kullanıcılara ait raporların varlığı da gizlenir.
+func (r *ReportRepository) GetByID(ctx context.Context, scope DataScope, id int) (*domain.Report, error) {
 	var report domain.Report
-	if err := r.db.WithContext(ctx).First(&report, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(scope.Apply(reportOwnerColumn)).First(&report, id).Error; err != nil {
 		return nil, err
 	}
 	return &report, nil
 }
 
+// FindAll, kapsamdaki raporları yeniden eskiye döner.
+func (r *ReportRepository) FindAll(ctx context.Context, scope DataScope) ([]domain.Report, error) {
+	var reports []domain.Report
+	if err := r.db.WithContext(ctx).Scopes(scope.Apply(reportOwnerColumn)).Order("created_at DESC").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
 func (r *ReportRepository) Update(ctx context.Context, report *domain.Report) error {
 	return r.db.WithContext(ctx).Save(report).Error
 }
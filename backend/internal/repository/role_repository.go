@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/tenant"
+)
+
+type IRoleRepository interface {
+	FindAll(ctx context.Context) ([]domain.Role, error)
+	FindByID(ctx context.Context, id int) (*domain.Role, error)
+	FindByName(ctx context.Context, name string) (*domain.Role, error)
+	Create(ctx context.Context, role *domain.Role) error
+	Update(ctx context.Context, role *domain.Role) error
+	ReplacePermissions(ctx context.Context, roleID int, permissions []string) error
+	Delete(ctx context.Context, id int) error
+	CountChildren(ctx context.Context, id int) (int64, error)
+	// GetPermissions, rolün kendi ve üst rollerinden devraldığı tüm yetkileri döner.
+	GetPermissions(ctx context.Context, id int) ([]string, error)
+	FindByUser(ctx context.Context, userID int) ([]domain.Role, error)
+	// FindEffectiveByUser, kullanıcıya atanmış rolleri devraldıkları üst roller ile birlikte döner.
+	FindEffectiveByUser(ctx context.Context, userID int) ([]domain.Role, error)
+	Assign(ctx context.Context, userID, roleID int) (bool, error)
+	Unassign(ctx context.Context, userID, roleID int) (bool, error)
+	// UnassignAll, kullanıcının aktif organizasyondaki tüm rollerini kaldırır.
+	UnassignAll(ctx context.Context, userID int) error
+}
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) IRoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// roleAncestorsQuery, aktif organizasyondaki rolü ve tüm üst rollerini döner. UNION döngüsel
+// kalıtımda sonsuz döngüyü önler.
+const roleAncestorsQuery = `
+WITH RECURSIVE role_tree AS (
+	SELECT r.id, r.parent_id
+	FROM roles r
+	WHERE r.id = ? AND r.organization_id = ?
+	UNION
+	SELECT p.id, p.parent_id
+	FROM roles p
+	JOIN role_tree t ON p.id = t.parent_id
+)
+SELECT DISTINCT rp.permission
+FROM role_permissions rp
+JOIN role_tree t ON rp.role_id = t.id
+ORDER BY rp.permission`
+
+// effectiveRolesQuery, kullanıcıya aktif organizasyonda atanmış rolleri ve bu rollerin üst rollerini döner.
+const effectiveRolesQuery = `
+WITH RECURSIVE role_tree AS (
+	SELECT r.id, r.parent_id
+	FROM roles r
+	JOIN user_roles ur ON ur.role_id = r.id
+	WHERE ur.user_id = ? AND ur.organization_id = ?
+	UNION
+	SELECT p.id, p.parent_id
+	FROM roles p
+	JOIN role_tree t ON p.id = t.parent_id
+)
+SELECT r.*
+FROM roles r
+WHERE r.id IN (SELECT id FROM role_tree)
+ORDER BY r.name`
+
+func (r *RoleRepository) FindAll(ctx context.Context) ([]domain.Role, error) {
+	start := time.Now()
+	var roles []domain.Role
+	result := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "success").Inc()
+	return roles, nil
+}
+
+func (r *RoleRepository) FindByID(ctx context.Context, id int) (*domain.Role, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *RoleRepository) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	return r.findOne(ctx, "name = ?", name)
+}
+
+func (r *RoleRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.Role, error) {
+	start := time.Now()
+	var role domain.Role
+	result := r.db.WithContext(ctx).Preload("Permissions").Where(query, arg).First(&role)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "roles").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "success").Inc()
+	return &role, nil
+}
+
+// Create, rolü yetkileri ile birlikte oluşturur.
+func (r *RoleRepository) Create(ctx context.Context, role *domain.Role) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(role)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "roles", "success").Inc()
+	return nil
+}
+
+// Update, rolün adını, açıklamasını ve üst rolünü günceller. Yetkiler ReplacePermissions ile değiştirilir.
+func (r *RoleRepository) Update(ctx context.Context, role *domain.Role) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.Role{}).Where("id = ?", role.ID).
+		Select("name", "description", "parent_id").
+		Updates(map[string]interface{}{
+			"name":        role.Name,
+			"description": role.Description,
+			"parent_id":   role.ParentID,
+		})
+	metrics.M.DbQueryDuration.WithLabelValues("update", "roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "roles", "success").Inc()
+	return nil
+}
+
+// ReplacePermissions, rolün yetkilerini verilen liste ile değiştirir.
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, roleID int, permissions []string) error {
+	start := time.Now()
+	err := r.db.WithContext(ctx).Where("role_id = ?", roleID).Delete(&domain.RolePermission{}).Error
+	if err == nil && len(permissions) > 0 {
+		rows := make([]domain.RolePermission, 0, len(permissions))
+		for _, permission := range permissions {
+			rows = append(rows, domain.RolePermission{RoleID: roleID, Permission: permission})
+		}
+		err = r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	}
+	metrics.M.DbQueryDuration.WithLabelValues("update", "role_permissions").Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "role_permissions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return err
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "role_permissions", "success").Inc()
+	return nil
+}
+
+// Delete, rolü siler. Rolün yetkileri cascade ile, kullanıcı atamaları burada silinir.
+func (r *RoleRepository) Delete(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.db.WithContext(ctx).Where("role_id = ?", id).Delete(&domain.UserRole{}).Error
+	if err == nil {
+		err = r.db.WithContext(ctx).Delete(&domain.Role{}, "id = ?", id).Error
+	}
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "roles").Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return err
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "roles", "success").Inc()
+	return nil
+}
+
+// CountChildren, rolü üst rol olarak kullanan rollerin sayısını döner.
+func (r *RoleRepository) CountChildren(ctx context.Context, id int) (int64, error) {
+	start := time.Now()
+	var count int64
+	result := r.db.WithContext(ctx).Model(&domain.Role{}).Where("parent_id = ?", id).Count(&count)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return 0, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "roles", "success").Inc()
+	return count, nil
+}
+
+func (r *RoleRepository) GetPermissions(ctx context.Context, id int) ([]string, error) {
+	db := r.db.WithContext(ctx)
+	organizationID, err := tenant.RequireOrganization(db)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var permissions []string
+	result := db.Raw(roleAncestorsQuery, id, organizationID).Scan(&permissions)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "role_permissions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "role_permissions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "role_permissions", "success").Inc()
+	return permissions, nil
+}
+
+func (r *RoleRepository) FindByUser(ctx context.Context, userID int) ([]domain.Role, error) {
+	start := time.Now()
+	var roles []domain.Role
+	result := r.db.WithContext(ctx).Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_roles", "success").Inc()
+	return roles, nil
+}
+
+func (r *RoleRepository) FindEffectiveByUser(ctx context.Context, userID int) ([]domain.Role, error) {
+	db := r.db.WithContext(ctx)
+	organizationID, err := tenant.RequireOrganization(db)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var roles []domain.Role
+	result := db.Raw(effectiveRolesQuery, userID, organizationID).Scan(&roles)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_roles", "success").Inc()
+	return roles, nil
+}
+
+// Assign, rolü kullanıcıya atar. Rol zaten atanmışsa false döner.
+func (r *RoleRepository) Assign(ctx context.Context, userID, roleID int) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.UserRole{UserID: userID, RoleID: roleID})
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "user_roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_roles", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+// Unassign, rolü kullanıcıdan kaldırır. Rol atanmamışsa false döner.
+func (r *RoleRepository) Unassign(ctx context.Context, userID, roleID int) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&domain.UserRole{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "user_roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_roles", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+func (r *RoleRepository) UnassignAll(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.UserRole{})
+	metrics.M.DbQueryDuration.WithLabelValues("delete", "user_roles").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_roles", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("delete", "user_roles", "success").Inc()
+	return nil
+}
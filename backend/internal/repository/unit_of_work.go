@@ -16,6 +16,19 @@ type IUnitOfWork interface {
 	CountryRepository() ICountryRepository
 	LanguageRepository() ILanguageRepository
 	UnitRepository() IUnitRepository
+	RefreshTokenRepository() IRefreshTokenRepository
+	PasswordResetTokenRepository() IPasswordResetTokenRepository
+	EmailVerificationTokenRepository() IEmailVerificationTokenRepository
+	LoginAttemptRepository() ILoginAttemptRepository
+	UserSessionRepository() IUserSessionRepository
+	APIKeyRepository() IAPIKeyRepository
+	UserIdentityRepository() IUserIdentityRepository
+	ImpersonationRepository() IImpersonationRepository
+	RoleRepository() IRoleRepository
+	PermissionAuditRepository() IPermissionAuditRepository
+	PolicyRepository() IPolicyRepository
+	OrganizationRepository() IOrganizationRepository
+	AuditEventRepository() IAuditEventRepository
 	Commit() error
 	Rollback()
 }
@@ -66,6 +79,71 @@ func (u *unitOfWork) UnitRepository() IUnitRepository {
 	return NewUnitRepository(u.tx)
 }
 
+// RefreshTokenRepository returns a refresh token repository that uses the transaction.
+func (u *unitOfWork) RefreshTokenRepository() IRefreshTokenRepository {
+	return NewRefreshTokenRepository(u.tx)
+}
+
+// PasswordResetTokenRepository returns a password reset token repository that uses the transaction.
+func (u *unitOfWork) PasswordResetTokenRepository() IPasswordResetTokenRepository {
+	return NewPasswordResetTokenRepository(u.tx)
+}
+
+// EmailVerificationTokenRepository returns an email verification token repository that uses the transaction.
+func (u *unitOfWork) EmailVerificationTokenRepository() IEmailVerificationTokenRepository {
+	return NewEmailVerificationTokenRepository(u.tx)
+}
+
+// LoginAttemptRepository returns a login attempt repository that uses the transaction.
+func (u *unitOfWork) LoginAttemptRepository() ILoginAttemptRepository {
+	return NewLoginAttemptRepository(u.tx)
+}
+
+// UserSessionRepository returns a user session repository that uses the transaction.
+func (u *unitOfWork) UserSessionRepository() IUserSessionRepository {
+	return NewUserSessionRepository(u.tx)
+}
+
+// APIKeyRepository returns an API key repository that uses the transaction.
+func (u *unitOfWork) APIKeyRepository() IAPIKeyRepository {
+	return NewAPIKeyRepository(u.tx)
+}
+
+// UserIdentityRepository returns a user identity repository that uses the transaction.
+func (u *unitOfWork) UserIdentityRepository() IUserIdentityRepository {
+	return NewUserIdentityRepository(u.tx)
+}
+
+// ImpersonationRepository returns an impersonation repository that uses the transaction.
+func (u *unitOfWork) ImpersonationRepository() IImpersonationRepository {
+	return NewImpersonationRepository(u.tx)
+}
+
+// RoleRepository returns a role repository that uses the transaction.
+func (u *unitOfWork) RoleRepository() IRoleRepository {
+	return NewRoleRepository(u.tx)
+}
+
+// PermissionAuditRepository returns a permission audit repository that uses the transaction.
+func (u *unitOfWork) PermissionAuditRepository() IPermissionAuditRepository {
+	return NewPermissionAuditRepository(u.tx)
+}
+
+// PolicyRepository returns a policy repository that uses the transaction.
+func (u *unitOfWork) PolicyRepository() IPolicyRepository {
+	return NewPolicyRepository(u.tx)
+}
+
+// OrganizationRepository returns an organization repository that uses the transaction.
+func (u *unitOfWork) OrganizationRepository() IOrganizationRepository {
+	return NewOrganizationRepository(u.tx)
+}
+
+// AuditEventRepository returns an audit event repository that uses the transaction.
+func (u *unitOfWork) AuditEventRepository() IAuditEventRepository {
+	return NewAuditEventRepository(u.tx)
+}
+
 // Commit commits the transaction.
 func (u *unitOfWork) Commit() error {
 	if err := u.tx.Commit().Error; err != nil {
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IUserIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+	FindBySubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	TouchLastLogin(ctx context.Context, id int, loginAt time.Time) error
+}
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) IUserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(identity)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "user_identities").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_identities", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_identities", "success").Inc()
+	return nil
+}
+
+func (r *UserIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	start := time.Now()
+	var identity domain.UserIdentity
+	result := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_identities").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_identities", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_identities", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_identities", "success").Inc()
+	return &identity, nil
+}
+
+func (r *UserIdentityRepository) TouchLastLogin(ctx context.Context, id int, loginAt time.Time) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_at", loginAt)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "user_identities").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "user_identities", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "user_identities", "success").Inc()
+	return nil
+}
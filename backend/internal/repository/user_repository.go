@@ -5,17 +5,27 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/domain"
 	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/tenant"
 )
 
+// IUserRepository - Kullanıcılar organizasyonlar arasında paylaşılır; FindByID ve FindAll aktif
+// organizasyon varsa sadece organizasyonun üyelerini döner (bkz. memberScope). FindByEmail,
+// login ve e-posta tekilliği kontrolleri için tüm kullanıcılarda arar.
 type IUserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindByID(ctx context.Context, id int) (*domain.User, error)
 	FindAll(ctx context.Context) ([]domain.User, error)
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	Update(ctx context.Context, id int, user *domain.User) (*domain.User, error)
+	UpdateFields(ctx context.Context, id int, fields map[string]interface{}) error
 	Delete(ctx context.Context, id int) error
+	SetTokensValidAfter(ctx context.Context, id int, validAfter time.Time) error
+	AdvanceTwoFactorStep(ctx context.Context, id int, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, id int, hashedCode string) (bool, error)
+	ReplacePasswordHash(ctx context.Context, id int, oldHash, newHash string) (bool, error)
 }
 
 type UserRepository struct {
@@ -51,7 +61,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id int) (*domain.User, er
 	start := time.Now()
 	var user domain.User
 
-	result := r.db.WithContext(ctx).First(&user, id)
+	result := r.db.WithContext(ctx).Scopes(memberScope(ctx)).First(&user, id)
 	duration := time.Since(start).Seconds()
 
 	metrics.M.DbQueryDuration.WithLabelValues("select", "users").Observe(duration)
@@ -74,7 +84,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	start := time.Now()
 	var users []domain.User
 
-	result := r.db.WithContext(ctx).Find(&users)
+	result := r.db.WithContext(ctx).Scopes(memberScope(ctx)).Find(&users)
 	duration := time.Since(start).Seconds()
 
 	metrics.M.DbQueryDuration.WithLabelValues("select", "users").Observe(duration)
@@ -133,6 +143,30 @@ func (r *UserRepository) Update(ctx context.Context, id int, user *domain.User)
 	return &updatedUser, nil
 }
 
+// UpdateFields, sadece verilen sütunları günceller. Update'in aksine sıfır değerler
+// (false, "", nil) de yazılır; bu yüzden alanları temizlemek için kullanılır.
+func (r *UserRepository) UpdateFields(ctx context.Context, id int, fields map[string]interface{}) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(fields)
+	duration := time.Since(start).Seconds()
+
+	metrics.M.DbQueryDuration.WithLabelValues("update", "users").Observe(duration)
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "not_found").Inc()
+		return gorm.ErrRecordNotFound
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "success").Inc()
+	return nil
+}
+
 func (r *UserRepository) Delete(ctx context.Context, id int) error {
 	start := time.Now()
 	result := r.db.WithContext(ctx).Delete(&domain.User{}, id)
@@ -154,3 +188,114 @@ func (r *UserRepository) Delete(ctx context.Context, id int) error {
 	metrics.M.DbQueriesTotal.WithLabelValues("delete", "users", "success").Inc()
 	return nil
 }
+
+// SetTokensValidAfter, kullanıcının "tokens valid after" zaman damgasını günceller.
+// Kullanıcı silinmiş olabileceğinden etkilenen satır olmaması hata sayılmaz.
+func (r *UserRepository) SetTokensValidAfter(ctx context.Context, id int, validAfter time.Time) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("tokens_valid_after", validAfter)
+	duration := time.Since(start).Seconds()
+
+	metrics.M.DbQueryDuration.WithLabelValues("update", "users").Observe(duration)
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "success").Inc()
+	return nil
+}
+
+// AdvanceTwoFactorStep, son kullanılan TOTP zaman adımını sadece daha büyük bir adıma ilerletir.
+// Koşul veritabanında kontrol edildiği için aynı kod eşzamanlı iki istekte de kullanılamaz;
+// adım zaten kullanılmışsa false döner.
+func (r *UserRepository) AdvanceTwoFactorStep(ctx context.Context, id int, step int64) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND two_factor_last_step < ?", id, step).
+		Update("two_factor_last_step", step)
+	duration := time.Since(start).Seconds()
+
+	metrics.M.DbQueryDuration.WithLabelValues("update", "users").Observe(duration)
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+// ConsumeRecoveryCode, kurtarma kodunu listeden sadece hâlâ listedeyse çıkarır. Koşul veritabanında
+// kontrol edildiği için aynı kod eşzamanlı iki istekte de kullanılamaz; kod zaten kullanılmışsa false döner.
+func (r *UserRepository) ConsumeRecoveryCode(ctx context.Context, id int, hashedCode string) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND ? = ANY(two_factor_recovery_codes)", id, hashedCode).
+		Update("two_factor_recovery_codes", gorm.Expr("array_remove(two_factor_recovery_codes, ?)", hashedCode))
+	duration := time.Since(start).Seconds()
+
+	metrics.M.DbQueryDuration.WithLabelValues("update", "users").Observe(duration)
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+// ReplacePasswordHash, şifre özetini sadece kayıtlı özet hâlâ oldHash ise değiştirir.
+// Arka planda yapılan yeniden özetleme, eşzamanlı bir şifre değişikliğini ezmemelidir;
+// özet bu arada değiştiyse false döner.
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, id int, oldHash, newHash string) (bool, error) {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND password_hash = ?", id, oldHash).
+		Update("password_hash", newHash)
+	duration := time.Since(start).Seconds()
+
+	metrics.M.DbQueryDuration.WithLabelValues("update", "users").Observe(duration)
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return false, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "users", "success").Inc()
+	return result.RowsAffected > 0, nil
+}
+
+// memberScope, kullanıcı sorgularını aktif organizasyonun üyeleriyle sınırlar. users tablosu
+// kiracıya ait değildir (bir kullanıcı birden fazla organizasyonun üyesi olabilir); üyelik
+// organization_members üzerinden kontrol edilir. Oturumdaki kullanıcı kendi kaydını her zaman görür;
+// hiçbir organizasyonun üyesi olmayan bir kullanıcı sadece kendi kaydını görür. Kimliksiz akışlarda
+// (login, token yenileme, şifre sıfırlama) ve WithoutScope ile işaretlenmiş sistem süreçlerinde
+// kısıtlama uygulanmaz.
+func memberScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenant.IsUnscoped(ctx) {
+			return db
+		}
+		const isMember = "EXISTS (SELECT 1 FROM organization_members m WHERE m.user_id = users.id AND m.organization_id = ?)"
+		organizationID, hasOrganization := tenant.OrganizationID(ctx)
+		user, err := auth.GetUserFromContext(ctx)
+		switch {
+		case err == nil && hasOrganization:
+			return db.Where("(users.id = ? OR "+isMember+")", user.UserID, organizationID)
+		case err == nil:
+			return db.Where("users.id = ?", user.UserID)
+		case hasOrganization:
+			return db.Where(isMember, organizationID)
+		default:
+			return db
+		}
+	}
+}
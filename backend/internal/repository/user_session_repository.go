@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+type IUserSessionRepository interface {
+	Create(ctx context.Context, session *domain.UserSession) error
+	FindBySessionID(ctx context.Context, sessionID string) (*domain.UserSession, error)
+	FindActiveByUser(ctx context.Context, userID int) ([]domain.UserSession, error)
+	Touch(ctx context.Context, sessionID, ip string, seenAt time.Time, expiresAt *time.Time) error
+	// SetOrganization, oturumun aktif organizasyonunu değiştirir.
+	SetOrganization(ctx context.Context, sessionID string, organizationID int) error
+	Revoke(ctx context.Context, sessionID string) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+type UserSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) IUserSessionRepository {
+	return &UserSessionRepository{db: db}
+}
+
+func (r *UserSessionRepository) Create(ctx context.Context, session *domain.UserSession) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).Create(session)
+	metrics.M.DbQueryDuration.WithLabelValues("insert", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("insert", "user_sessions", "success").Inc()
+	return nil
+}
+
+func (r *UserSessionRepository) FindBySessionID(ctx context.Context, sessionID string) (*domain.UserSession, error) {
+	start := time.Now()
+	var session domain.UserSession
+	result := r.db.WithContext(ctx).Where("session_id = ?", sessionID).First(&session)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error == gorm.ErrRecordNotFound {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_sessions", "not_found").Inc()
+		return nil, result.Error
+	}
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_sessions", "success").Inc()
+	return &session, nil
+}
+
+// FindActiveByUser, kullanıcının iptal edilmemiş ve süresi dolmamış oturumlarını
+// son görülme zamanına göre yeniden eskiye sıralı döner.
+func (r *UserSessionRepository) FindActiveByUser(ctx context.Context, userID int) ([]domain.UserSession, error) {
+	start := time.Now()
+	var sessions []domain.UserSession
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	metrics.M.DbQueryDuration.WithLabelValues("select", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("select", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return nil, result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("select", "user_sessions", "success").Inc()
+	return sessions, nil
+}
+
+// Touch, oturumun son görülme zamanını ve IP adresini günceller. expiresAt verilirse
+// (refresh token rotasyonu) oturumun bitiş zamanı da uzatılır.
+func (r *UserSessionRepository) Touch(ctx context.Context, sessionID, ip string, seenAt time.Time, expiresAt *time.Time) error {
+	fields := map[string]interface{}{"last_seen_at": seenAt}
+	if ip != "" {
+		fields["ip"] = ip
+	}
+	if expiresAt != nil {
+		fields["expires_at"] = *expiresAt
+	}
+
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Updates(fields)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "success").Inc()
+	return nil
+}
+
+func (r *UserSessionRepository) SetOrganization(ctx context.Context, sessionID string, organizationID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("organization_id", organizationID)
+	metrics.M.DbQueryDuration.WithLabelValues("update", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "success").Inc()
+	return nil
+}
+
+func (r *UserSessionRepository) Revoke(ctx context.Context, sessionID string) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "success").Inc()
+	return nil
+}
+
+func (r *UserSessionRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	start := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&domain.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	metrics.M.DbQueryDuration.WithLabelValues("update", "user_sessions").Observe(time.Since(start).Seconds())
+
+	if result.Error != nil {
+		metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "error").Inc()
+		metrics.M.DatabaseErrorsTotal.Inc()
+		return result.Error
+	}
+
+	metrics.M.DbQueriesTotal.WithLabelValues("update", "user_sessions", "success").Inc()
+	return nil
+}
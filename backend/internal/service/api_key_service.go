@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/platform/cache"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/tenant"
+)
+
+// apiKeyUsedKeyPrefix, anahtarın son kullanım zamanının yakın zamanda yazıldığını işaretler.
+const apiKeyUsedKeyPrefix = "auth:api_key_used:"
+
+// apiKeyTouchInterval, son kullanım zamanının veritabanına en fazla hangi sıklıkla yazılacağıdır.
+const apiKeyTouchInterval = time.Minute
+
+type IAPIKeyService interface {
+	CreateKey(ctx context.Context, ownerID, createdByID int, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error)
+	ListKeys(ctx context.Context, ownerID int) ([]dto.APIKeyResponse, error)
+	RevokeKey(ctx context.Context, ownerID, keyID int) error
+	Authenticate(ctx context.Context, rawKey string) (*auth.AuthUser, error)
+}
+
+// APIKeyServiceConfig, anahtar sürelerini belirler.
+type APIKeyServiceConfig struct {
+	DefaultTTL time.Duration // ExpiresInDays verilmediğinde kullanılan süre
+	MaxTTL     time.Duration // İzin verilen en uzun süre
+}
+
+type APIKeyService struct {
+	uowFactory IUnitOfWorkFactory
+	cache      cache.ICache
+	cfg        APIKeyServiceConfig
+}
+
+func NewAPIKeyService(uowFactory IUnitOfWorkFactory, cache cache.ICache, cfg APIKeyServiceConfig) IAPIKeyService {
+	return &APIKeyService{
+		uowFactory: uowFactory,
+		cache:      cache,
+		cfg:        cfg,
+	}
+}
+
+// CreateKey, ownerID adına yeni bir anahtar üretir. Anahtar düz metin olarak sadece bu
+// yanıtta döner. Kapsamlar anahtara ek bir sınır koyar; anahtar hiçbir zaman sahibinin
+// yetkilerinden fazlasına erişemez. Anahtar aktif organizasyona bağlanır; istekleri her zaman
+// bu organizasyonda çalışır ve sahibi organizasyonun üyesi olmalıdır.
+//
+// Başka bir kullanıcı adına anahtar sadece servis hesapları için oluşturulabilir; aksi halde
+// anahtar, impersonation kontrolleri atlanarak o kullanıcının yetkileriyle işlem yapmak için
+// kullanılabilir. Kapsamlar oluşturan kullanıcının aktif organizasyondaki yetkilerini aşamaz.
+func (s *APIKeyService) CreateKey(ctx context.Context, ownerID, createdByID int, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(req.Scopes) == 0 {
+		metrics.M.ValidationErrorsTotal.WithLabelValues("api_key", "missing_fields").Inc()
+		return nil, apperrors.ErrValidation
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !permission.ValidGrant(scope) {
+			metrics.M.ValidationErrorsTotal.WithLabelValues("api_key", "invalid_scope").Inc()
+			return nil, apperrors.ErrValidation
+		}
+		scopes = append(scopes, scope)
+	}
+
+	ttl := s.cfg.DefaultTTL
+	if req.ExpiresInDays > 0 {
+		ttl = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+	if ttl <= 0 || ttl > s.cfg.MaxTTL {
+		metrics.M.ValidationErrorsTotal.WithLabelValues("api_key", "invalid_expiry").Inc()
+		return nil, apperrors.ErrValidation
+	}
+
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return nil, apperrors.ErrNoOrganization
+	}
+
+	rawKey, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	owner, err := uow.UserRepository().FindByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	if ownerID != createdByID && !owner.ServiceAccount {
+		return nil, apperrors.ErrNotServiceAccount
+	}
+	member, err := uow.OrganizationRepository().IsMember(ctx, organizationID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, apperrors.ErrNotMember
+	}
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, createdByID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureDelegable(held, scopes); err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		UserID:         ownerID,
+		OrganizationID: organizationID,
+		Name:           name,
+		Prefix:         prefix,
+		KeyHash:        auth.HashToken(rawKey),
+		Scopes:         scopes,
+		ExpiresAt:      time.Now().Add(ttl),
+		CreatedByID:    createdByID,
+	}
+	if err := uow.APIKeyRepository().Create(ctx, key); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityAPIKey,
+		EntityID:   strconv.Itoa(key.ID),
+		Action:     domain.AuditActionCreated,
+		Changes: auditChanges(nil, map[string]any{
+			"userId":    key.UserID,
+			"name":      key.Name,
+			"prefix":    key.Prefix,
+			"scopes":    key.Scopes,
+			"expiresAt": key.ExpiresAt,
+		}),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("✓ API key %s created for user %d by user %d", key.Prefix, ownerID, createdByID)
+	return &dto.APIKeyCreatedResponse{APIKeyResponse: toAPIKeyResponse(key), Key: rawKey}, nil
+}
+
+// ListKeys, kullanıcının iptal edilmemiş anahtarlarını döner. Kullanıcı kendisi değilse aktif
+// organizasyonun üyesi olmalıdır; aksi halde ErrNotFound döner.
+func (s *APIKeyService) ListKeys(ctx context.Context, ownerID int) ([]dto.APIKeyResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, ownerID); err != nil {
+		return nil, err
+	}
+
+	keys, err := uow.APIKeyRepository().FindByUser(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, toAPIKeyResponse(&keys[i]))
+	}
+	return responses, nil
+}
+
+// RevokeKey, anahtarı kalıcı olarak iptal eder. Anahtar başka bir kullanıcıya aitse
+// veya zaten iptal edilmişse ErrAPIKeyNotFound, kullanıcı aktif organizasyonun üyesi değilse
+// ErrNotFound döner.
+func (s *APIKeyService) RevokeKey(ctx context.Context, ownerID, keyID int) error {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, ownerID); err != nil {
+		return err
+	}
+
+	keyRepo := uow.APIKeyRepository()
+	key, err := keyRepo.FindByID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrAPIKeyNotFound
+		}
+		return err
+	}
+	if key.UserID != ownerID || key.RevokedAt != nil {
+		return apperrors.ErrAPIKeyNotFound
+	}
+
+	if err := keyRepo.Revoke(ctx, key.ID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityAPIKey,
+		EntityID:   strconv.Itoa(key.ID),
+		Action:     domain.AuditActionRevoked,
+		Changes:    auditChanges(map[string]any{"revoked": false}, map[string]any{"revoked": true}),
+	}); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// Authenticate, ham API anahtarını doğrular ve anahtarın sahibini kapsamlarıyla birlikte döner.
+// İptal edilmiş, süresi dolmuş, sahibi silinmiş veya sahibi anahtarın organizasyonundan çıkarılmış
+// anahtarlar ErrInvalidAPIKey ile reddedilir.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*auth.AuthUser, error) {
+	if !strings.HasPrefix(rawKey, auth.APIKeyPrefix) {
+		metrics.M.APIKeyAuthTotal.WithLabelValues("invalid").Inc()
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	key, err := uow.APIKeyRepository().FindByHash(ctx, auth.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			metrics.M.APIKeyAuthTotal.WithLabelValues("invalid").Inc()
+			return nil, apperrors.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		metrics.M.APIKeyAuthTotal.WithLabelValues("revoked").Inc()
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+	if key.IsExpired() {
+		metrics.M.APIKeyAuthTotal.WithLabelValues("expired").Inc()
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+
+	owner, err := uow.UserRepository().FindByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			metrics.M.APIKeyAuthTotal.WithLabelValues("invalid").Inc()
+			return nil, apperrors.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	member, err := uow.OrganizationRepository().IsMember(ctx, key.OrganizationID, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		metrics.M.APIKeyAuthTotal.WithLabelValues("invalid").Inc()
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+
+	s.touchKey(ctx, key.ID)
+	metrics.M.APIKeyAuthTotal.WithLabelValues("success").Inc()
+
+	return &auth.AuthUser{
+		UserID:   owner.ID,
+		Email:    owner.Email,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+		TenantID: key.OrganizationID,
+	}, nil
+}
+
+// touchKey, anahtarın son kullanım zamanını günceller. Her istekte veritabanına yazmamak
+// için güncelleme anahtar başına apiKeyTouchInterval'da bir yapılır; hata isteği engellemez.
+func (s *APIKeyService) touchKey(ctx context.Context, keyID int) {
+	cacheKey := apiKeyUsedKeyPrefix + strconv.Itoa(keyID)
+	if _, recent := s.cache.Get(cacheKey); recent {
+		return
+	}
+	s.cache.Set(cacheKey, "1", apiKeyTouchInterval)
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+	if err := uow.APIKeyRepository().TouchLastUsed(ctx, keyID, time.Now()); err != nil {
+		log.Printf("ERROR: Could not update last used time of API key %d: %v", keyID, err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not update last used time of API key %d: %v", keyID, err)
+	}
+}
+
+func toAPIKeyResponse(key *domain.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:             key.ID,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		OrganizationID: key.OrganizationID,
+		Scopes:         key.Scopes,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		CreatedAt:      key.CreatedAt,
+		Expired:        key.IsExpired(),
+	}
+}
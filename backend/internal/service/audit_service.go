@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
+)
+
+// auditExportBatchSize, CSV dışa aktarımında veritabanından tek seferde okunan kayıt sayısıdır.
+const auditExportBatchSize = 500
+
+// IAuditService, denetçilerin aktif organizasyondaki denetim kayıtlarını sorgulamasını sağlar.
+// Kayıtlar bu servis ile değil, değişikliği yapan servis tarafından recordAudit ile yazılır.
+type IAuditService interface {
+	ListEvents(ctx context.Context, filter domain.AuditEventFilter, pagination *domain.Pagination) ([]dto.AuditEventResponse, *domain.Pagination, error)
+	// ExportCSV, filtreye uyan tüm kayıtları eskiden yeniye CSV olarak w'ye yazar.
+	ExportCSV(ctx context.Context, filter domain.AuditEventFilter, w io.Writer) error
+}
+
+type AuditService struct {
+	uowFactory IUnitOfWorkFactory
+}
+
+func NewAuditService(uowFactory IUnitOfWorkFactory) IAuditService {
+	return &AuditService{uowFactory: uowFactory}
+}
+
+func (s *AuditService) ListEvents(ctx context.Context, filter domain.AuditEventFilter, pagination *domain.Pagination) ([]dto.AuditEventResponse, *domain.Pagination, error) {
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return nil, nil, apperrors.ErrNoOrganization
+	}
+	filter.OrganizationID = organizationID
+
+	allowedSortBy := map[string]bool{
+		"id":         true,
+		"created_at": true,
+	}
+	if !allowedSortBy[pagination.SortBy] {
+		pagination.SortBy = "created_at"
+	}
+	if pagination.SortOrder != "asc" {
+		pagination.SortOrder = "desc"
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	events, pagination, err := uow.AuditEventRepository().Find(ctx, filter, pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses := make([]dto.AuditEventResponse, 0, len(events))
+	for i := range events {
+		responses = append(responses, toAuditEventResponse(&events[i]))
+	}
+	return responses, pagination, nil
+}
+
+// auditCSVHeader, dışa aktarılan CSV dosyasının sütunlarıdır.
+var auditCSVHeader = []string{"id", "created_at", "organization_id", "actor_id", "impersonator_id", "api_key_id", "entity_type", "entity_id", "action", "changes", "request_id", "ip"}
+
+func (s *AuditService) ExportCSV(ctx context.Context, filter domain.AuditEventFilter, w io.Writer) error {
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return apperrors.ErrNoOrganization
+	}
+	filter.OrganizationID = organizationID
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	err := uow.AuditEventRepository().FindInBatches(ctx, filter, auditExportBatchSize, func(events []domain.AuditEvent) error {
+		for _, e := range events {
+			if err := cw.Write([]string{
+				strconv.Itoa(e.ID),
+				e.CreatedAt.UTC().Format(time.RFC3339),
+				strconv.Itoa(e.OrganizationID),
+				formatOptionalID(e.ActorID),
+				formatOptionalID(e.ImpersonatorID),
+				formatOptionalID(e.APIKeyID),
+				csvSafe(e.EntityType),
+				csvSafe(e.EntityID),
+				csvSafe(e.Action),
+				csvSafe(e.Changes),
+				csvSafe(e.RequestID),
+				csvSafe(e.IP),
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// recordAudit, denetim kaydını değişiklik ile aynı transaction'da yazar. İşlemi yapan kullanıcı,
+// impersonation yapan yönetici, API anahtarı, organizasyon ve istemci bilgileri context'ten alınır;
+// kimliksiz işlemlerde (örn. şifre sıfırlama) ActorID boş kalır. Aktif organizasyon yoksa kayıt
+// organizasyonsuz (organization_id = 0) yazılır ve sadece veritabanından/sistem yöneticisince görülür.
+func recordAudit(ctx context.Context, uow repository.IUnitOfWork, event *domain.AuditEvent) error {
+	if actor, err := auth.GetUserFromContext(ctx); err == nil {
+		event.ActorID = optionalID(actor.UserID)
+		event.ImpersonatorID = optionalID(actor.ImpersonatorID)
+		event.APIKeyID = optionalID(actor.APIKeyID)
+	}
+	event.OrganizationID, _ = tenant.OrganizationID(ctx)
+	client := auth.GetClientInfo(ctx)
+	event.RequestID = client.RequestID
+	event.IP = client.IP
+	if event.Changes == "" {
+		event.Changes = "{}"
+	}
+	return uow.AuditEventRepository().Create(ctx, event)
+}
+
+// auditChange, bir alanın değişiklikten önceki ve sonraki değeridir. Oluşturmada From,
+// silmede To boştur.
+type auditChange struct {
+	From any `json:"from,omitempty"`
+	To   any `json:"to,omitempty"`
+}
+
+// auditChanges, iki anlık görüntü arasında değişen alanları JSON olarak döner. Oluşturma
+// işlemlerinde before, silme işlemlerinde after nil verilir.
+func auditChanges(before, after map[string]any) string {
+	changes := map[string]auditChange{}
+	for field, value := range after {
+		old, existed := before[field]
+		if existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes[field] = auditChange{From: old, To: value}
+	}
+	for field, value := range before {
+		if _, exists := after[field]; !exists {
+			changes[field] = auditChange{From: value}
+		}
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+// userAuditSnapshot, kullanıcının denetim kaydına giren alanlarıdır. Şifre özeti, 2FA sırrı ve
+// kurtarma kodları gibi gizli alanlar kaydedilmez.
+func userAuditSnapshot(user *domain.User) map[string]any {
+	return map[string]any{
+		"name":             user.Name,
+		"email":            user.Email,
+		"pendingEmail":     user.PendingEmail,
+		"emailVerifiedAt":  user.EmailVerifiedAt,
+		"twoFactorEnabled": user.TwoFactorEnabled,
+		"serviceAccount":   user.ServiceAccount,
+	}
+}
+
+// countryAuditSnapshot, ülkenin kodunu ve çevirilerini denetim kaydı için döner.
+func countryAuditSnapshot(country *domain.Country) map[string]any {
+	snapshot := map[string]any{"code": country.Code}
+	for _, t := range country.Translations {
+		snapshot["name."+t.LanguageCode] = t.Name
+	}
+	return snapshot
+}
+
+// organizationAuditSnapshot, organizasyonun denetim kaydına giren alanlarıdır.
+func organizationAuditSnapshot(org *domain.Organization) map[string]any {
+	return map[string]any{
+		"name": org.Name,
+		"slug": org.Slug,
+	}
+}
+
+func toAuditEventResponse(e *domain.AuditEvent) dto.AuditEventResponse {
+	return dto.AuditEventResponse{
+		ID:             e.ID,
+		ActorID:        e.ActorID,
+		ImpersonatorID: e.ImpersonatorID,
+		APIKeyID:       e.APIKeyID,
+		EntityType:     e.EntityType,
+		EntityID:       e.EntityID,
+		Action:         e.Action,
+		Changes:        json.RawMessage(e.Changes),
+		RequestID:      e.RequestID,
+		IP:             e.IP,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func optionalID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func formatOptionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
+
+// csvSafe, hücrenin tablolama programlarında formül olarak çalıştırılmasını (CSV injection)
+// önlemek için formül karakterleri ile başlayan değerlerin başına tek tırnak ekler.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"gorm.io/gorm"
 	"ths-erp.com/internal/domain"
 	"ths-erp.com/internal/dto"
 	"ths-erp.com/internal/platform/cache"
@@ -104,6 +106,14 @@ func (s *CountryService) Create(ctx context.Context, req dto.CreateCountryReques
 	if err := uow.CountryRepository().Create(ctx, country); err != nil {
 		return err
 	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityCountry,
+		EntityID:   country.Code,
+		Action:     domain.AuditActionCreated,
+		Changes:    auditChanges(nil, countryAuditSnapshot(&country)),
+	}); err != nil {
+		return err
+	}
 
 	return uow.Commit()
 }
@@ -116,6 +126,7 @@ func (s *CountryService) Update(ctx context.Context, code string, req dto.Update
 	if err != nil {
 		return err
 	}
+	before := countryAuditSnapshot(country)
 
 	if len(country.Translations) > 0 {
 		country.Translations[0].Name = req.Name
@@ -129,6 +140,14 @@ func (s *CountryService) Update(ctx context.Context, code string, req dto.Update
 	if err := uow.CountryRepository().Update(ctx, *country); err != nil {
 		return err
 	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityCountry,
+		EntityID:   country.Code,
+		Action:     domain.AuditActionUpdated,
+		Changes:    auditChanges(before, countryAuditSnapshot(country)),
+	}); err != nil {
+		return err
+	}
 
 	return uow.Commit()
 }
@@ -137,9 +156,26 @@ func (s *CountryService) Delete(ctx context.Context, code string) error {
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
+	// Silinecek ülke denetim kaydı için okunur; olmayan bir ülkenin silinmesi hata değildir.
+	country, err := uow.CountryRepository().FindByCode(ctx, code, "tr")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
 	if err := uow.CountryRepository().DeleteByCode(ctx, code); err != nil {
 		return err
 	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityCountry,
+		EntityID:   country.Code,
+		Action:     domain.AuditActionDeleted,
+		Changes:    auditChanges(countryAuditSnapshot(country), nil),
+	}); err != nil {
+		return err
+	}
 
 	return uow.Commit()
 }
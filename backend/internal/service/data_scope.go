@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/repository"
+)
+
+// resolveDataScope, oturumdaki kullanıcının sahiplik kısıtlamalı bir kaynakta hangi satırları
+// görebileceğini belirler. readAll yetkisi (örn. permission.ReportReadAll) olan kullanıcılar
+// tüm satırları, diğerleri sadece kendi satırlarını görür. Yeni bir varlık aynı mekanizmayı
+// kendi "kaynak:read_all" yetkisi ve repository'sindeki sahiplik sütunu ile kullanır.
+func resolveDataScope(ctx context.Context, permService IPermissionService, readAll permission.Permission) (repository.DataScope, error) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return repository.DataScope{}, apperrors.ErrUnauthorized
+	}
+
+	allowed, err := permService.CheckPermission(ctx, user.UserID, readAll)
+	if err != nil {
+		return repository.DataScope{}, err
+	}
+	if allowed {
+		return repository.Unrestricted(), nil
+	}
+	return repository.OwnedBy(user.UserID), nil
+}
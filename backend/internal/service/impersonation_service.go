@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/platform/cache"
+)
+
+// IImpersonationService, yöneticilerin destek amacıyla kullanıcı adına işlem yapmasını yönetir.
+type IImpersonationService interface {
+	Start(ctx context.Context, actor *auth.AuthUser, targetUserID int, req *dto.StartImpersonationRequest) (*dto.ImpersonationResponse, error)
+	End(ctx context.Context, user *auth.AuthUser) error
+	// RecordRequest, impersonation token'ı ile yapılan bir isteği denetim kaydına yazar.
+	RecordRequest(ctx context.Context, user *auth.AuthUser, method, path string, status int)
+	ListForUser(ctx context.Context, userID int) ([]dto.ImpersonationSessionResponse, error)
+}
+
+type ImpersonationService struct {
+	uowFactory  IUnitOfWorkFactory
+	permService IPermissionService
+	mapper      IMapper[*domain.User, *dto.UserResponse]
+	cache       cache.ICache
+	ttl         time.Duration
+}
+
+func NewImpersonationService(uowFactory IUnitOfWorkFactory, permService IPermissionService, mapper IMapper[*domain.User, *dto.UserResponse], cache cache.ICache, ttl time.Duration) IImpersonationService {
+	return &ImpersonationService{
+		uowFactory:  uowFactory,
+		permService: permService,
+		mapper:      mapper,
+		cache:       cache,
+		ttl:         ttl,
+	}
+}
+
+// Start, actor adına hedef kullanıcının kimliğiyle kısa ömürlü bir access token üretir.
+// Yetki kontrolü (permission.UserSpecial) route seviyesinde yapılır. Ayrıca:
+//   - impersonation iç içe başlatılamaz ve API anahtarı ile başlatılamaz,
+//   - yönetici kendisini veya servis hesaplarını taklit edemez,
+//   - user:special yetkisi olan başka bir yönetici taklit edilemez (yetki yükseltmeyi önler),
+//   - hedef, yöneticinin aktif organizasyonunun üyesi olmalıdır; değilse ErrNotFound döner,
+//   - hedefin o organizasyondaki yetkileri yöneticinin yetkilerinin alt kümesi olmalıdır.
+func (s *ImpersonationService) Start(ctx context.Context, actor *auth.AuthUser, targetUserID int, req *dto.StartImpersonationRequest) (*dto.ImpersonationResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, apperrors.ErrValidation
+	}
+	if actor.IsAPIKey() || actor.IsImpersonated() || actor.UserID == targetUserID || actor.TenantID == 0 {
+		return nil, apperrors.ErrImpersonationDenied
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	target, err := uow.UserRepository().FindByID(ctx, targetUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+	if target.ServiceAccount {
+		return nil, apperrors.ErrImpersonationDenied
+	}
+	member, err := uow.OrganizationRepository().IsMember(ctx, actor.TenantID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, apperrors.ErrNotFound
+	}
+
+	// Yönetici, impersonation ile sahip olmadığı bir yetkiyi kullanamamalıdır.
+	permRepo := uow.PermissionRepository()
+	held, err := permRepo.GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	targetPermissions, err := permRepo.GetEffectivePermissions(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureDelegable(held, targetPermissions); err != nil {
+		return nil, apperrors.ErrImpersonationDenied
+	}
+
+	// Hedefin yönetici olup olmadığı rol yetkilerine göre belirlenir; o an geçerli bir deny
+	// politikası (örn. mesai saatleri) bir yöneticiyi taklit edilebilir hale getirmemelidir.
+	targetAccess, err := s.permService.Authorize(ctx, target.ID, permission.UserSpecial, AuthorizeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if targetAccess.RBAC || targetAccess.Allowed {
+		return nil, apperrors.ErrImpersonationDenied
+	}
+
+	session := &domain.ImpersonationSession{
+		ImpersonatorID: actor.UserID,
+		UserID:         target.ID,
+		Reason:         reason,
+		TokenID:        uuid.New().String(),
+		IP:             auth.GetClientInfo(ctx).IP,
+		ExpiresAt:      time.Now().Add(s.ttl),
+	}
+	if err := uow.ImpersonationRepository().Create(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityImpersonation,
+		EntityID:   strconv.Itoa(session.ID),
+		Action:     domain.AuditActionCreated,
+		Changes: auditChanges(nil, map[string]any{
+			"userId":    session.UserID,
+			"reason":    session.Reason,
+			"expiresAt": session.ExpiresAt,
+		}),
+	}); err != nil {
+		return nil, err
+	}
+
+	token, err := auth.GenerateImpersonationJWT(target.ID, target.Email, actor.TenantID, auth.ActorClaims{UserID: actor.UserID, Email: actor.Email}, session.ID, session.TokenID, s.ttl)
+	if err != nil {
+		log.Printf("Error generating impersonation token for user %d: %v", target.ID, err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("⚠ Impersonation %d started: user %d acting as user %d (reason: %s)", session.ID, actor.UserID, target.ID, reason)
+	return &dto.ImpersonationResponse{
+		ImpersonationID: session.ID,
+		Token:           token,
+		ExpiresIn:       int(s.ttl.Seconds()),
+		User:            s.mapper.ToResponse(target),
+		ImpersonatorID:  actor.UserID,
+	}, nil
+}
+
+// End, impersonation'ı sonlandırır ve token'ı iptal listesine ekler. Sadece impersonation
+// token'ı ile çağrılabilir; yöneticinin kendi oturumu etkilenmez.
+func (s *ImpersonationService) End(ctx context.Context, user *auth.AuthUser) error {
+	if !user.IsImpersonated() {
+		return apperrors.ErrNotImpersonating
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	// Kayıt, impersonation token'ı ile yazıldığı için hem kullanıcıyı hem yöneticiyi taşır.
+	ended, err := uow.ImpersonationRepository().End(ctx, user.ImpersonationID, time.Now())
+	if err != nil {
+		return err
+	}
+	if ended {
+		if err := recordAudit(ctx, uow, &domain.AuditEvent{
+			EntityType: domain.AuditEntityImpersonation,
+			EntityID:   strconv.Itoa(user.ImpersonationID),
+			Action:     domain.AuditActionEnded,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	if ttl := time.Until(user.TokenExpiresAt); ttl > 0 && user.TokenID != "" {
+		s.cache.Set(revokedTokenKeyPrefix+user.TokenID, "1", ttl)
+	}
+
+	log.Printf("✓ Impersonation %d ended by user %d", user.ImpersonationID, user.ImpersonatorID)
+	return nil
+}
+
+func (s *ImpersonationService) RecordRequest(ctx context.Context, user *auth.AuthUser, method, path string, status int) {
+	if !user.IsImpersonated() {
+		return
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := uow.ImpersonationRepository().RecordRequest(ctx, &domain.ImpersonationRequest{
+		ImpersonationID: user.ImpersonationID,
+		RequestID:       auth.GetClientInfo(ctx).RequestID,
+		Method:          method,
+		Path:            path,
+		Status:          status,
+	}); err != nil {
+		log.Printf("ERROR: Could not record request of impersonation %d: %v", user.ImpersonationID, err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not record request of impersonation %d: %v", user.ImpersonationID, err)
+	}
+}
+
+// ListForUser, kullanıcının adına yapılan ve kullanıcının yaptığı impersonation'ları döner.
+// Kullanıcı aktif organizasyonun üyesi değilse ErrNotFound döner.
+func (s *ImpersonationService) ListForUser(ctx context.Context, userID int) ([]dto.ImpersonationSessionResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := uow.ImpersonationRepository().FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ImpersonationSessionResponse, 0, len(sessions))
+	for i := range sessions {
+		session := &sessions[i]
+		responses = append(responses, dto.ImpersonationSessionResponse{
+			ID:             session.ID,
+			ImpersonatorID: session.ImpersonatorID,
+			UserID:         session.UserID,
+			Reason:         session.Reason,
+			IP:             session.IP,
+			CreatedAt:      session.CreatedAt,
+			ExpiresAt:      session.ExpiresAt,
+			EndedAt:        session.EndedAt,
+			Active:         session.IsActive(),
+		})
+	}
+	return responses, nil
+}
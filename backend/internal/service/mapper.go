@@ -26,9 +26,12 @@ func (m *UserMapper) ToResponse(user *domain.User) *dto.UserResponse {
 		return nil
 	}
 	return &dto.UserResponse{
-		BaseResponse: dto.BaseResponse{ID: user.ID},
-		Name:         user.Name,
-		Email:        user.Email,
+		BaseResponse:   dto.BaseResponse{ID: user.ID},
+		Name:           user.Name,
+		Email:          user.Email,
+		EmailVerified:  user.EmailVerifiedAt != nil,
+		PendingEmail:   user.PendingEmail,
+		ServiceAccount: user.ServiceAccount,
 	}
 }
 
@@ -47,8 +50,9 @@ func (m *UserMapper) ToEntity(req domain.IRequest) *domain.User {
 	}
 	// PasswordHash burada atanmaz, service katmanında hash'lendikten sonra atanır.
 	return &domain.User{
-		Name:  createReq.Name,
-		Email: createReq.Email,
+		Name:           createReq.Name,
+		Email:          createReq.Email,
+		ServiceAccount: createReq.ServiceAccount,
 	}
 }
 
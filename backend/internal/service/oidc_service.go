@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/cache"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/platform/oidc"
+	"ths-erp.com/internal/repository"
+)
+
+const (
+	oidcStateKeyPrefix = "oidc:state:"
+	// OIDCStateTTL, kullanıcının IdP'de giriş yapması için tanınan süredir.
+	OIDCStateTTL = 10 * time.Minute
+)
+
+// IOIDCService, harici kimlik sağlayıcılar ile authorization code + PKCE akışını yönetir.
+type IOIDCService interface {
+	ListProviders() []dto.OIDCProviderResponse
+	// BeginLogin, state/nonce/PKCE değerlerini üretip saklar ve kullanıcının yönlendirileceği adresi
+	// ile state'in özetini döner. Özet, akışı başlatan tarayıcıya cookie olarak verilir.
+	BeginLogin(ctx context.Context, providerName string) (authURL, stateHash string, err error)
+	// CompleteLogin, callback'e dönen code'u doğrular ve eşleşen (gerekirse yeni oluşturulan) kullanıcıyı döner.
+	// stateHash, akışı başlatan tarayıcının cookie'sidir ve state ile eşleşmelidir; böylece başkasının
+	// başlattığı bir akışın callback adresi açılarak onun hesabıyla giriş yapılamaz (login CSRF).
+	// Token üretimi çağırana bırakılır; böylece 2FA akışı normal login ile aynı kalır.
+	CompleteLogin(ctx context.Context, providerName, state, stateHash, code string) (*domain.User, error)
+}
+
+type OIDCService struct {
+	uowFactory IUnitOfWorkFactory
+	cache      cache.ICache
+	registry   *oidc.Registry
+	basePath   string
+}
+
+// oidcLoginState, BeginLogin ile CompleteLogin arasında cache'te tutulan akış bilgisidir.
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
+
+// NewOIDCService, yeni bir OIDCService oluşturur. basePath, sağlayıcı listesinde dönen
+// login adreslerinin önekidir (örn. "/api/v1/auth/oidc").
+func NewOIDCService(uowFactory IUnitOfWorkFactory, cache cache.ICache, registry *oidc.Registry, basePath string) IOIDCService {
+	return &OIDCService{
+		uowFactory: uowFactory,
+		cache:      cache,
+		registry:   registry,
+		basePath:   strings.TrimSuffix(basePath, "/"),
+	}
+}
+
+func (s *OIDCService) ListProviders() []dto.OIDCProviderResponse {
+	names := s.registry.Names()
+	providers := make([]dto.OIDCProviderResponse, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, dto.OIDCProviderResponse{
+			Name:     name,
+			LoginURL: s.basePath + "/" + name + "/login",
+		})
+	}
+	return providers
+}
+
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.registry.Get(providerName)
+	if !ok {
+		return "", "", apperrors.ErrOIDCProviderNotFound
+	}
+
+	state, err := oidc.NewState()
+	if err != nil {
+		return "", "", apperrors.ErrInternalServer
+	}
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		return "", "", apperrors.ErrInternalServer
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", "", apperrors.ErrInternalServer
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Printf("ERROR: Could not build authorization url for provider %s: %v", providerName, err)
+		return "", "", err
+	}
+
+	payload, err := json.Marshal(oidcLoginState{Provider: providerName, Nonce: nonce, CodeVerifier: verifier})
+	if err != nil {
+		return "", "", err
+	}
+	s.cache.Set(oidcStateKeyPrefix+state, string(payload), OIDCStateTTL)
+
+	return authURL, auth.HashToken(state), nil
+}
+
+func (s *OIDCService) CompleteLogin(ctx context.Context, providerName, state, stateHash, code string) (*domain.User, error) {
+	provider, ok := s.registry.Get(providerName)
+	if !ok {
+		return nil, apperrors.ErrOIDCProviderNotFound
+	}
+	if state == "" || code == "" {
+		return nil, apperrors.ErrInvalidOIDCState
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.HashToken(state)), []byte(stateHash)) != 1 {
+		metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, "invalid_state").Inc()
+		return nil, apperrors.ErrInvalidOIDCState
+	}
+
+	// State tek kullanımlıktır; aynı callback adresi ikinci kez kullanılamaz.
+	val, found := s.cache.Take(oidcStateKeyPrefix + state)
+	if !found {
+		metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, "invalid_state").Inc()
+		return nil, apperrors.ErrInvalidOIDCState
+	}
+
+	var loginState oidcLoginState
+	if err := json.Unmarshal([]byte(cacheValueString(val)), &loginState); err != nil || loginState.Provider != providerName {
+		metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, "invalid_state").Inc()
+		return nil, apperrors.ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("WARN: OIDC login via %s failed: %v", providerName, err)
+		metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, "invalid_token").Inc()
+		return nil, apperrors.ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		result := "error"
+		if errors.Is(err, apperrors.ErrOIDCAccountNotLinked) {
+			result = "not_linked"
+		} else if errors.Is(err, apperrors.ErrAccountLocked) {
+			result = "locked"
+		}
+		metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, result).Inc()
+		return nil, err
+	}
+
+	metrics.M.OIDCLoginsTotal.WithLabelValues(providerName, "success").Inc()
+	return user, nil
+}
+
+// resolveUser, IdP hesabını yerel kullanıcıya eşler. Sıra şöyledir:
+//  1. Daha önce bağlanmış (provider, sub) kimliği
+//  2. IdP'nin doğruladığı e-posta adresi ile eşleşen mevcut kullanıcı (kimlik bağlanır)
+//  3. Sağlayıcı izin veriyorsa just-in-time oluşturulan yeni kullanıcı
+//
+// Doğrulanmamış e-posta adresleri ile eşleştirme yapılmaz; aksi halde IdP'de başkasının
+// adresini yazan biri o kullanıcının hesabını ele geçirebilirdi.
+func (s *OIDCService) resolveUser(ctx context.Context, provider *oidc.Provider, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+	identityRepo := uow.UserIdentityRepository()
+	now := time.Now()
+
+	var user *domain.User
+	identity, err := identityRepo.FindBySubject(ctx, provider.Name(), claims.Subject)
+	switch {
+	case err == nil:
+		user, err = userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := identityRepo.TouchLastLogin(ctx, identity.ID, now); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user, err = s.linkOrCreateUser(ctx, uow, provider, claims)
+		if err != nil {
+			return nil, err
+		}
+		if err := identityRepo.Create(ctx, &domain.UserIdentity{
+			UserID:      user.ID,
+			Provider:    provider.Name(),
+			Subject:     claims.Subject,
+			Email:       claims.Email,
+			LastLoginAt: &now,
+		}); err != nil {
+			return nil, err
+		}
+		log.Printf("✓ User %d linked to %s identity %s", user.ID, provider.Name(), claims.Subject)
+	default:
+		return nil, err
+	}
+
+	if user.ServiceAccount {
+		return nil, apperrors.ErrOIDCAccountNotLinked
+	}
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return nil, apperrors.ErrAccountLocked
+	}
+
+	client := auth.GetClientInfo(ctx)
+	if err := uow.LoginAttemptRepository().Create(ctx, &domain.LoginAttempt{
+		UserID:    &user.ID,
+		Email:     strings.ToLower(user.Email),
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Success:   true,
+		Result:    domain.LoginResultSuccess,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *OIDCService) linkOrCreateUser(ctx context.Context, uow repository.IUnitOfWork, provider *oidc.Provider, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	email := strings.TrimSpace(claims.Email)
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, apperrors.ErrOIDCAccountNotLinked
+	}
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		// IdP adresi doğruladığı için yerel hesapta da doğrulanmış sayılır.
+		if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{"email_verified_at": now}); err != nil {
+				return nil, err
+			}
+			user.EmailVerifiedAt = &now
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if !provider.AutoCreate() {
+		return nil, apperrors.ErrOIDCAccountNotLinked
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = email
+	}
+	// Just-in-time kullanıcıların yerel şifresi yoktur; boş özet hiçbir şifre ile eşleşmez.
+	// Organizasyon üyeliği ve yetkiler bir yönetici tarafından ayrıca verilir.
+	verifiedAt := time.Now()
+	created, err := userRepo.Create(ctx, &domain.User{
+		Name:            name,
+		Email:           email,
+		EmailVerifiedAt: &verifiedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✓ User %d created just-in-time from %s login", created.ID, provider.Name())
+	return created, nil
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
+)
+
+// organizationNameMaxLength, organizasyon adının en fazla uzunluğudur (organizations.name sütunu).
+const organizationNameMaxLength = 128
+
+// organizationInvitationTTL, organizasyon davetinin kabul edilebileceği süredir.
+const organizationInvitationTTL = 7 * 24 * time.Hour
+
+// organizationSlugPattern, organizasyon kısa adının biçimidir (organizations.slug sütunu, en fazla 64 karakter).
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IOrganizationService, organizasyonları (kiracıları) ve üyeliklerini yönetir. Yönetici işlemleri
+// her zaman access token'daki aktif organizasyonda yapılır; aktif organizasyonun değiştirilmesi
+// ITokenService.SwitchOrganization ile yapılır.
+type IOrganizationService interface {
+	// ListMine, oturumdaki kullanıcının üyesi olduğu organizasyonları döner.
+	ListMine(ctx context.Context) ([]dto.OrganizationResponse, error)
+	Create(ctx context.Context, req *dto.CreateOrganizationRequest) (*dto.OrganizationResponse, error)
+	UpdateCurrent(ctx context.Context, req *dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error)
+	InviteMember(ctx context.Context, req *dto.InviteOrganizationMemberRequest) error
+	CancelInvitation(ctx context.Context, userID int) error
+	RemoveMember(ctx context.Context, userID int) error
+	// ListMyInvitations, oturumdaki kullanıcının bekleyen davetlerini döner.
+	ListMyInvitations(ctx context.Context) ([]dto.OrganizationInvitationResponse, error)
+	AcceptInvitation(ctx context.Context, organizationID int) error
+	DeclineInvitation(ctx context.Context, organizationID int) error
+}
+
+type OrganizationService struct {
+	uowFactory IUnitOfWorkFactory
+	permCache  IPermissionCache
+}
+
+func NewOrganizationService(uowFactory IUnitOfWorkFactory, permCache IPermissionCache) IOrganizationService {
+	return &OrganizationService{uowFactory: uowFactory, permCache: permCache}
+}
+
+func (s *OrganizationService) ListMine(ctx context.Context) ([]dto.OrganizationResponse, error) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	orgs, err := uow.OrganizationRepository().FindByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := tenant.OrganizationID(ctx)
+	responses := make([]dto.OrganizationResponse, 0, len(orgs))
+	for i := range orgs {
+		responses = append(responses, *toOrganizationResponse(&orgs[i], current))
+	}
+	return responses, nil
+}
+
+// Create, yeni bir organizasyon oluşturur. Oluşturan kullanıcı organizasyonun ilk üyesi olur ve
+// organizasyonun sistem yöneticisi rolü (bkz. domain.SystemAdminRole) kendisine atanır. Yeni
+// organizasyona geçmek için SwitchOrganization kullanılır.
+func (s *OrganizationService) Create(ctx context.Context, req *dto.CreateOrganizationRequest) (*dto.OrganizationResponse, error) {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := normalizeOrganizationName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if len(slug) > 64 || !organizationSlugPattern.MatchString(slug) {
+		return nil, apperrors.ErrValidation
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	orgRepo := uow.OrganizationRepository()
+	if _, err := orgRepo.FindBySlug(ctx, slug); err == nil {
+		return nil, apperrors.ErrOrganizationExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	org := &domain.Organization{Name: name, Slug: slug}
+	if err := orgRepo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	if _, err := orgRepo.AddMember(ctx, org.ID, actor.UserID); err != nil {
+		return nil, err
+	}
+
+	// Rol ve atama yeni organizasyonda oluşturulur.
+	orgCtx := tenant.WithOrganization(ctx, org.ID)
+	adminRole := &domain.Role{
+		Name:        domain.SystemAdminRole,
+		Description: "System administrator with every permission",
+		IsSystem:    true,
+		Permissions: []domain.RolePermission{{Permission: domain.PermissionWildcard}},
+	}
+	if err := uow.RoleRepository().Create(orgCtx, adminRole); err != nil {
+		return nil, err
+	}
+	if _, err := uow.RoleRepository().Assign(orgCtx, actor.UserID, adminRole.ID); err != nil {
+		return nil, err
+	}
+	if err := recordPermissionAudit(orgCtx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditRoleAssigned,
+		TargetUserID: &actor.UserID,
+		RoleID:       &adminRole.ID,
+		RoleName:     adminRole.Name,
+		Detail:       "organization created: " + org.Slug,
+	}); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(orgCtx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityOrganization,
+		EntityID:   strconv.Itoa(org.ID),
+		Action:     domain.AuditActionCreated,
+		Changes:    auditChanges(nil, organizationAuditSnapshot(org)),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	current, _ := tenant.OrganizationID(ctx)
+	return toOrganizationResponse(org, current), nil
+}
+
+// UpdateCurrent, aktif organizasyonun adını değiştirir.
+func (s *OrganizationService) UpdateCurrent(ctx context.Context, req *dto.UpdateOrganizationRequest) (*dto.OrganizationResponse, error) {
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return nil, apperrors.ErrNoOrganization
+	}
+	name, err := normalizeOrganizationName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	org, err := findOrganization(ctx, uow, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	before := organizationAuditSnapshot(org)
+	org.Name = name
+	if err := uow.OrganizationRepository().Update(ctx, org); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityOrganization,
+		EntityID:   strconv.Itoa(org.ID),
+		Action:     domain.AuditActionUpdated,
+		Changes:    auditChanges(before, organizationAuditSnapshot(org)),
+	}); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	return toOrganizationResponse(org, organizationID), nil
+}
+
+// InviteMember, mevcut bir kullanıcıyı aktif organizasyona davet eder. Kullanıcı daveti kabul
+// edene kadar organizasyonun üyesi olmaz; yönetici kullanıcıyı listeleyemez veya hesabını
+// değiştiremez. Davet edilmiş bir kullanıcı tekrar davet edilirse davetin süresi yenilenir.
+// Yeni üyenin organizasyonda rolü yoktur; rolleri ayrıca atanır.
+func (s *OrganizationService) InviteMember(ctx context.Context, req *dto.InviteOrganizationMemberRequest) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return apperrors.ErrNoOrganization
+	}
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return apperrors.ErrValidation
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+	orgRepo := uow.OrganizationRepository()
+	member, err := orgRepo.IsMember(ctx, organizationID, user.ID)
+	if err != nil {
+		return err
+	}
+	if member {
+		return apperrors.ErrAlreadyMember
+	}
+	if err := orgRepo.SaveInvitation(ctx, &domain.OrganizationInvitation{
+		OrganizationID: organizationID,
+		UserID:         user.ID,
+		InvitedByID:    actor.UserID,
+		ExpiresAt:      time.Now().Add(organizationInvitationTTL),
+		CreatedAt:      time.Now(),
+	}); err != nil {
+		return err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditMemberInvited,
+		TargetUserID: &user.ID,
+	}); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// CancelInvitation, aktif organizasyonun kullanıcıya gönderdiği daveti geri alır.
+func (s *OrganizationService) CancelInvitation(ctx context.Context, userID int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return apperrors.ErrNoOrganization
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	deleted, err := uow.OrganizationRepository().DeleteInvitation(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.ErrInvitationNotFound
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditInviteCanceled,
+		TargetUserID: &userID,
+	}); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+func (s *OrganizationService) ListMyInvitations(ctx context.Context) ([]dto.OrganizationInvitationResponse, error) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	invitations, err := uow.OrganizationRepository().FindInvitationsByUser(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OrganizationInvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		responses = append(responses, dto.OrganizationInvitationResponse{
+			OrganizationID:   inv.OrganizationID,
+			OrganizationName: inv.Organization.Name,
+			OrganizationSlug: inv.Organization.Slug,
+			InvitedByID:      inv.InvitedByID,
+			ExpiresAt:        inv.ExpiresAt,
+			CreatedAt:        inv.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// AcceptInvitation, oturumdaki kullanıcıyı davet edildiği organizasyona üye yapar. Davet sadece
+// kullanıcının kendisi tarafından kabul edilebilir; API anahtarı ile veya impersonation sırasında
+// kabul edilemez. Yeni organizasyona geçmek için SwitchOrganization kullanılır.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, organizationID int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	orgRepo := uow.OrganizationRepository()
+	invitation, err := orgRepo.FindInvitation(ctx, organizationID, actor.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvitationNotFound
+		}
+		return err
+	}
+	if invitation.IsExpired() {
+		return apperrors.ErrInvitationNotFound
+	}
+	if _, err := orgRepo.DeleteInvitation(ctx, organizationID, actor.UserID); err != nil {
+		return err
+	}
+	added, err := orgRepo.AddMember(ctx, organizationID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return apperrors.ErrAlreadyMember
+	}
+	// Kayıt davet eden organizasyonun denetim geçmişine yazılır.
+	orgCtx := tenant.WithOrganization(ctx, organizationID)
+	if err := recordPermissionAudit(orgCtx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditMemberAdded,
+		TargetUserID: &actor.UserID,
+		Detail:       "invitation accepted",
+	}); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// DeclineInvitation, oturumdaki kullanıcının bekleyen davetini reddeder.
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, organizationID int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	deleted, err := uow.OrganizationRepository().DeleteInvitation(ctx, organizationID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.ErrInvitationNotFound
+	}
+	orgCtx := tenant.WithOrganization(ctx, organizationID)
+	if err := recordPermissionAudit(orgCtx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditInviteCanceled,
+		TargetUserID: &actor.UserID,
+		Detail:       "invitation declined",
+	}); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// RemoveMember, kullanıcıyı aktif organizasyondan çıkarır; kullanıcının organizasyondaki rolleri
+// ve doğrudan yetkileri de silinir. Yönetici kendisini çıkaramaz. Kullanıcının bu organizasyonu
+// taşıyan access token'ları süreleri dolana kadar geçerlidir ancak yetki önbelleği geçersiz
+// kılındığı için organizasyonda hiçbir yetkisi kalmaz; token yenilendiğinde başka bir
+// organizasyona geçilir.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+	if actor.UserID == userID {
+		return apperrors.ErrPermissionDelegation
+	}
+	organizationID, ok := tenant.OrganizationID(ctx)
+	if !ok {
+		return apperrors.ErrNoOrganization
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	removed, err := uow.OrganizationRepository().RemoveMember(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return apperrors.ErrNotMember
+	}
+	if err := uow.RoleRepository().UnassignAll(ctx, userID); err != nil {
+		return err
+	}
+	if err := uow.PermissionRepository().RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:       domain.PermissionAuditMemberRemoved,
+		TargetUserID: &userID,
+	}); err != nil {
+		return err
+	}
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.permCache.InvalidateUser(ctx, userID)
+	return nil
+}
+
+func findOrganization(ctx context.Context, uow repository.IUnitOfWork, id int) (*domain.Organization, error) {
+	org, err := uow.OrganizationRepository().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+func normalizeOrganizationName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > organizationNameMaxLength {
+		return "", apperrors.ErrValidation
+	}
+	return name, nil
+}
+
+func toOrganizationResponse(org *domain.Organization, current int) *dto.OrganizationResponse {
+	return &dto.OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		Current:   org.ID == current,
+		CreatedAt: org.CreatedAt,
+	}
+}
@@ -0,0 +1,736 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/repository"
+)
+
+// permissionAuditLimit, kullanıcı başına listelenen en fazla denetim kaydı sayısıdır.
+const permissionAuditLimit = 200
+
+// roleNameMaxLength, rol adının en fazla uzunluğudur (roles.name sütunu).
+const roleNameMaxLength = 64
+
+// ListRoles, tüm rolleri kendi yetkileri ile birlikte listeler.
+func (s *PermissionService) ListRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	roles, err := uow.RoleRepository().FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.RoleResponse, 0, len(roles))
+	for i := range roles {
+		responses = append(responses, *toRoleResponse(&roles[i]))
+	}
+	return responses, nil
+}
+
+func (s *PermissionService) GetRole(ctx context.Context, id int) (*dto.RoleResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	role, err := findRole(ctx, uow, id)
+	if err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// CreateRole, yeni bir rol oluşturur. Yönetici sadece kendi sahip olduğu yetkileri (üst rolden
+// devralınanlar dahil) içeren bir rol oluşturabilir; aksi halde rol yetki yükseltmek için kullanılabilir.
+func (s *PermissionService) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := normalizeRoleName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	perms, err := normalizeGrants(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureRoleNameAvailable(ctx, uow, name); err != nil {
+		return nil, err
+	}
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureDelegable(held, perms); err != nil {
+		return nil, err
+	}
+	if err := ensureParentDelegable(ctx, uow, held, 0, req.ParentID); err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		ParentID:    req.ParentID,
+		Permissions: make([]domain.RolePermission, 0, len(perms)),
+	}
+	for _, p := range perms {
+		role.Permissions = append(role.Permissions, domain.RolePermission{Permission: p})
+	}
+	if err := uow.RoleRepository().Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:   domain.PermissionAuditRoleCreated,
+		RoleID:   &role.ID,
+		RoleName: role.Name,
+		Detail:   strings.Join(perms, ","),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	return toRoleResponse(role), nil
+}
+
+// UpdateRole, rolün adını, açıklamasını, üst rolünü ve yetkilerini günceller. Eklenen ve
+// çıkarılan yetkilerin ikisi de yöneticinin sahip olduğu yetkiler olmalıdır.
+func (s *PermissionService) UpdateRole(ctx context.Context, id int, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := normalizeRoleName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	perms, err := normalizeGrants(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	role, err := findRole(ctx, uow, id)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsSystem {
+		return nil, apperrors.ErrSystemRole
+	}
+	if name != role.Name {
+		if err := ensureRoleNameAvailable(ctx, uow, name); err != nil {
+			return nil, err
+		}
+	}
+
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	added, removed := diffGrants(rolePermissionNames(role), perms)
+	if err := ensureDelegable(held, append(append([]string{}, added...), removed...)); err != nil {
+		return nil, err
+	}
+	parentChanged := !sameParent(role.ParentID, req.ParentID)
+	if parentChanged {
+		if err := ensureParentDelegable(ctx, uow, held, role.ID, req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	role.Name = name
+	role.Description = strings.TrimSpace(req.Description)
+	role.ParentID = req.ParentID
+	if err := uow.RoleRepository().Update(ctx, role); err != nil {
+		return nil, err
+	}
+	if err := uow.RoleRepository().ReplacePermissions(ctx, role.ID, perms); err != nil {
+		return nil, err
+	}
+
+	detail := fmt.Sprintf("added=%s; removed=%s", strings.Join(added, ","), strings.Join(removed, ","))
+	if parentChanged {
+		detail += "; parent=" + formatParent(req.ParentID)
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:   domain.PermissionAuditRoleUpdated,
+		RoleID:   &role.ID,
+		RoleName: role.Name,
+		Detail:   detail,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+	// Rolün yetkileri veya kalıtımı rolü doğrudan ya da dolaylı taşıyan tüm kullanıcıları etkiler.
+	s.permCache.InvalidateAll(ctx)
+
+	role.Permissions = make([]domain.RolePermission, 0, len(perms))
+	for _, p := range perms {
+		role.Permissions = append(role.Permissions, domain.RolePermission{RoleID: role.ID, Permission: p})
+	}
+	return toRoleResponse(role), nil
+}
+
+// DeleteRole, rolü ve kullanıcı atamalarını siler. Sistem rolleri ve başka rollerin üst rolü
+// olan roller silinemez.
+func (s *PermissionService) DeleteRole(ctx context.Context, id int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	role, err := findRole(ctx, uow, id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return apperrors.ErrSystemRole
+	}
+	children, err := uow.RoleRepository().CountChildren(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return apperrors.ErrRoleHasChildren
+	}
+
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if err := ensureDelegable(held, rolePermissionNames(role)); err != nil {
+		return err
+	}
+
+	if err := uow.RoleRepository().Delete(ctx, role.ID); err != nil {
+		return err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:   domain.PermissionAuditRoleDeleted,
+		RoleID:   &role.ID,
+		RoleName: role.Name,
+		Detail:   strings.Join(rolePermissionNames(role), ","),
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	s.permCache.InvalidateAll(ctx)
+	return nil
+}
+
+// GetUserAccess, kullanıcıya atanmış rolleri ve doğrudan verilmiş yetkileri döner.
+func (s *PermissionService) GetUserAccess(ctx context.Context, userID int) (*dto.UserAccessResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return nil, err
+	}
+	roles, err := uow.RoleRepository().FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := uow.PermissionRepository().FindUserGrants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.UserAccessResponse{
+		UserID: userID,
+		Roles:  make([]dto.RoleResponse, 0, len(roles)),
+		Grants: make([]dto.UserGrantResponse, 0, len(grants)),
+	}
+	for i := range roles {
+		resp.Roles = append(resp.Roles, *toRoleResponse(&roles[i]))
+	}
+	for _, g := range grants {
+		resp.Grants = append(resp.Grants, dto.UserGrantResponse{
+			Permission: g.Permission,
+			GrantedBy:  g.GrantedBy,
+			CreatedAt:  g.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// AssignRole, kullanıcıya rol atar. Yönetici sadece tüm yetkilerine (devralınanlar dahil)
+// sahip olduğu rolleri atayabilir ve kendi rollerini değiştiremez. Rol zaten atanmışsa işlem yapılmaz.
+func (s *PermissionService) AssignRole(ctx context.Context, userID, roleID int) error {
+	return s.changeUserRole(ctx, userID, roleID, true)
+}
+
+// UnassignRole, kullanıcıdan rolü kaldırır. Atama yoksa apperrors.ErrNotFound döner.
+func (s *PermissionService) UnassignRole(ctx context.Context, userID, roleID int) error {
+	return s.changeUserRole(ctx, userID, roleID, false)
+}
+
+func (s *PermissionService) changeUserRole(ctx context.Context, userID, roleID int, assign bool) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+	if actor.UserID == userID {
+		return apperrors.ErrPermissionDelegation
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return err
+	}
+	role, err := findRole(ctx, uow, roleID)
+	if err != nil {
+		return err
+	}
+	rolePerms, err := uow.RoleRepository().GetPermissions(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if err := ensureDelegable(held, rolePerms); err != nil {
+		return err
+	}
+
+	action := domain.PermissionAuditRoleAssigned
+	var changed bool
+	if assign {
+		changed, err = uow.RoleRepository().Assign(ctx, userID, role.ID)
+	} else {
+		action = domain.PermissionAuditRoleUnassigned
+		changed, err = uow.RoleRepository().Unassign(ctx, userID, role.ID)
+	}
+	if err != nil {
+		return err
+	}
+	if !changed {
+		if assign {
+			return nil
+		}
+		return apperrors.ErrNotFound
+	}
+
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:       action,
+		TargetUserID: &userID,
+		RoleID:       &role.ID,
+		RoleName:     role.Name,
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	s.permCache.InvalidateUser(ctx, userID)
+	return nil
+}
+
+// GrantPermission, kullanıcıya rol dışında doğrudan yetki verir. Yetki zaten verilmişse işlem yapılmaz.
+func (s *PermissionService) GrantPermission(ctx context.Context, userID int, perm string) error {
+	return s.changeUserGrant(ctx, userID, perm, true)
+}
+
+// RevokePermission, kullanıcıya doğrudan verilen yetkiyi geri alır. Rollerden gelen yetkiler
+// bu yolla geri alınamaz; yetki verilmemişse apperrors.ErrNotFound döner.
+func (s *PermissionService) RevokePermission(ctx context.Context, userID int, perm string) error {
+	return s.changeUserGrant(ctx, userID, perm, false)
+}
+
+func (s *PermissionService) changeUserGrant(ctx context.Context, userID int, perm string, grant bool) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+	if actor.UserID == userID {
+		return apperrors.ErrPermissionDelegation
+	}
+	perms, err := normalizeGrants([]string{perm})
+	if err != nil {
+		return err
+	}
+	perm = perms[0]
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return err
+	}
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if err := ensureDelegable(held, perms); err != nil {
+		return err
+	}
+
+	action := domain.PermissionAuditPermissionGranted
+	var changed bool
+	if grant {
+		changed, err = uow.PermissionRepository().Grant(ctx, &domain.UserGrant{
+			UserID:     userID,
+			Permission: perm,
+			GrantedBy:  actor.UserID,
+		})
+	} else {
+		action = domain.PermissionAuditPermissionRevoked
+		changed, err = uow.PermissionRepository().Revoke(ctx, userID, perm)
+	}
+	if err != nil {
+		return err
+	}
+	if !changed {
+		if grant {
+			return nil
+		}
+		return apperrors.ErrNotFound
+	}
+
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action:       action,
+		TargetUserID: &userID,
+		Permission:   perm,
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+	s.permCache.InvalidateUser(ctx, userID)
+	return nil
+}
+
+// GetEffectivePermissions, kullanıcının rolleri, kalıtım ve doğrudan yetkiler sonucunda sahip
+// olduğu yetkileri döner.
+func (s *PermissionService) GetEffectivePermissions(ctx context.Context, userID int) (*dto.EffectivePermissionsResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return nil, err
+	}
+	return effectivePermissions(ctx, uow, userID, func(string, string) bool { return true })
+}
+
+// GetMyPermissions, oturumdaki kullanıcının yetkilerini döner. İstek bir API anahtarı ile
+// yapıldıysa liste anahtarın kapsamı ile sınırlanır; arayüz menüleri bu listeye göre gösterir.
+func (s *PermissionService) GetMyPermissions(ctx context.Context, user *auth.AuthUser) (*dto.EffectivePermissionsResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	return effectivePermissions(ctx, uow, user.UserID, user.AllowsScope)
+}
+
+// ListAuditLog, kullanıcının yetkilerinde yapılan ve kullanıcının yaptığı değişiklikleri listeler.
+func (s *PermissionService) ListAuditLog(ctx context.Context, userID int) ([]dto.PermissionAuditResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return nil, err
+	}
+	entries, err := uow.PermissionAuditRepository().FindByUser(ctx, userID, permissionAuditLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PermissionAuditResponse, 0, len(entries))
+	for _, e := range entries {
+		responses = append(responses, dto.PermissionAuditResponse{
+			ID:           e.ID,
+			Action:       e.Action,
+			ActorID:      e.ActorID,
+			TargetUserID: e.TargetUserID,
+			RoleID:       e.RoleID,
+			RoleName:     e.RoleName,
+			Permission:   e.Permission,
+			Detail:       e.Detail,
+			IP:           e.IP,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// effectivePermissions, kullanıcının rollerini ve ham yetkilerini okur; ham yetkileri registry'deki
+// yetkilere açarken allows ile ek bir filtre (örn. API anahtarı kapsamı) uygular.
+func effectivePermissions(ctx context.Context, uow repository.IUnitOfWork, userID int, allows func(resource, action string) bool) (*dto.EffectivePermissionsResponse, error) {
+	roles, err := uow.RoleRepository().FindEffectiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := uow.PermissionRepository().GetEffectivePermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.EffectivePermissionsResponse{
+		UserID:      userID,
+		Roles:       make([]string, 0, len(roles)),
+		Grants:      append([]string{}, grants...),
+		Permissions: []string{},
+	}
+	for _, r := range roles {
+		resp.Roles = append(resp.Roles, r.Name)
+	}
+	for _, d := range permission.All() {
+		if domain.PermissionGranted(grants, d.Resource, d.Action) && allows(d.Resource, d.Action) {
+			resp.Permissions = append(resp.Permissions, d.String())
+		}
+	}
+	return resp, nil
+}
+
+// currentPermissionActor, yetki değişikliğini yapan kullanıcıyı döner. Yetki yönetimi API anahtarı
+// ile veya impersonation sırasında yapılamaz; değişiklik her zaman gerçek bir yöneticiye atfedilir.
+func currentPermissionActor(ctx context.Context) (*auth.AuthUser, error) {
+	actor, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+	if actor.IsAPIKey() {
+		return nil, apperrors.ErrAPIKeyNotAllowed
+	}
+	if actor.IsImpersonated() {
+		return nil, apperrors.ErrImpersonationBlocked
+	}
+	return actor, nil
+}
+
+// recordPermissionAudit, denetim kaydını değişiklik ile aynı transaction'da yazar. Kayıt, kullanıcı
+// geçmişi için permission_audit_logs'a, tüm değişikliklerle birlikte sorgulanabilmesi için audit_events'e yazılır.
+func recordPermissionAudit(ctx context.Context, uow repository.IUnitOfWork, actor *auth.AuthUser, entry *domain.PermissionAuditLog) error {
+	client := auth.GetClientInfo(ctx)
+	entry.ActorID = actor.UserID
+	entry.IP = client.IP
+	entry.RequestID = client.RequestID
+	if err := uow.PermissionAuditRepository().Create(ctx, entry); err != nil {
+		return err
+	}
+	return recordAudit(ctx, uow, permissionAuditEvent(entry))
+}
+
+// permissionAuditEvent, yetki denetim kaydını audit_events kaydına çevirir. Yetkisi değişen bir
+// kullanıcı varsa kayıt kullanıcıya, yoksa role veya politikaya aittir.
+func permissionAuditEvent(entry *domain.PermissionAuditLog) *domain.AuditEvent {
+	event := &domain.AuditEvent{Action: entry.Action}
+	switch {
+	case entry.TargetUserID != nil:
+		event.EntityType = domain.AuditEntityUser
+		event.EntityID = strconv.Itoa(*entry.TargetUserID)
+	case entry.RoleID != nil:
+		event.EntityType = domain.AuditEntityRole
+		event.EntityID = strconv.Itoa(*entry.RoleID)
+	default:
+		// Politika kayıtlarında Detail politika adı ile başlar (bkz. policyAuditDetail).
+		event.EntityType = domain.AuditEntityPolicy
+		event.EntityID, _, _ = strings.Cut(entry.Detail, " ")
+	}
+
+	details := map[string]any{}
+	if entry.RoleID != nil {
+		details["roleId"] = *entry.RoleID
+	}
+	if entry.RoleName != "" {
+		details["role"] = entry.RoleName
+	}
+	if entry.Permission != "" {
+		details["permission"] = entry.Permission
+	}
+	if entry.Detail != "" {
+		details["detail"] = entry.Detail
+	}
+	event.Changes = auditChanges(nil, details)
+	return event
+}
+
+// ensureDelegable, yöneticinin verdiği veya geri aldığı tüm yetkilere kendisinin de sahip olduğunu doğrular.
+func ensureDelegable(held, perms []string) error {
+	for _, p := range perms {
+		if !domain.PermissionCovers(held, p) {
+			return apperrors.ErrPermissionDelegation
+		}
+	}
+	return nil
+}
+
+// ensureParentDelegable, üst rolün var olduğunu, kalıtımın döngü oluşturmadığını ve üst rolden
+// devralınacak yetkilere yöneticinin sahip olduğunu doğrular. roleID yeni roller için 0'dır.
+func ensureParentDelegable(ctx context.Context, uow repository.IUnitOfWork, held []string, roleID int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	seen := map[int]bool{}
+	for id := *parentID; ; {
+		if id == roleID || seen[id] {
+			return apperrors.ErrValidation
+		}
+		seen[id] = true
+
+		ancestor, err := findRole(ctx, uow, id)
+		if err != nil {
+			return err
+		}
+		if ancestor.ParentID == nil {
+			break
+		}
+		id = *ancestor.ParentID
+	}
+
+	inherited, err := uow.RoleRepository().GetPermissions(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	return ensureDelegable(held, inherited)
+}
+
+func ensureRoleNameAvailable(ctx context.Context, uow repository.IUnitOfWork, name string) error {
+	_, err := uow.RoleRepository().FindByName(ctx, name)
+	if err == nil {
+		return apperrors.ErrRoleExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func ensureUserExists(ctx context.Context, uow repository.IUnitOfWork, userID int) error {
+	if _, err := uow.UserRepository().FindByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func findRole(ctx context.Context, uow repository.IUnitOfWork, id int) (*domain.Role, error) {
+	role, err := uow.RoleRepository().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func normalizeRoleName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > roleNameMaxLength {
+		return "", apperrors.ErrValidation
+	}
+	return name, nil
+}
+
+// normalizeGrants, yetkileri registry'ye göre doğrular, tekrarları eler ve sıralar.
+func normalizeGrants(perms []string) ([]string, error) {
+	set := make(map[string]struct{}, len(perms))
+	for _, p := range perms {
+		p = strings.TrimSpace(p)
+		if !permission.ValidGrant(p) {
+			return nil, apperrors.ErrInvalidPermission
+		}
+		set[p] = struct{}{}
+	}
+
+	normalized := make([]string, 0, len(set))
+	for p := range set {
+		normalized = append(normalized, p)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// diffGrants, iki yetki listesi arasında eklenen ve çıkarılan yetkileri döner.
+func diffGrants(current, next []string) (added, removed []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, p := range current {
+		currentSet[p] = true
+	}
+	nextSet := make(map[string]bool, len(next))
+	for _, p := range next {
+		nextSet[p] = true
+		if !currentSet[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range current {
+		if !nextSet[p] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatParent(parentID *int) string {
+	if parentID == nil {
+		return "none"
+	}
+	return fmt.Sprint(*parentID)
+}
+
+func rolePermissionNames(role *domain.Role) []string {
+	names := make([]string, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		names = append(names, p.Permission)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toRoleResponse(role *domain.Role) *dto.RoleResponse {
+	return &dto.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		ParentID:    role.ParentID,
+		IsSystem:    role.IsSystem,
+		Permissions: rolePermissionNames(role),
+		CreatedAt:   role.CreatedAt,
+	}
+}
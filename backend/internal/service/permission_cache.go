@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"ths-erp.com/internal/platform/cache"
+	"ths-erp.com/internal/platform/metrics"
+)
+
+const (
+	permissionCacheKeyPrefix      = "permissions:"
+	permissionGenerationKey       = "permissions:generation"
+	permissionVersionKeyPrefix    = "permissions:version:user:"
+	permissionInvalidationChannel = "permissions:invalidate"
+)
+
+// ResolvedAccess, kullanıcının çözümlenmiş erişim bilgisidir: yetki kümesi (roller, kalıtım ve
+// doğrudan yetkiler birleştirilmiş hali) ve devralınanlar dahil rol adları (politikalar için).
+type ResolvedAccess struct {
+	Permissions []string `json:"permissions"`
+	Roles       []string `json:"roles"`
+}
+
+// tenantAccess, bir kullanıcının organizasyon bazında çözümlenmiş erişim bilgileridir. Roller ve
+// yetkiler organizasyona göre atandığı için aynı kullanıcının her organizasyonda ayrı bir kaydı olur;
+// hepsi kullanıcının tek anahtarında tutulur, böylece InvalidateUser tek silme ile hepsini düşürür.
+type tenantAccess map[int]*ResolvedAccess
+
+// IPermissionCache, kullanıcıların organizasyon bazında çözümlenmiş erişim bilgilerini önbellekte tutar.
+type IPermissionCache interface {
+	Get(userID, organizationID int) (*ResolvedAccess, bool)
+	// Version, kullanıcının önbellek sürümünü döner. Veritabanından okumadan önce alınıp Set'e verilir;
+	// okuma sırasında kullanıcı veya tüm önbellek geçersiz kılındıysa Set eskimiş veriyi yazmaz.
+	Version(userID int) string
+	Set(userID, organizationID int, version string, access *ResolvedAccess)
+	// InvalidateUser, tek bir kullanıcının yetki kümesini tüm instance'larda geçersiz kılar.
+	InvalidateUser(ctx context.Context, userID int)
+	// InvalidateAll, tüm kullanıcıların yetki kümelerini tüm instance'larda geçersiz kılar
+	// (örn. bir rolün yetkileri veya kalıtımı değiştiğinde).
+	InvalidateAll(ctx context.Context)
+}
+
+// PermissionCache, iki katmanlı yetki önbelleğidir: instance içi yerel önbellek ve instance'lar
+// arasında paylaşılan Redis. Anahtarlar bir "nesil" içerir; InvalidateAll yeni bir nesil başlatır,
+// eski nesildeki anahtarlar TTL dolunca kendiliğinden düşer. Geçersiz kılma mesajları Redis
+// pub/sub ile diğer instance'lara iletilir; bir mesaj kaçırılırsa eskimiş veri en fazla TTL kadar yaşar.
+// Ayrıca her kullanıcının Redis'te bir sürümü vardır; InvalidateUser sürümü değiştirir ve Set, okuma
+// başladıktan sonra sürümü veya nesli değişen kullanıcılar için veri yazmaz (bkz. Version).
+type PermissionCache struct {
+	local  cache.ICache
+	shared cache.ICache
+	pubsub cache.IPubSub
+	ttl    time.Duration
+	// instanceID, bu instance'ın yayınladığı mesajları ayırt etmek için kullanılır.
+	instanceID string
+
+	mu         sync.RWMutex
+	generation string
+}
+
+// NewPermissionCache, yetki önbelleğini oluşturur ve geçersiz kılma kanalına abone olur.
+// pubsub nil ise geçersiz kılmalar sadece bu instance'ta ve Redis'te uygulanır.
+func NewPermissionCache(ctx context.Context, local, shared cache.ICache, pubsub cache.IPubSub, ttl time.Duration) IPermissionCache {
+	c := &PermissionCache{
+		local:      local,
+		shared:     shared,
+		pubsub:     pubsub,
+		ttl:        ttl,
+		instanceID: uuid.New().String(),
+	}
+
+	if val, ok := shared.Get(permissionGenerationKey); ok {
+		c.generation = cacheValueString(val)
+	} else {
+		c.generation = newPermissionGeneration()
+		shared.Set(permissionGenerationKey, c.generation, 0)
+	}
+
+	if pubsub != nil {
+		pubsub.Subscribe(ctx, permissionInvalidationChannel, c.handleInvalidation)
+	}
+	return c
+}
+
+func (c *PermissionCache) Get(userID, organizationID int) (*ResolvedAccess, bool) {
+	key := c.key(userID)
+
+	if val, ok := c.local.Get(key); ok {
+		if entries, ok := val.(tenantAccess); ok {
+			if access, ok := entries[organizationID]; ok {
+				metrics.M.PermissionCacheRequestsTotal.WithLabelValues("hit_local").Inc()
+				return access, true
+			}
+		}
+	}
+
+	if entries, ok := c.loadShared(key); ok {
+		c.local.Set(key, entries, c.ttl)
+		if access, ok := entries[organizationID]; ok {
+			metrics.M.PermissionCacheRequestsTotal.WithLabelValues("hit_redis").Inc()
+			return access, true
+		}
+	}
+
+	metrics.M.PermissionCacheRequestsTotal.WithLabelValues("miss").Inc()
+	return nil, false
+}
+
+func (c *PermissionCache) Version(userID int) string {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	var version string
+	if val, ok := c.shared.Get(permissionVersionKeyPrefix + strconv.Itoa(userID)); ok {
+		version = cacheValueString(val)
+	}
+	return generation + "/" + version
+}
+
+// Set, kullanıcının bir organizasyondaki erişim bilgisini diğer organizasyonlardakilerle birlikte
+// yazar. Yerel önbellekteki map paylaşıldığı için yerinde değiştirilmez, kopyası yazılır.
+// Okuma-birleştirme-yazma atomik değildir; bu yüzden sürüm yazmadan önce ve sonra kontrol edilir.
+// Yazma sırasında araya bir geçersiz kılma girdiyse yazılan kayıt silinir. Aynı anda farklı
+// organizasyonlar için yapılan iki Set birbirinin kaydını düşürebilir; bu sadece bir sonraki
+// okumada önbellek ıskalamasına yol açar.
+func (c *PermissionCache) Set(userID, organizationID int, version string, access *ResolvedAccess) {
+	if c.Version(userID) != version {
+		return
+	}
+
+	key := c.key(userID)
+	entries := tenantAccess{}
+	if existing, ok := c.loadShared(key); ok {
+		entries = existing
+	}
+	entries[organizationID] = access
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	c.shared.Set(key, string(data), c.ttl)
+	c.local.Set(key, entries, c.ttl)
+
+	// InvalidateUser önce sürümü değiştirip sonra anahtarı sildiği için, silmeden sonra yazılmış
+	// bir kayıt burada yeni sürümle fark edilir.
+	if c.Version(userID) != version {
+		c.local.Delete(key)
+		c.shared.Delete(key)
+	}
+}
+
+func (c *PermissionCache) loadShared(key string) (tenantAccess, bool) {
+	val, ok := c.shared.Get(key)
+	if !ok {
+		return nil, false
+	}
+	var entries tenantAccess
+	if err := json.Unmarshal([]byte(cacheValueString(val)), &entries); err != nil || entries == nil {
+		return nil, false
+	}
+	return entries, true
+}
+
+func (c *PermissionCache) InvalidateUser(ctx context.Context, userID int) {
+	c.shared.Set(permissionVersionKeyPrefix+strconv.Itoa(userID), uuid.New().String(), c.ttl)
+	c.dropUser(userID)
+	metrics.M.PermissionCacheInvalidationsTotal.WithLabelValues("user", "local").Inc()
+	c.publish(ctx, "user:"+strconv.Itoa(userID))
+}
+
+func (c *PermissionCache) InvalidateAll(ctx context.Context) {
+	generation := newPermissionGeneration()
+	c.shared.Set(permissionGenerationKey, generation, 0)
+	c.setGeneration(generation)
+	metrics.M.PermissionCacheInvalidationsTotal.WithLabelValues("all", "local").Inc()
+	c.publish(ctx, "all:"+generation)
+}
+
+// handleInvalidation, diğer instance'lardan gelen geçersiz kılma mesajlarını uygular. Mesajlar
+// "<instance> user:<id>" veya "<instance> all:<nesil>" biçimindedir; kendi mesajlarımız atlanır.
+func (c *PermissionCache) handleInvalidation(message string) {
+	origin, body, ok := strings.Cut(message, " ")
+	if !ok || origin == c.instanceID {
+		return
+	}
+	scope, value, ok := strings.Cut(body, ":")
+	if !ok {
+		return
+	}
+
+	switch scope {
+	case "user":
+		userID, err := strconv.Atoi(value)
+		if err != nil {
+			return
+		}
+		// Paylaşılan anahtar da silinir; instance'lar farklı nesillerde olsa bile eskimiş kayıt kalmaz.
+		c.dropUser(userID)
+	case "all":
+		if value == "" {
+			return
+		}
+		c.setGeneration(value)
+	default:
+		return
+	}
+	metrics.M.PermissionCacheInvalidationsTotal.WithLabelValues(scope, "remote").Inc()
+}
+
+func (c *PermissionCache) dropUser(userID int) {
+	key := c.key(userID)
+	c.local.Delete(key)
+	c.shared.Delete(key)
+}
+
+func (c *PermissionCache) publish(ctx context.Context, message string) {
+	if c.pubsub == nil {
+		return
+	}
+	if err := c.pubsub.Publish(ctx, permissionInvalidationChannel, c.instanceID+" "+message); err != nil {
+		log.Printf("ERROR: Could not publish permission cache invalidation %q: %v", message, err)
+	}
+}
+
+func (c *PermissionCache) key(userID int) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return permissionCacheKeyPrefix + c.generation + ":user:" + strconv.Itoa(userID)
+}
+
+func (c *PermissionCache) setGeneration(generation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation = generation
+}
+
+func newPermissionGeneration() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
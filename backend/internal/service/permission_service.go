@@ -2,25 +2,189 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/policy"
+	"ths-erp.com/internal/tenant"
 )
 
+// decidedByAPIKeyScope, isteğin API anahtarının kapsamı nedeniyle reddedildiğini gösterir.
+const decidedByAPIKeyScope = "api_key_scope"
+
 type IPermissionService interface {
-	CheckPermission(ctx context.Context, userID int, resource, action string) (bool, error)
+	CheckPermission(ctx context.Context, userID int, perm permission.Permission) (bool, error)
+	// Authorize, yetkiyi kaynak ve ortam öznitelikleri ile birlikte politikalara göre değerlendirir.
+	// Kaynak özniteliği gerektirmeyen kontroller için CheckPermission yeterlidir.
+	Authorize(ctx context.Context, userID int, perm permission.Permission, opts AuthorizeOptions) (*policy.Decision, error)
+	ListPermissions() []dto.PermissionResponse
+
+	// Rol yönetimi
+	ListRoles(ctx context.Context) ([]dto.RoleResponse, error)
+	GetRole(ctx context.Context, id int) (*dto.RoleResponse, error)
+	CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(ctx context.Context, id int, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(ctx context.Context, id int) error
+
+	// Kullanıcı yetkileri
+	GetUserAccess(ctx context.Context, userID int) (*dto.UserAccessResponse, error)
+	AssignRole(ctx context.Context, userID, roleID int) error
+	UnassignRole(ctx context.Context, userID, roleID int) error
+	GrantPermission(ctx context.Context, userID int, perm string) error
+	RevokePermission(ctx context.Context, userID int, perm string) error
+	GetEffectivePermissions(ctx context.Context, userID int) (*dto.EffectivePermissionsResponse, error)
+	GetMyPermissions(ctx context.Context, user *auth.AuthUser) (*dto.EffectivePermissionsResponse, error)
+	ListAuditLog(ctx context.Context, userID int) ([]dto.PermissionAuditResponse, error)
+}
+
+// AuthorizeOptions, Authorize için kaynak ve ortam bilgilerini taşır.
+type AuthorizeOptions struct {
+	// Resource, erişilen kaynağın öznitelikleridir (örn. {"id": 5, "amount": 9000}).
+	// resource.type her zaman yetkinin kaynağıdır.
+	Resource policy.Attributes
+	// Env, hesaplanan ortam özniteliklerine eklenir veya onları değiştirir (örn. dry-run için env.hour).
+	Env policy.Attributes
+	// Extra, sadece bu değerlendirmede uygulanacak, kaydedilmemiş politikalardır (dry-run).
+	Extra   []policy.Policy
+	Explain bool
 }
 
 type PermissionService struct {
 	uowFactory IUnitOfWorkFactory
+	permCache  IPermissionCache
+	engine     *policy.Engine
 }
 
-func NewPermissionService(uowFactory IUnitOfWorkFactory) IPermissionService {
-	return &PermissionService{uowFactory: uowFactory}
+func NewPermissionService(uowFactory IUnitOfWorkFactory, permCache IPermissionCache, engine *policy.Engine) IPermissionService {
+	return &PermissionService{uowFactory: uowFactory, permCache: permCache, engine: engine}
+}
+
+// CheckPermission, kullanıcının yetkisini kaynak özniteliği olmadan kontrol eder (bkz. Authorize).
+func (s *PermissionService) CheckPermission(ctx context.Context, userID int, perm permission.Permission) (bool, error) {
+	decision, err := s.Authorize(ctx, userID, perm, AuthorizeOptions{})
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// Authorize, kullanıcının yetkisini rol yetkileri ve politikalar ile değerlendirir. Registry'de
+// tanımlı olmayan yetkiler hata döner. İstek bir API anahtarı ile yapıldıysa (context'teki
+// kullanıcı), anahtarın kapsamı dışındaki işlemler politikalardan bağımsız olarak reddedilir;
+// böylece REST, GraphQL ve servisler aynı kuralı uygular.
+func (s *PermissionService) Authorize(ctx context.Context, userID int, perm permission.Permission, opts AuthorizeOptions) (*policy.Decision, error) {
+	if err := permission.Validate(perm); err != nil {
+		return nil, err
+	}
+
+	user, err := auth.GetUserFromContext(ctx)
+	self := err == nil && user.UserID == userID
+	if self && !user.AllowsScope(perm.Resource, perm.Action) {
+		metrics.M.PermissionChecksTotal.WithLabelValues(perm.Resource, perm.Action, "false").Inc()
+		return &policy.Decision{
+			DecidedBy: decidedByAPIKeyScope,
+			Reason:    fmt.Sprintf("%s is outside the API key scope", perm),
+		}, nil
+	}
+
+	organizationID, _ := tenant.OrganizationID(ctx)
+	access, err := s.resolveAccess(ctx, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := policy.Attributes{
+		"id":              userID,
+		"organization_id": organizationID,
+		"roles":           access.Roles,
+		"permissions":     access.Permissions,
+	}
+	if self {
+		subject["email"] = user.Email
+		subject["api_key"] = user.IsAPIKey()
+		subject["impersonated"] = user.IsImpersonated()
+	}
+
+	resource := policy.Attributes{}
+	for k, v := range opts.Resource {
+		resource[k] = v
+	}
+	resource["type"] = perm.Resource
+
+	env := policy.Attributes{}
+	if ip := auth.GetClientInfo(ctx).IP; ip != "" {
+		env["ip"] = ip
+	}
+	for k, v := range opts.Env {
+		env[k] = v
+	}
+
+	decision := s.engine.Evaluate(&policy.Request{
+		OrganizationID: organizationID,
+		Subject:        subject,
+		Action:         perm.String(),
+		Resource:       resource,
+		Env:            s.engine.Env(time.Now(), env),
+	}, access.Permissions, opts.Extra, opts.Explain)
+
+	metrics.M.PermissionChecksTotal.WithLabelValues(perm.Resource, perm.Action, strconv.FormatBool(decision.Allowed)).Inc()
+	return decision, nil
 }
 
-func (s *PermissionService) CheckPermission(ctx context.Context, userID int, resource, action string) (bool, error) {
+// resolveAccess, kullanıcının organizasyondaki çözümlenmiş yetki kümesini ve rollerini önbellekten,
+// yoksa veritabanından okur. Yetki, rol veya kullanıcı değişikliklerinde önbellek geçersiz kılınır
+// (bkz. PermissionCache). Aktif organizasyon yoksa kullanıcının hiçbir rolü ve yetkisi yoktur.
+func (s *PermissionService) resolveAccess(ctx context.Context, userID, organizationID int) (*ResolvedAccess, error) {
+	if organizationID == 0 {
+		return &ResolvedAccess{Permissions: []string{}, Roles: []string{}}, nil
+	}
+	if access, ok := s.permCache.Get(userID, organizationID); ok {
+		return access, nil
+	}
+	// Sürüm veritabanından okumadan önce alınır; okuma sırasında yapılan bir değişiklikten sonra
+	// eskimiş sonuç önbelleğe yazılmaz.
+	version := s.permCache.Version(userID)
+
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback() // Read-only operation
 
-	// Bu katman şimdilik direkt repository'i çağırıyor,
-	// ileride cache'leme gibi ek iş mantıkları buraya eklenebilir.
-	return uow.PermissionRepository().CheckPermission(ctx, userID, resource, action)
+	granted, err := uow.PermissionRepository().GetEffectivePermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := uow.RoleRepository().FindEffectiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := &ResolvedAccess{Permissions: granted, Roles: make([]string, 0, len(roles))}
+	if access.Permissions == nil {
+		access.Permissions = []string{}
+	}
+	for _, r := range roles {
+		access.Roles = append(access.Roles, r.Name)
+	}
+
+	s.permCache.Set(userID, organizationID, version, access)
+	return access, nil
+}
+
+// ListPermissions, registry'deki tüm yetkileri yönetim arayüzleri için döner.
+func (s *PermissionService) ListPermissions() []dto.PermissionResponse {
+	definitions := permission.All()
+	responses := make([]dto.PermissionResponse, 0, len(definitions))
+	for _, d := range definitions {
+		responses = append(responses, dto.PermissionResponse{
+			Permission:  d.String(),
+			Resource:    d.Resource,
+			Action:      d.Action,
+			Description: d.Description,
+		})
+	}
+	return responses
 }
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/permission"
+	"ths-erp.com/internal/platform/cache"
+	"ths-erp.com/internal/policy"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
+)
+
+// policyReloadChannel, politikalar değiştiğinde diğer instance'lara yeniden yükleme mesajı gönderilen kanaldır.
+const policyReloadChannel = "policies:reload"
+
+// policyNameMaxLength, politika adının en fazla uzunluğudur (policies.name sütunu).
+const policyNameMaxLength = 128
+
+// IPolicyService, dosyalardan ve veritabanından yüklenen yetkilendirme politikalarını yönetir.
+// Politikaların değerlendirilmesi IPermissionService.Authorize ile yapılır.
+type IPolicyService interface {
+	ListPolicies(ctx context.Context) ([]dto.PolicyResponse, error)
+	CreatePolicy(ctx context.Context, req *dto.PolicyRequest) (*dto.PolicyResponse, error)
+	UpdatePolicy(ctx context.Context, id int, req *dto.PolicyRequest) (*dto.PolicyResponse, error)
+	DeletePolicy(ctx context.Context, id int) error
+	// Reload, politikaları dosyalardan ve veritabanından yeniden yükler ve diğer instance'lara bildirir.
+	Reload(ctx context.Context) error
+	// Explain, bir kullanıcının yetki kararını gerekçesiyle birlikte döner; istekteki politikalar
+	// kaydedilmeden sadece bu değerlendirmeye eklenir (dry-run).
+	Explain(ctx context.Context, req *dto.ExplainPolicyRequest) (*policy.Decision, error)
+}
+
+type PolicyService struct {
+	uowFactory  IUnitOfWorkFactory
+	engine      *policy.Engine
+	permService IPermissionService
+	pubsub      cache.IPubSub
+	dir         string
+	instanceID  string
+}
+
+// NewPolicyService, politika servisini oluşturur ve yeniden yükleme kanalına abone olur. dir boşsa
+// dosya politikaları okunmaz; pubsub nil ise değişiklikler sadece bu instance'a uygulanır.
+// Politikalar ilk kez Reload ile yüklenir.
+func NewPolicyService(ctx context.Context, uowFactory IUnitOfWorkFactory, engine *policy.Engine, permService IPermissionService, pubsub cache.IPubSub, dir string) IPolicyService {
+	s := &PolicyService{
+		uowFactory:  uowFactory,
+		engine:      engine,
+		permService: permService,
+		pubsub:      pubsub,
+		dir:         dir,
+		instanceID:  uuid.New().String(),
+	}
+	if pubsub != nil {
+		pubsub.Subscribe(ctx, policyReloadChannel, func(origin string) {
+			if origin == s.instanceID {
+				return
+			}
+			if err := s.load(context.Background()); err != nil {
+				log.Printf("ERROR: Could not reload policies: %v", err)
+			}
+		})
+	}
+	return s
+}
+
+// ListPolicies, aktif organizasyonda geçerli dosya politikalarını ve organizasyonun (pasif olanlar
+// dahil) veritabanı politikalarını listeler.
+func (s *PolicyService) ListPolicies(ctx context.Context) ([]dto.PolicyResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	records, err := uow.PolicyRepository().FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationID, _ := tenant.OrganizationID(ctx)
+	responses := []dto.PolicyResponse{}
+	for _, p := range s.engine.Policies() {
+		if strings.HasPrefix(p.Source, policy.SourceFile) && appliesTo(&p, organizationID) {
+			responses = append(responses, dto.PolicyResponse{Source: p.Source, Enabled: true, Policy: p})
+		}
+	}
+	for i := range records {
+		resp, err := toPolicyResponse(&records[i])
+		if err != nil {
+			// Registry'den kaldırılmış bir yetkiye başvuran kayıt listelemeyi engellemez.
+			resp = &dto.PolicyResponse{ID: records[i].ID, Source: policy.SourceDatabase, Enabled: records[i].Enabled, Policy: policy.Policy{Name: records[i].Name}}
+		}
+		responses = append(responses, *resp)
+	}
+	return responses, nil
+}
+
+// CreatePolicy, veritabanına yeni bir politika ekler. Yönetici sadece sahip olduğu yetkiler için
+// politika yazabilir; aksi halde bir allow politikası yetki yükseltmek için kullanılabilir.
+func (s *PolicyService) CreatePolicy(ctx context.Context, req *dto.PolicyRequest) (*dto.PolicyResponse, error) {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, document, err := parsePolicyDocument(req.Document)
+	if err != nil {
+		return nil, err
+	}
+	if s.isFilePolicy(ctx, p.Name) {
+		return nil, apperrors.ErrPolicyExists
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if _, err := uow.PolicyRepository().FindByName(ctx, p.Name); err == nil {
+		return nil, apperrors.ErrPolicyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := ensurePolicyDelegable(ctx, uow, actor.UserID, p); err != nil {
+		return nil, err
+	}
+
+	record := &domain.Policy{
+		Name:      p.Name,
+		Document:  document,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		UpdatedBy: actor.UserID,
+	}
+	if err := uow.PolicyRepository().Create(ctx, record); err != nil {
+		return nil, err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action: domain.PermissionAuditPolicyCreated,
+		Detail: policyAuditDetail(p, record.Enabled),
+	}); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.reloadAfterChange(ctx)
+	return toPolicyResponse(record)
+}
+
+// UpdatePolicy, veritabanı politikasının dokümanını ve durumunu değiştirir.
+func (s *PolicyService) UpdatePolicy(ctx context.Context, id int, req *dto.PolicyRequest) (*dto.PolicyResponse, error) {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, document, err := parsePolicyDocument(req.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	record, err := findPolicy(ctx, uow, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Name != record.Name {
+		if s.isFilePolicy(ctx, p.Name) {
+			return nil, apperrors.ErrPolicyExists
+		}
+		if _, err := uow.PolicyRepository().FindByName(ctx, p.Name); err == nil {
+			return nil, apperrors.ErrPolicyExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	// Hem eski hem yeni politikanın işlemlerine sahip olunmalıdır; aksi halde sahip olunmayan bir
+	// yetki üzerindeki deny politikası güncellenerek kaldırılabilir.
+	if previous, _, err := parsePolicyDocument(json.RawMessage(record.Document)); err == nil {
+		if err := ensurePolicyDelegable(ctx, uow, actor.UserID, previous); err != nil {
+			return nil, err
+		}
+	}
+	if err := ensurePolicyDelegable(ctx, uow, actor.UserID, p); err != nil {
+		return nil, err
+	}
+
+	record.Name = p.Name
+	record.Document = document
+	if req.Enabled != nil {
+		record.Enabled = *req.Enabled
+	}
+	record.UpdatedBy = actor.UserID
+	if err := uow.PolicyRepository().Update(ctx, record); err != nil {
+		return nil, err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action: domain.PermissionAuditPolicyUpdated,
+		Detail: policyAuditDetail(p, record.Enabled),
+	}); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.reloadAfterChange(ctx)
+	return toPolicyResponse(record)
+}
+
+// DeletePolicy, veritabanı politikasını siler. Dosya politikaları sadece dosyadan kaldırılabilir.
+func (s *PolicyService) DeletePolicy(ctx context.Context, id int) error {
+	actor, err := currentPermissionActor(ctx)
+	if err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	record, err := findPolicy(ctx, uow, id)
+	if err != nil {
+		return err
+	}
+	p, _, err := parsePolicyDocument(json.RawMessage(record.Document))
+	if err == nil {
+		if err := ensurePolicyDelegable(ctx, uow, actor.UserID, p); err != nil {
+			return err
+		}
+	} else {
+		p = &policy.Policy{Name: record.Name}
+	}
+
+	if err := uow.PolicyRepository().Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := recordPermissionAudit(ctx, uow, actor, &domain.PermissionAuditLog{
+		Action: domain.PermissionAuditPolicyDeleted,
+		Detail: policyAuditDetail(p, record.Enabled),
+	}); err != nil {
+		return err
+	}
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.reloadAfterChange(ctx)
+	return nil
+}
+
+func (s *PolicyService) Reload(ctx context.Context) error {
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	s.publishReload(ctx)
+	return nil
+}
+
+func (s *PolicyService) Explain(ctx context.Context, req *dto.ExplainPolicyRequest) (*policy.Decision, error) {
+	perm, ok := lookupPermission(req.Permission)
+	if !ok {
+		return nil, apperrors.ErrInvalidPermission
+	}
+
+	extra := make([]policy.Policy, 0, len(req.Policies))
+	for _, raw := range req.Policies {
+		p, _, err := parsePolicyDocument(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.Source = policy.SourceDryRun
+		extra = append(extra, *p)
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+	if err := ensureUserExists(ctx, uow, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return s.permService.Authorize(ctx, req.UserID, perm, AuthorizeOptions{
+		Resource: req.Resource,
+		Env:      req.Env,
+		Extra:    extra,
+		Explain:  true,
+	})
+}
+
+// load, dosya ve veritabanı politikalarını okuyup motora yükler. Dosya politikalarındaki bir hata
+// yüklemeyi durdurur ve mevcut politikalar korunur. Geçersiz hale gelmiş veritabanı kayıtları (örn.
+// registry'den kaldırılmış bir yetkiye başvuran) atlanır ve loglanır. Motor tüm organizasyonların
+// politikalarını tutar; her politika sadece kendi organizasyonundaki isteklere uygulanır.
+func (s *PolicyService) load(ctx context.Context) error {
+	ctx = tenant.WithoutScope(ctx)
+
+	var policies []policy.Policy
+	if s.dir != "" {
+		files, err := policy.LoadDir(s.dir)
+		if err != nil {
+			return fmt.Errorf("could not load policy files: %w", err)
+		}
+		policies = append(policies, files...)
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	records, err := uow.PolicyRepository().FindEnabled(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load policies from database: %w", err)
+	}
+	for i := range records {
+		p, _, err := parsePolicyDocument(json.RawMessage(records[i].Document))
+		if err != nil {
+			log.Printf("ERROR: Skipping invalid policy %q: %v", records[i].Name, err)
+			continue
+		}
+		p.Source = policy.SourceDatabase
+		p.OrganizationID = records[i].OrganizationID
+		policies = append(policies, *p)
+	}
+
+	if err := s.engine.Load(policies); err != nil {
+		return err
+	}
+	log.Printf("✓ %d authorization policies loaded", len(policies))
+	return nil
+}
+
+// reloadAfterChange, commit edilmiş bir politika değişikliğini bu instance'a yükler ve diğer
+// instance'lara bildirir. Değişiklik kaydedildiği için yükleme hatası isteği başarısız kılmaz.
+func (s *PolicyService) reloadAfterChange(ctx context.Context) {
+	if err := s.load(ctx); err != nil {
+		log.Printf("ERROR: Could not reload policies: %v", err)
+	}
+	s.publishReload(ctx)
+}
+
+func (s *PolicyService) publishReload(ctx context.Context) {
+	if s.pubsub == nil {
+		return
+	}
+	if err := s.pubsub.Publish(ctx, policyReloadChannel, s.instanceID); err != nil {
+		log.Printf("ERROR: Could not publish policy reload: %v", err)
+	}
+}
+
+// isFilePolicy, aktif organizasyonda geçerli bir dosya politikasının bu adı kullanıp kullanmadığını söyler.
+func (s *PolicyService) isFilePolicy(ctx context.Context, name string) bool {
+	organizationID, _ := tenant.OrganizationID(ctx)
+	for _, p := range s.engine.Policies() {
+		if p.Name == name && strings.HasPrefix(p.Source, policy.SourceFile) && appliesTo(&p, organizationID) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo, politikanın organizasyonda geçerli olup olmadığını söyler.
+func appliesTo(p *policy.Policy, organizationID int) bool {
+	return p.OrganizationID == 0 || p.OrganizationID == organizationID
+}
+
+// parsePolicyDocument, tek bir politika dokümanını okur, doğrular ve saklanacak biçimini döner.
+func parsePolicyDocument(raw json.RawMessage) (*policy.Policy, string, error) {
+	if len(raw) == 0 {
+		return nil, "", apperrors.ErrInvalidPolicy
+	}
+	parsed, err := policy.Parse(raw)
+	if err != nil || len(parsed) != 1 {
+		return nil, "", apperrors.ErrInvalidPolicy
+	}
+	p := parsed[0]
+	p.Name = strings.TrimSpace(p.Name)
+	p.Source = ""
+	// Veritabanı politikaları her zaman kaydın organizasyonuna aittir; dokümandaki değer kullanılmaz.
+	p.OrganizationID = 0
+	if len(p.Name) > policyNameMaxLength {
+		return nil, "", apperrors.ErrInvalidPolicy
+	}
+	if err := policy.Validate(&p); err != nil {
+		return nil, "", apperrors.ErrInvalidPolicy
+	}
+
+	document, err := json.Marshal(p)
+	if err != nil {
+		return nil, "", err
+	}
+	return &p, string(document), nil
+}
+
+// ensurePolicyDelegable, yöneticinin politikanın tüm işlemlerine sahip olduğunu doğrular.
+func ensurePolicyDelegable(ctx context.Context, uow repository.IUnitOfWork, actorID int, p *policy.Policy) error {
+	held, err := uow.PermissionRepository().GetEffectivePermissions(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	return ensureDelegable(held, p.Actions)
+}
+
+func findPolicy(ctx context.Context, uow repository.IUnitOfWork, id int) (*domain.Policy, error) {
+	record, err := uow.PolicyRepository().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrPolicyNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// lookupPermission, "kaynak:işlem" biçimindeki tanımlı bir yetkiyi döner.
+func lookupPermission(name string) (permission.Permission, bool) {
+	resource, action, ok := strings.Cut(name, ":")
+	p := permission.Permission{Resource: resource, Action: action}
+	return p, ok && permission.IsDeclared(p)
+}
+
+func policyAuditDetail(p *policy.Policy, enabled bool) string {
+	detail := fmt.Sprintf("%s effect=%s actions=%s", p.Name, p.Effect, strings.Join(p.Actions, ","))
+	if !enabled {
+		detail += " (disabled)"
+	}
+	return detail
+}
+
+func toPolicyResponse(record *domain.Policy) (*dto.PolicyResponse, error) {
+	var p policy.Policy
+	if err := json.Unmarshal([]byte(record.Document), &p); err != nil {
+		return nil, err
+	}
+	p.Source = policy.SourceDatabase
+	p.OrganizationID = record.OrganizationID
+	updatedAt := record.UpdatedAt
+	return &dto.PolicyResponse{
+		ID:        record.ID,
+		Source:    policy.SourceDatabase,
+		Enabled:   record.Enabled,
+		Policy:    p,
+		UpdatedBy: record.UpdatedBy,
+		UpdatedAt: &updatedAt,
+	}, nil
+}
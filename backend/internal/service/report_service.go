@@ -6,8 +6,13 @@ import (
 	"log"
 	"time"
 
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/permission"
 	"ths-erp.com/internal/platform/queue"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
 )
 
 // GenerateReportJob, rapor oluşturma görevi için kuyruğa atılacak veriyi tanımlar.
@@ -18,23 +23,32 @@ type GenerateReportJob struct {
 type IReportService interface {
 	RequestReport(ctx context.Context, reportType string, payload map[string]interface{}) (*domain.Report, error)
 	GetReportStatus(ctx context.Context, id int) (*domain.Report, error)
+	ListReports(ctx context.Context) ([]domain.Report, error)
 	ProcessReport(ctx context.Context, reportID int) error // Bu Worker tarafından çağrılacak
 }
 
 type ReportService struct {
 	uowFactory  IUnitOfWorkFactory
 	queueClient *queue.RabbitMQClient
+	permService IPermissionService
 }
 
-func NewReportService(uowFactory IUnitOfWorkFactory, queueClient *queue.RabbitMQClient) IReportService {
+// NewReportService, yeni bir ReportService oluşturur. Sadece ProcessReport'u çağıran worker
+// süreci queueClient ve permService için nil verebilir.
+func NewReportService(uowFactory IUnitOfWorkFactory, queueClient *queue.RabbitMQClient, permService IPermissionService) IReportService {
 	return &ReportService{
 		uowFactory:  uowFactory,
 		queueClient: queueClient,
+		permService: permService,
 	}
 }
 
-// RequestReport API tarafından çağrılır.
+// RequestReport API tarafından çağrılır. Rapor, isteği yapan kullanıcıya ait olarak kaydedilir.
 func (s *ReportService) RequestReport(ctx context.Context, reportType string, payload map[string]interface{}) (*domain.Report, error) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
 	payloadBytes, _ := json.Marshal(payload)
 
 	uow := s.uowFactory.New(ctx)
@@ -42,9 +56,10 @@ func (s *ReportService) RequestReport(ctx context.Context, reportType string, pa
 
 	// 1. Veritabanına rapor kaydı oluştur (status: pending)
 	report := &domain.Report{
-		Type:    reportType,
-		Status:  domain.ReportStatusPending,
-		Payload: string(payloadBytes),
+		RequestedBy: user.UserID,
+		Type:        reportType,
+		Status:      domain.ReportStatusPending,
+		Payload:     string(payloadBytes),
 	}
 	createdReport, err := uow.ReportRepository().Create(ctx, report)
 	if err != nil {
@@ -69,25 +84,45 @@ func (s *ReportService) RequestReport(ctx context.Context, reportType string, pa
 	return createdReport, nil
 }
 
-// GetReportStatus API tarafından çağrılır.
+// GetReportStatus API tarafından çağrılır. Kullanıcı başkasına ait bir raporu, ancak
+// permission.ReportReadAll yetkisi varsa görebilir.
 func (s *ReportService) GetReportStatus(ctx context.Context, id int) (*domain.Report, error) {
+	scope, err := resolveDataScope(ctx, s.permService, permission.ReportReadAll)
+	if err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+	return uow.ReportRepository().GetByID(ctx, scope, id)
+}
+
+// ListReports, kullanıcının görebileceği raporları listeler.
+func (s *ReportService) ListReports(ctx context.Context) ([]domain.Report, error) {
+	scope, err := resolveDataScope(ctx, s.permService, permission.ReportReadAll)
+	if err != nil {
+		return nil, err
+	}
+
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
-	return uow.ReportRepository().GetByID(ctx, id)
+	return uow.ReportRepository().FindAll(ctx, scope)
 }
 
-// ProcessReport Worker tarafından çağrılır.
+// ProcessReport Worker tarafından çağrılır. Rapor, ait olduğu organizasyonun verileriyle oluşturulur.
 func (s *ReportService) ProcessReport(ctx context.Context, reportID int) error {
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
 	reportRepo := uow.ReportRepository()
 
-	// 1. Raporu DB'den al
-	report, err := reportRepo.GetByID(ctx, reportID)
+	// 1. Raporu DB'den al (worker sistem süreci olduğu için kapsam ve organizasyon kısıtlaması yok)
+	report, err := reportRepo.GetByID(tenant.WithoutScope(ctx), repository.Unrestricted(), reportID)
 	if err != nil {
 		return err
 	}
+	// Sonraki sorgular raporun organizasyonunda çalışır.
+	ctx = tenant.WithOrganization(ctx, report.OrganizationID)
 
 	// 2. Durumu 'processing' yap
 	report.Status = domain.ReportStatusProcessing
@@ -97,7 +132,7 @@ func (s *ReportService) ProcessReport(ctx context.Context, reportID int) error {
 
 	// 3. Ağır işi yap: Raporu oluştur
 	userRepo := uow.UserRepository()
-	allUsers, err := userRepo.FindAll(ctx) // Bu normalde filtrelenmiş bir sorgu olmalı
+	allUsers, err := userRepo.FindAll(ctx) // Organizasyonun üyeleri
 	if err != nil {
 		report.Status = domain.ReportStatusFailed
 		report.Error = err.Error()
@@ -0,0 +1,735 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/cache"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
+)
+
+// Redis'te tutulan token iptal listesi (denylist) anahtarları.
+const (
+	revokedTokenKeyPrefix   = "auth:revoked:jti:"
+	revokedSessionKeyPrefix = "auth:revoked:sid:"
+	validAfterKeyPrefix     = "auth:valid_after:"
+	sessionSeenKeyPrefix    = "auth:session_seen:"
+)
+
+// sessionTouchInterval, bir oturumun son görülme zamanının access token kullanımlarında
+// veritabanına en fazla hangi sıklıkla yazılacağını belirler.
+const sessionTouchInterval = time.Minute
+
+type ITokenService interface {
+	IssueTokens(ctx context.Context, user *domain.User) (*dto.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*dto.LoginResponse, error)
+	IssueChallenge(ctx context.Context, user *domain.User) (*dto.LoginResponse, error)
+	VerifyChallenge(ctx context.Context, challengeToken string) (*auth.JWTClaims, error)
+	ConsumeChallenge(ctx context.Context, claims *auth.JWTClaims) error
+	ValidateAccessToken(ctx context.Context, tokenString string) (*auth.AuthUser, error)
+	Logout(ctx context.Context, user *auth.AuthUser) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+	RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string) error
+	ListSessions(ctx context.Context, userID int, currentSessionID string) ([]dto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	SwitchOrganization(ctx context.Context, user *auth.AuthUser, organizationID int) (*dto.LoginResponse, error)
+}
+
+// TokenServiceConfig, token sürelerini belirler.
+type TokenServiceConfig struct {
+	RefreshTokenTTL  time.Duration
+	ImpersonationTTL time.Duration // Impersonation token'larının geçerlilik süresi (bkz. ImpersonationService)
+}
+
+type TokenService struct {
+	uowFactory IUnitOfWorkFactory
+	mapper     IMapper[*domain.User, *dto.UserResponse]
+	cache      cache.ICache
+	cfg        TokenServiceConfig
+}
+
+func NewTokenService(uowFactory IUnitOfWorkFactory, mapper IMapper[*domain.User, *dto.UserResponse], cache cache.ICache, cfg TokenServiceConfig) ITokenService {
+	return &TokenService{
+		uowFactory: uowFactory,
+		mapper:     mapper,
+		cache:      cache,
+		cfg:        cfg,
+	}
+}
+
+// IssueTokens, başarılı bir girişten sonra yeni bir token ailesi başlatır ve
+// access token ile refresh token çiftini döner.
+func (s *TokenService) IssueTokens(ctx context.Context, user *domain.User) (*dto.LoginResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	familyID := uuid.New().String()
+	refreshToken, err := s.createRefreshToken(ctx, uow.RefreshTokenRepository(), user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationID, err := resolveOrganization(ctx, uow, user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	client := auth.GetClientInfo(ctx)
+	now := time.Now()
+	if err := uow.UserSessionRepository().Create(ctx, &domain.UserSession{
+		SessionID:      familyID,
+		UserID:         user.ID,
+		OrganizationID: organizationID,
+		Device:         client.Device(),
+		UserAgent:      client.UserAgent,
+		IP:             client.IP,
+		LastSeenAt:     now,
+		ExpiresAt:      refreshToken.entity.ExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	if organizationID != 0 {
+		if err := uow.OrganizationRepository().TouchMember(ctx, organizationID, user.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(user, familyID, organizationID, refreshToken)
+}
+
+// Refresh, verilen refresh token'ı tek kullanımlık olarak tüketir ve aynı aile içinde
+// yeni bir token çifti üretir. Daha önce kullanılmış (rotasyona uğramış) bir token
+// tekrar gelirse token'ın çalındığı varsayılır ve tüm aile iptal edilir.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*dto.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, apperrors.ErrInvalidRefreshToken
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	tokenRepo := uow.RefreshTokenRepository()
+	current, err := tokenRepo.FindByHashForUpdate(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		log.Printf("WARNING: Refresh token reuse detected for user %d, revoking family %s", current.UserID, current.FamilyID)
+		if err := tokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		if err := uow.UserSessionRepository().Revoke(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		if err := s.revoke(revokedSessionKeyPrefix+current.FamilyID, auth.GetAccessTokenTTL()); err != nil {
+			return nil, err
+		}
+		if err := uow.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, apperrors.ErrRefreshTokenReused
+	}
+
+	if current.IsExpired() {
+		return nil, apperrors.ErrInvalidRefreshToken
+	}
+
+	user, err := uow.UserRepository().FindByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if user.TokensValidAfter != nil && current.CreatedAt.Before(*user.TokensValidAfter) {
+		return nil, apperrors.ErrInvalidRefreshToken
+	}
+
+	// Oturumun aktif organizasyonu korunur; kullanıcı bu arada organizasyondan çıkarıldıysa
+	// üyesi olduğu başka bir organizasyona geçilir.
+	sessionRepo := uow.UserSessionRepository()
+	session, err := sessionRepo.FindBySessionID(ctx, current.FamilyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	preferred := 0
+	if session != nil {
+		preferred = session.OrganizationID
+	}
+	organizationID, err := resolveOrganization(ctx, uow, user.ID, preferred)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil && organizationID != session.OrganizationID {
+		if err := sessionRepo.SetOrganization(ctx, current.FamilyID, organizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	newToken, err := s.createRefreshToken(ctx, tokenRepo, user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current.RevokedAt = &now
+	current.ReplacedByID = &newToken.entity.ID
+	if err := tokenRepo.Update(ctx, current); err != nil {
+		return nil, err
+	}
+	if err := sessionRepo.Touch(ctx, current.FamilyID, auth.GetClientInfo(ctx).IP, now, &newToken.entity.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(user, current.FamilyID, organizationID, newToken)
+}
+
+// IssueChallenge, şifresi doğrulanmış ve 2FA'sı açık kullanıcı için kısa ömürlü
+// bir challenge token üretir. Login, bu token ve bir 2FA kodu ile tamamlanır.
+func (s *TokenService) IssueChallenge(ctx context.Context, user *domain.User) (*dto.LoginResponse, error) {
+	challengeToken, err := auth.GenerateChallengeToken(user.ID, user.Email)
+	if err != nil {
+		log.Printf("Error generating 2FA challenge for user %d: %v", user.ID, err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return &dto.LoginResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpiresIn:         int(auth.ChallengeTokenTTL.Seconds()),
+	}, nil
+}
+
+// VerifyChallenge, challenge token'ın geçerli ve daha önce kullanılmamış olduğunu doğrular.
+func (s *TokenService) VerifyChallenge(ctx context.Context, challengeToken string) (*auth.JWTClaims, error) {
+	claims, err := auth.ParseChallengeToken(challengeToken)
+	if err != nil || claims.ID == "" {
+		return nil, apperrors.ErrInvalidChallenge
+	}
+
+	// Kullanılmış challenge listesine ulaşılamazsa token kabul edilmez (fail closed).
+	_, used, err := s.cache.Lookup(revokedTokenKeyPrefix + claims.ID)
+	if err != nil {
+		log.Printf("ERROR: Could not check challenge token reuse: %v", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	if used {
+		return nil, apperrors.ErrInvalidChallenge
+	}
+
+	return claims, nil
+}
+
+// ConsumeChallenge, başarıyla kullanılan challenge token'ını iptal listesine ekler;
+// böylece aynı token ile ikinci bir oturum açılamaz. Liste yazılamazsa giriş tamamlanmaz.
+func (s *TokenService) ConsumeChallenge(ctx context.Context, claims *auth.JWTClaims) error {
+	ttl := auth.ChallengeTokenTTL
+	if claims.ExpiresAt != nil {
+		ttl = time.Until(claims.ExpiresAt.Time)
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	return s.revoke(revokedTokenKeyPrefix+claims.ID, ttl)
+}
+
+// ValidateAccessToken, access token'ın imzasını ve süresini doğrular, ardından
+// Redis'teki iptal listesini kontrol eder: token'ın kendisi (jti), ait olduğu oturum (sid)
+// veya kullanıcının "tokens valid after" zamanından önce üretilmiş olması token'ı geçersiz kılar.
+// Impersonation token'ları ayrıca impersonation kaydı sonlandırılmışsa reddedilir.
+func (s *TokenService) ValidateAccessToken(ctx context.Context, tokenString string) (*auth.AuthUser, error) {
+	claims, err := auth.ParseJWT(tokenString)
+	if err != nil {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	// İptal listesine ulaşılamazsa token kabul edilmez (fail closed).
+	revokedKeys := make([]string, 0, 2)
+	if claims.ID != "" {
+		revokedKeys = append(revokedKeys, revokedTokenKeyPrefix+claims.ID)
+	}
+	if claims.SessionID != "" {
+		revokedKeys = append(revokedKeys, revokedSessionKeyPrefix+claims.SessionID)
+	}
+	for _, key := range revokedKeys {
+		_, revoked, err := s.cache.Lookup(key)
+		if err != nil {
+			log.Printf("ERROR: Could not check token revocation: %v", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		if revoked {
+			return nil, apperrors.ErrTokenRevoked
+		}
+	}
+
+	revoked, err := s.issuedBeforeValidAfter(ctx, claims.UserID, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, apperrors.ErrTokenRevoked
+	}
+	if claims.Actor != nil {
+		// Yöneticinin oturumları kapatıldıysa (örn. hesabı silindi) yürüttüğü impersonation da biter.
+		revoked, err := s.issuedBeforeValidAfter(ctx, claims.Actor.UserID, claims)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, apperrors.ErrTokenRevoked
+		}
+
+		ended, err := s.impersonationEnded(ctx, claims.ImpersonationID)
+		if err != nil {
+			return nil, err
+		}
+		if ended {
+			return nil, apperrors.ErrTokenRevoked
+		}
+	}
+
+	if claims.SessionID != "" {
+		s.touchSession(ctx, claims.SessionID)
+	}
+
+	user := &auth.AuthUser{
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+		TenantID:  claims.TenantID,
+	}
+	if claims.ExpiresAt != nil {
+		user.TokenExpiresAt = claims.ExpiresAt.Time
+	}
+	if claims.Actor != nil {
+		user.ImpersonatorID = claims.Actor.UserID
+		user.ImpersonatorEmail = claims.Actor.Email
+		user.ImpersonationID = claims.ImpersonationID
+	}
+	return user, nil
+}
+
+// issuedBeforeValidAfter, token'ın kullanıcının "tokens valid after" zamanından önce üretilip üretilmediğini söyler.
+// Kullanıcı artık yoksa token geçersiz sayılır.
+func (s *TokenService) issuedBeforeValidAfter(ctx context.Context, userID int, claims *auth.JWTClaims) (bool, error) {
+	validAfter, err := s.tokensValidAfter(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return claims.IssuedAt == nil || claims.IssuedAt.Unix() < validAfter, nil
+}
+
+// tokensValidAfter, kullanıcının "tokens valid after" zamanını Unix saniye olarak (yoksa 0) döner.
+// Önce Redis'e bakılır; anahtar yoksa veya Redis'e ulaşılamazsa users.tokens_valid_after okunur.
+// Okunan değer Redis'e sadece anahtar hâlâ yoksa yazılır; böylece bu arada RevokeAllForUser'ın
+// yazdığı daha yeni zaman ezilmez.
+func (s *TokenService) tokensValidAfter(ctx context.Context, userID int) (int64, error) {
+	val, found, err := s.cache.Lookup(validAfterKey(userID))
+	if err != nil {
+		log.Printf("ERROR: Could not read tokens valid after of user %d from cache: %v", userID, err)
+	}
+	if found {
+		if validAfter, err := strconv.ParseInt(cacheValueString(val), 10, 64); err == nil {
+			return validAfter, nil
+		}
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	user, err := uow.UserRepository().FindByID(tenant.WithoutScope(ctx), userID)
+	if err != nil {
+		return 0, err
+	}
+	var validAfter int64
+	if user.TokensValidAfter != nil {
+		validAfter = user.TokensValidAfter.Unix()
+	}
+	s.cache.Add(validAfterKey(userID), strconv.FormatInt(validAfter, 10), s.revocationTTL())
+	return validAfter, nil
+}
+
+// impersonationEnded, impersonation kaydının sonlandırılıp sonlandırılmadığını veritabanından okur.
+// End, token'ı Redis'teki iptal listesine de ekler; bu kontrol Redis'e ulaşılamadığında veya kayıt
+// düştüğünde sonlandırılmış bir impersonation'ın devam etmesini engeller. Impersonation token'ları
+// nadir ve kısa ömürlü olduğundan her istekte okunmaları kabul edilebilir.
+func (s *TokenService) impersonationEnded(ctx context.Context, impersonationID int) (bool, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback() // Read-only operation
+
+	session, err := uow.ImpersonationRepository().FindByID(ctx, impersonationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return session.EndedAt != nil, nil
+}
+
+// revocationTTL, kullanıcı bazlı iptal kayıtlarının tutulması gereken süredir: en uzun ömürlü
+// token (access veya impersonation) süresi dolana kadar kayıt silinmemelidir.
+func (s *TokenService) revocationTTL() time.Duration {
+	return max(auth.GetAccessTokenTTL(), s.cfg.ImpersonationTTL)
+}
+
+// Logout, mevcut access token'ı iptal listesine ekler ve token'ın ait olduğu
+// refresh token ailesini iptal eder; böylece o oturum bir daha yenilenemez. İptal listesine
+// yazılamazsa oturum kapatılmaz ve hata döner.
+func (s *TokenService) Logout(ctx context.Context, user *auth.AuthUser) error {
+	if user.SessionID != "" {
+		uow := s.uowFactory.New(ctx)
+		defer uow.Rollback()
+
+		if err := uow.RefreshTokenRepository().RevokeFamily(ctx, user.SessionID); err != nil {
+			return err
+		}
+		if err := uow.UserSessionRepository().Revoke(ctx, user.SessionID); err != nil {
+			return err
+		}
+		if err := s.revoke(revokedSessionKeyPrefix+user.SessionID, auth.GetAccessTokenTTL()); err != nil {
+			return err
+		}
+		if err := uow.Commit(); err != nil {
+			return err
+		}
+	}
+
+	if user.TokenID != "" {
+		ttl := time.Until(user.TokenExpiresAt)
+		if ttl <= 0 {
+			return nil
+		}
+		return s.revoke(revokedTokenKeyPrefix+user.TokenID, ttl)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser, kullanıcının tüm oturumlarını sonlandırır. Kullanıcının
+// "tokens valid after" zamanı şimdiye çekilir ve tüm refresh token'ları iptal edilir.
+// Çıkış (logout-all), kullanıcı silme ve şifre değişikliği sonrası çağrılır.
+func (s *TokenService) RevokeAllForUser(ctx context.Context, userID int) error {
+	// JWT'nin iat değeri saniye hassasiyetindedir; aynı saniye içinde üretilmiş
+	// token'ları da geçersiz kılmak için bir sonraki saniyeye yuvarlıyoruz.
+	validAfter := time.Now().Truncate(time.Second).Add(time.Second)
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := uow.UserRepository().SetTokensValidAfter(ctx, userID, validAfter); err != nil {
+		return err
+	}
+	if err := uow.RefreshTokenRepository().RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := uow.UserSessionRepository().RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	// Token'lar en fazla revocationTTL kadar yaşadığından, bu süreden sonra anahtarın Redis'te
+	// kalmasına gerek yoktur. Impersonation token'ları access token'lardan uzun yaşayabilir.
+	// Önbellekteki eski değer ezilemezse yeni zaman okunmayacağı için işlem başarısız sayılır.
+	if err := s.cache.Store(validAfterKey(userID), strconv.FormatInt(validAfter.Unix(), 10), s.revocationTTL()); err != nil {
+		log.Printf("ERROR: Could not store tokens valid after for user %d: %v", userID, err)
+		return apperrors.ErrInternalServer
+	}
+	return uow.Commit()
+}
+
+// RevokeOtherSessions, keepSessionID dışındaki tüm oturumları sonlandırır. Oturumların refresh
+// token'ları iptal edilir ve access token'ları sid üzerinden iptal listesine eklenir.
+// Şifre değişikliği sonrası mevcut oturumu kapatmadan diğer cihazları çıkarmak için kullanılır.
+func (s *TokenService) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string) error {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	tokenRepo := uow.RefreshTokenRepository()
+	sessionRepo := uow.UserSessionRepository()
+	families, err := tokenRepo.FindActiveFamilies(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, familyID := range families {
+		if familyID == keepSessionID {
+			continue
+		}
+		if err := tokenRepo.RevokeFamily(ctx, familyID); err != nil {
+			return err
+		}
+		if err := sessionRepo.Revoke(ctx, familyID); err != nil {
+			return err
+		}
+		if err := s.revoke(revokedSessionKeyPrefix+familyID, auth.GetAccessTokenTTL()); err != nil {
+			return err
+		}
+	}
+
+	return uow.Commit()
+}
+
+// ListSessions, kullanıcının açık oturumlarını döner. currentSessionID ile eşleşen oturum
+// "current" olarak işaretlenir. Kullanıcı kendisi değilse aktif organizasyonun üyesi olmalıdır;
+// aksi halde ErrNotFound döner.
+func (s *TokenService) ListSessions(ctx context.Context, userID int, currentSessionID string) ([]dto.SessionResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := uow.UserSessionRepository().FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, dto.SessionResponse{
+			SessionID:  session.SessionID,
+			Device:     session.Device,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.SessionID == currentSessionID,
+		})
+	}
+	return responses, nil
+}
+
+// RevokeSession, kullanıcının tek bir oturumunu sonlandırır. Oturumun refresh token'ları iptal
+// edilir ve access token'ları sid üzerinden iptal listesine eklenir. Oturum başka bir kullanıcıya
+// aitse veya zaten kapanmışsa ErrSessionNotFound, kullanıcı aktif organizasyonun üyesi değilse
+// ErrNotFound döner.
+func (s *TokenService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := ensureUserExists(ctx, uow, userID); err != nil {
+		return err
+	}
+
+	sessionRepo := uow.UserSessionRepository()
+	session, err := sessionRepo.FindBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID || !session.IsActive() {
+		return apperrors.ErrSessionNotFound
+	}
+
+	if err := uow.RefreshTokenRepository().RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+	if err := sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(userID),
+		Action:     domain.AuditActionSessionRevoked,
+		Changes:    auditChanges(nil, map[string]any{"sessionId": sessionID}),
+	}); err != nil {
+		return err
+	}
+	if err := s.revoke(revokedSessionKeyPrefix+sessionID, auth.GetAccessTokenTTL()); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// SwitchOrganization, oturumun aktif organizasyonunu değiştirir ve yeni organizasyonu taşıyan
+// bir access token döner. Seçim oturuma kaydedilir; refresh token ile yenilenen token'lar da
+// bu organizasyonu taşır. Eski access token iptal listesine eklenir. Kullanıcı organizasyonun
+// üyesi değilse ErrNotMember döner.
+func (s *TokenService) SwitchOrganization(ctx context.Context, user *auth.AuthUser, organizationID int) (*dto.LoginResponse, error) {
+	if user.IsAPIKey() {
+		return nil, apperrors.ErrAPIKeyNotAllowed
+	}
+	if user.IsImpersonated() {
+		return nil, apperrors.ErrImpersonationBlocked
+	}
+	if user.SessionID == "" {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	orgRepo := uow.OrganizationRepository()
+	member, err := orgRepo.IsMember(ctx, organizationID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, apperrors.ErrNotMember
+	}
+	if err := uow.UserSessionRepository().SetOrganization(ctx, user.SessionID, organizationID); err != nil {
+		return nil, err
+	}
+	if err := orgRepo.TouchMember(ctx, organizationID, user.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := auth.GenerateJWT(user.UserID, user.Email, user.SessionID, organizationID)
+	if err != nil {
+		log.Printf("Error generating JWT for user %d: %v", user.UserID, err)
+		return nil, apperrors.ErrInternalServer
+	}
+	if ttl := time.Until(user.TokenExpiresAt); ttl > 0 && user.TokenID != "" {
+		if err := s.revoke(revokedTokenKeyPrefix+user.TokenID, ttl); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.LoginResponse{
+		Token:          accessToken,
+		ExpiresIn:      int(auth.GetAccessTokenTTL().Seconds()),
+		OrganizationID: organizationID,
+	}, nil
+}
+
+// resolveOrganization, oturumun aktif organizasyonunu belirler: tercih edilen organizasyonun
+// kullanıcı hâlâ üyesiyse o, değilse kullanıcının en son kullandığı organizasyon seçilir.
+// Kullanıcı hiçbir organizasyonun üyesi değilse 0 döner; bu durumda token "tid" taşımaz ve
+// kiracıya ait verilere erişilemez.
+func resolveOrganization(ctx context.Context, uow repository.IUnitOfWork, userID, preferred int) (int, error) {
+	orgRepo := uow.OrganizationRepository()
+	if preferred != 0 {
+		member, err := orgRepo.IsMember(ctx, preferred, userID)
+		if err != nil || member {
+			return preferred, err
+		}
+	}
+	orgs, err := orgRepo.FindByUser(ctx, userID)
+	if err != nil || len(orgs) == 0 {
+		return 0, err
+	}
+	return orgs[0].ID, nil
+}
+
+// touchSession, oturumun son görülme zamanını günceller. Her istekte veritabanına yazmamak
+// için güncelleme oturum başına sessionTouchInterval'da bir yapılır; hata isteği engellemez.
+func (s *TokenService) touchSession(ctx context.Context, sessionID string) {
+	if _, recent := s.cache.Get(sessionSeenKeyPrefix + sessionID); recent {
+		return
+	}
+	s.cache.Set(sessionSeenKeyPrefix+sessionID, "1", sessionTouchInterval)
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+	if err := uow.UserSessionRepository().Touch(ctx, sessionID, auth.GetClientInfo(ctx).IP, time.Now(), nil); err != nil {
+		log.Printf("ERROR: Could not update last seen time of session %s: %v", sessionID, err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not update last seen time of session %s: %v", sessionID, err)
+	}
+}
+
+// revoke, bir token veya oturum iptalini önbelleğe yazar. Access token'lar sadece bu liste ile
+// iptal edildiğinden yazılamayan bir iptal sessizce geçilmez; çağıran işlem başarısız olur.
+func (s *TokenService) revoke(key string, ttl time.Duration) error {
+	if err := s.cache.Store(key, "1", ttl); err != nil {
+		log.Printf("ERROR: Could not store token revocation: %v", err)
+		return apperrors.ErrInternalServer
+	}
+	return nil
+}
+
+func validAfterKey(userID int) string {
+	return fmt.Sprintf("%s%d", validAfterKeyPrefix, userID)
+}
+
+// cacheValueString, ICache implementasyonundan bağımsız olarak değeri string'e çevirir.
+// RedisCache []byte, InMemoryCache ise kaydedilen değerin kendisini döner.
+func cacheValueString(val interface{}) string {
+	switch v := val.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// issuedRefreshToken, veritabanına yazılan kaydı ve istemciye bir kez gösterilecek
+// düz metin token'ı birlikte taşır.
+type issuedRefreshToken struct {
+	entity    *domain.RefreshToken
+	plaintext string
+}
+
+func (s *TokenService) createRefreshToken(ctx context.Context, tokenRepo repository.IRefreshTokenRepository, userID int, familyID string) (*issuedRefreshToken, error) {
+	plaintext, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
+
+	entity, err := tokenRepo.Create(ctx, &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(plaintext),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuedRefreshToken{entity: entity, plaintext: plaintext}, nil
+}
+
+func (s *TokenService) buildResponse(user *domain.User, familyID string, organizationID int, refreshToken *issuedRefreshToken) (*dto.LoginResponse, error) {
+	accessToken, err := auth.GenerateJWT(user.ID, user.Email, familyID, organizationID)
+	if err != nil {
+		log.Printf("Error generating JWT for user %d: %v", user.ID, err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return &dto.LoginResponse{
+		Token:          accessToken,
+		RefreshToken:   refreshToken.plaintext,
+		ExpiresIn:      int(auth.GetAccessTokenTTL().Seconds()),
+		User:           s.mapper.ToResponse(user),
+		OrganizationID: organizationID,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/platform/ratelimit"
+	"ths-erp.com/internal/repository"
+)
+
+// emailVerificationTokenTTL, e-posta doğrulama bağlantısının geçerlilik süresidir.
+const emailVerificationTokenTTL = 24 * time.Hour
+
+// EmailVerificationJob, worker'ın doğrulama e-postasını göndermesi için kuyruğa atılan görevdir.
+// Email, doğrulanacak adrestir; e-posta değişikliğinde kullanıcının yeni adresidir.
+type EmailVerificationJob struct {
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EmailRequestLimiters, kimlik doğrulaması gerektirmeyen ve e-posta gönderen istekleri (doğrulama
+// e-postasını yeniden gönderme, şifre sıfırlama) sınırlar. Alanlar nil ise ilgili sınırlama uygulanmaz.
+type EmailRequestLimiters struct {
+	Email ratelimit.ISlidingWindowLimiter // Adres başına istek penceresi
+	IP    ratelimit.ISlidingWindowLimiter // İstemci IP'si başına istek penceresi
+}
+
+// createEmailVerification, verilen adres için yeni bir doğrulama token'ı oluşturur ve
+// commit sonrası kuyruğa atılacak görevi döner. Aynı adres için üretilmiş önceki token'lar
+// geçersiz kılınır; diğer adresin (örn. onay bekleyen e-posta değişikliği) token'ları etkilenmez.
+func createEmailVerification(ctx context.Context, tokenRepo repository.IEmailVerificationTokenRepository, user *domain.User, email string) (*EmailVerificationJob, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
+
+	if err := tokenRepo.InvalidateForEmail(ctx, user.ID, email); err != nil {
+		return nil, err
+	}
+	verificationToken, err := tokenRepo.Create(ctx, &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(emailVerificationTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailVerificationJob{
+		UserID:    user.ID,
+		Email:     email,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: verificationToken.ExpiresAt,
+	}, nil
+}
+
+// publishEmailVerification, doğrulama e-postası görevini kuyruğa atar.
+// Hata durumunda kullanıcı doğrulama e-postasını yeniden isteyebileceği için sadece loglanır.
+func (s *UserService) publishEmailVerification(ctx context.Context, job *EmailVerificationJob) {
+	jobPayload, err := json.Marshal(job)
+	if err != nil {
+		log.Printf("ERROR: Could not marshal email verification job for user %d: %v", job.UserID, err)
+		return
+	}
+	if err := s.queueClient.Publish(ctx, "app_exchange", "user.email_verification", jobPayload); err != nil {
+		log.Printf("ERROR: Could not publish email verification job for user %d: %v", job.UserID, err)
+		return
+	}
+	log.Printf("✓ Email verification job published for user %d", job.UserID)
+}
+
+// VerifyEmail, doğrulama token'ını onaylar. Token kullanıcının onay bekleyen yeni adresine
+// aitse e-posta adresi bu adresle değiştirilir; o ana kadar eski adres kullanılmaya devam eder.
+func (s *UserService) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return apperrors.ErrValidation
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	tokenRepo := uow.EmailVerificationTokenRepository()
+	verificationToken, err := tokenRepo.FindByHashForUpdate(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvalidVerifyToken
+		}
+		return err
+	}
+	if verificationToken.UsedAt != nil || verificationToken.IsExpired() {
+		return apperrors.ErrInvalidVerifyToken
+	}
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, verificationToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvalidVerifyToken
+		}
+		return err
+	}
+
+	var fields map[string]interface{}
+	switch {
+	case user.PendingEmail != "" && verificationToken.Email == user.PendingEmail:
+		// Onay beklerken adres başka bir hesap tarafından alınmış olabilir.
+		existing, err := userRepo.FindByEmail(ctx, user.PendingEmail)
+		if err == nil && existing.ID != user.ID {
+			return apperrors.ErrEmailExists
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		fields = map[string]interface{}{
+			"email":             user.PendingEmail,
+			"pending_email":     "",
+			"email_verified_at": time.Now(),
+		}
+	case verificationToken.Email == user.Email:
+		fields = map[string]interface{}{"email_verified_at": time.Now()}
+	default:
+		// Token, artık kullanıcıya ait olmayan veya değiştirilmiş bir adres için üretilmiş.
+		return apperrors.ErrInvalidVerifyToken
+	}
+
+	before := map[string]any{"email": user.Email, "emailVerifiedAt": user.EmailVerifiedAt}
+	if err := userRepo.UpdateFields(ctx, user.ID, fields); err != nil {
+		return err
+	}
+	// Diğer adresin token'ı geçerli kalır; örn. mevcut adres doğrulandığında onay bekleyen
+	// e-posta değişikliği hâlâ onaylanabilir.
+	if err := tokenRepo.InvalidateForEmail(ctx, user.ID, verificationToken.Email); err != nil {
+		return err
+	}
+	after := map[string]any{"email": user.Email, "emailVerifiedAt": fields["email_verified_at"]}
+	if email, ok := fields["email"]; ok {
+		after["email"] = email
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionEmailVerified,
+		Changes:    auditChanges(before, after),
+	}); err != nil {
+		return err
+	}
+
+	return uow.Commit()
+}
+
+// ResendVerificationEmail, henüz doğrulanmamış bir adrese yeni bir doğrulama bağlantısı gönderir.
+// Onay bekleyen bir e-posta değişikliği varsa bağlantı yeni adrese gönderilir. İstekler adres ve
+// IP bazında sınırlandırılır; hesapların tespit edilememesi için sınır bilinmeyen adreslere de
+// uygulanır ve bilinmeyen veya zaten doğrulanmış adreslerde hata dönmez.
+func (s *UserService) ResendVerificationEmail(ctx context.Context, email string) error {
+	emailKey := strings.ToLower(strings.TrimSpace(email))
+	if err := throttleEmailRequest(ctx, s.verificationLimiters, emailKey, auth.GetClientInfo(ctx).IP); err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	target := user.PendingEmail
+	if target == "" {
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+		target = user.Email
+	}
+
+	job, err := createEmailVerification(ctx, uow.EmailVerificationTokenRepository(), user, target)
+	if err != nil {
+		return err
+	}
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.publishEmailVerification(ctx, job)
+	return nil
+}
+
+// throttleEmailRequest, adres ve IP pencerelerinden biri doluysa ErrTooManyAttempts döner;
+// aksi halde isteği iki pencereye de ekler.
+func throttleEmailRequest(ctx context.Context, limiters EmailRequestLimiters, emailKey, ip string) error {
+	checks := []struct {
+		limiter ratelimit.ISlidingWindowLimiter
+		key     string
+	}{
+		{limiters.Email, emailKey},
+		{limiters.IP, ip},
+	}
+	for _, check := range checks {
+		if check.limiter == nil || check.key == "" {
+			continue
+		}
+		retryAfter, err := check.limiter.Exceeded(ctx, check.key)
+		if err != nil {
+			log.Printf("ERROR: Could not check email request rate limit: %v", err)
+			return apperrors.ErrInternalServer
+		}
+		if retryAfter > 0 {
+			return apperrors.ErrTooManyAttempts
+		}
+	}
+	for _, check := range checks {
+		if check.limiter == nil || check.key == "" {
+			continue
+		}
+		if err := check.limiter.Hit(ctx, check.key); err != nil {
+			log.Printf("ERROR: Could not register email request: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/platform/password"
+	"ths-erp.com/internal/platform/ratelimit"
+)
+
+// lockStatusAttemptCount, kilit durumu ile birlikte gösterilen son login denemesi sayısıdır.
+const lockStatusAttemptCount = 20
+
+// LoginLimiters, Authenticate'in kullandığı sınırlayıcıları bir arada tutar.
+// Nil olan sınırlayıcılar uygulanmaz (örn. worker süreci).
+type LoginLimiters struct {
+	Email   ratelimit.ISlidingWindowLimiter // E-posta adresi başına hatalı deneme penceresi
+	IP      ratelimit.ISlidingWindowLimiter // İstemci IP'si başına hatalı deneme penceresi
+	Account ratelimit.IFailureLimiter       // Eşik aşılınca hesabı geçici olarak kilitler
+}
+
+// Authenticate, e-posta ve şifreyi doğrular. Hatalı denemeler e-posta ve IP bazında sınırlandırılır,
+// art arda hatalı denemelerde hesap geçici olarak kilitlenir ve her deneme login_attempts tablosuna yazılır.
+func (s *UserService) Authenticate(ctx context.Context, email, plainPassword string) (*domain.User, error) {
+	client := auth.GetClientInfo(ctx)
+	emailKey := strings.ToLower(strings.TrimSpace(email))
+
+	if err := s.checkLoginThrottle(ctx, emailKey, client.IP); err != nil {
+		if errors.Is(err, apperrors.ErrTooManyAttempts) {
+			s.recordLoginAttempt(ctx, nil, emailKey, domain.LoginResultThrottled)
+		}
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			password.VerifyDummy(plainPassword)
+			s.registerLoginFailure(ctx, nil, emailKey, client.IP)
+			s.recordLoginAttempt(ctx, nil, emailKey, domain.LoginResultInvalidCredentials)
+			return nil, apperrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// Servis hesapları şifre ile giriş yapamaz; sadece API anahtarları ile kullanılır.
+	if user.ServiceAccount {
+		password.VerifyDummy(plainPassword)
+		s.registerLoginFailure(ctx, user, emailKey, client.IP)
+		s.recordLoginAttempt(ctx, &user.ID, emailKey, domain.LoginResultInvalidCredentials)
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	ok, needsRehash, err := password.Verify(plainPassword, user.PasswordHash)
+	if err != nil {
+		log.Printf("ERROR: Could not verify password hash of user %d: %v", user.ID, err)
+	}
+
+	// Kilit süresince şifre doğru da olsa yanlış da olsa yanıt aynıdır: aksi halde kilitli hesaba
+	// yapılan denemeler şifrenin doğru olup olmadığını gösterirdi. Her deneme hesabın sayacına
+	// işlenir ve kilidi uzatabilir; hesap şifre sıfırlama veya yönetici tarafından açılabilir.
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		s.registerLoginFailure(ctx, user, emailKey, client.IP)
+		s.recordLoginAttempt(ctx, &user.ID, emailKey, domain.LoginResultLocked)
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	if !ok {
+		s.registerLoginFailure(ctx, user, emailKey, client.IP)
+		s.recordLoginAttempt(ctx, &user.ID, emailKey, domain.LoginResultInvalidCredentials)
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	// Şifre doğrulandıktan sonra kontrol edilir; böylece hesabın varlığı sızdırılmaz.
+	if s.cfg.RequireEmailVerification && user.EmailVerifiedAt == nil {
+		s.recordLoginAttempt(ctx, &user.ID, emailKey, domain.LoginResultUnverified)
+		return nil, apperrors.ErrEmailNotVerified
+	}
+
+	if needsRehash {
+		s.rehashPassword(ctx, user, plainPassword)
+	}
+
+	s.resetLoginFailures(ctx, user.ID, emailKey)
+	s.recordLoginAttempt(ctx, &user.ID, emailKey, domain.LoginResultSuccess)
+	return user, nil
+}
+
+// rehashPassword, eski bir algoritma veya maliyetle özetlenmiş şifreyi güncel hasher ile
+// yeniden özetler. Kullanıcılar giriş yaptıkça kademeli olarak yeni algoritmaya geçer;
+// hata olması girişi engellemez.
+func (s *UserService) rehashPassword(ctx context.Context, user *domain.User, plainPassword string) {
+	newHash, err := password.Hash(plainPassword)
+	if err != nil {
+		log.Printf("ERROR: Could not rehash password of user %d: %v", user.ID, err)
+		return
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+	replaced, err := uow.UserRepository().ReplacePasswordHash(ctx, user.ID, user.PasswordHash, newHash)
+	if err != nil {
+		log.Printf("ERROR: Could not save rehashed password of user %d: %v", user.ID, err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not save rehashed password of user %d: %v", user.ID, err)
+		return
+	}
+	if replaced {
+		user.PasswordHash = newHash
+		log.Printf("✓ Password of user %d rehashed with current algorithm", user.ID)
+	}
+}
+
+// checkLoginThrottle, e-posta veya IP için hatalı deneme limiti aşıldıysa ErrTooManyAttempts döner.
+func (s *UserService) checkLoginThrottle(ctx context.Context, emailKey, ip string) error {
+	checks := []struct {
+		limiter ratelimit.ISlidingWindowLimiter
+		key     string
+	}{
+		{s.loginLimiters.Email, emailKey},
+		{s.loginLimiters.IP, ip},
+	}
+	for _, check := range checks {
+		if check.limiter == nil || check.key == "" {
+			continue
+		}
+		retryAfter, err := check.limiter.Exceeded(ctx, check.key)
+		if err != nil {
+			log.Printf("ERROR: Could not check login rate limit: %v", err)
+			return apperrors.ErrInternalServer
+		}
+		if retryAfter > 0 {
+			return apperrors.ErrTooManyAttempts
+		}
+	}
+	return nil
+}
+
+// registerLoginFailure, hatalı denemeyi e-posta ve IP pencerelerine ekler. Kullanıcı biliniyorsa
+// hesap bazlı sayaç da artırılır ve eşik aşıldıysa hesap veritabanında kilitlenir.
+func (s *UserService) registerLoginFailure(ctx context.Context, user *domain.User, emailKey, ip string) {
+	if s.loginLimiters.Email != nil && emailKey != "" {
+		if err := s.loginLimiters.Email.Hit(ctx, emailKey); err != nil {
+			log.Printf("ERROR: Could not register login failure for email: %v", err)
+		}
+	}
+	if s.loginLimiters.IP != nil && ip != "" {
+		if err := s.loginLimiters.IP.Hit(ctx, ip); err != nil {
+			log.Printf("ERROR: Could not register login failure for ip %s: %v", ip, err)
+		}
+	}
+
+	if user == nil || s.loginLimiters.Account == nil {
+		return
+	}
+	lockout, err := s.loginLimiters.Account.RegisterFailure(ctx, strconv.Itoa(user.ID))
+	if err != nil {
+		log.Printf("ERROR: Could not register login failure for user %d: %v", user.ID, err)
+		return
+	}
+	if lockout <= 0 {
+		return
+	}
+
+	// Kilit, yöneticilerin görebilmesi ve kaldırabilmesi için veritabanında da tutulur.
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+	if err := uow.UserRepository().UpdateFields(ctx, user.ID, map[string]interface{}{
+		"locked_until": time.Now().Add(lockout),
+	}); err != nil {
+		log.Printf("ERROR: Could not lock account of user %d: %v", user.ID, err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not lock account of user %d: %v", user.ID, err)
+		return
+	}
+	log.Printf("WARNING: Account of user %d locked for %s after repeated login failures", user.ID, lockout)
+}
+
+// resetLoginFailures, başarılı girişten sonra hesap ve e-posta sayaçlarını sıfırlar.
+// IP penceresi sıfırlanmaz; aynı IP'den başka hesaplara yapılan denemeler sayılmaya devam eder.
+func (s *UserService) resetLoginFailures(ctx context.Context, userID int, emailKey string) {
+	if s.loginLimiters.Account != nil {
+		if err := s.loginLimiters.Account.Reset(ctx, strconv.Itoa(userID)); err != nil {
+			log.Printf("ERROR: Could not reset login failures for user %d: %v", userID, err)
+		}
+	}
+	if s.loginLimiters.Email != nil {
+		if err := s.loginLimiters.Email.Reset(ctx, emailKey); err != nil {
+			log.Printf("ERROR: Could not reset login failures for email: %v", err)
+		}
+	}
+}
+
+// recordLoginAttempt, denemeyi Prometheus'a ve login_attempts tablosuna yazar.
+// Login işleminin sonucunu etkilememesi için ayrı bir transaction kullanılır ve hatalar sadece loglanır.
+func (s *UserService) recordLoginAttempt(ctx context.Context, userID *int, email, result string) {
+	metrics.M.LoginAttemptsTotal.WithLabelValues(result).Inc()
+
+	client := auth.GetClientInfo(ctx)
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	if err := uow.LoginAttemptRepository().Create(ctx, &domain.LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Success:   result == domain.LoginResultSuccess,
+		Result:    result,
+	}); err != nil {
+		log.Printf("ERROR: Could not record login attempt: %v", err)
+		return
+	}
+	if err := uow.Commit(); err != nil {
+		log.Printf("ERROR: Could not record login attempt: %v", err)
+	}
+}
+
+// GetLockStatus, hesabın kilit durumunu ve son login denemelerini döner.
+func (s *UserService) GetLockStatus(ctx context.Context, userID int) (*dto.AccountLockStatusResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	attempts, err := uow.LoginAttemptRepository().FindRecentByUser(ctx, userID, lockStatusAttemptCount)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.AccountLockStatusResponse{
+		RecentAttempts: make([]dto.LoginAttemptResponse, 0, len(attempts)),
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		status.Locked = true
+		status.LockedUntil = user.LockedUntil
+	}
+	for _, attempt := range attempts {
+		status.RecentAttempts = append(status.RecentAttempts, dto.LoginAttemptResponse{
+			IP:        attempt.IP,
+			UserAgent: attempt.UserAgent,
+			Success:   attempt.Success,
+			Result:    attempt.Result,
+			CreatedAt: attempt.CreatedAt,
+		})
+	}
+	return status, nil
+}
+
+// UnlockUser, hesabın kilidini kaldırır ve hatalı deneme sayaçlarını sıfırlar.
+func (s *UserService) UnlockUser(ctx context.Context, userID int) error {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{"locked_until": nil}); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionUnlocked,
+		Changes:    auditChanges(map[string]any{"lockedUntil": user.LockedUntil}, map[string]any{"lockedUntil": nil}),
+	}); err != nil {
+		return err
+	}
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.resetLoginFailures(ctx, user.ID, strings.ToLower(user.Email))
+	return nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
+	"ths-erp.com/internal/domain"
+	"ths-erp.com/internal/dto"
+	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/platform/password"
+)
+
+// passwordResetTokenTTL, e-posta ile gönderilen şifre sıfırlama bağlantısının geçerlilik süresidir.
+const passwordResetTokenTTL = time.Hour
+
+// validatePassword, yeni belirlenen şifrelerin şifre politikasına uyduğunu kontrol eder.
+// İhlaller *password.PolicyError olarak döner ve handler'da alan hatası olarak yerelleştirilir.
+func validatePassword(newPassword string) error {
+	err := password.Validate(newPassword)
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		for _, v := range policyErr.Violations {
+			metrics.M.ValidationErrorsTotal.WithLabelValues("password", v.Rule).Inc()
+		}
+	}
+	return err
+}
+
+// verifyPassword, şifreyi kullanıcının kayıtlı özetiyle karşılaştırır. Yeniden özetleme
+// sadece Authenticate'te yapılır; burada özetin eski olması önemsizdir.
+func verifyPassword(user *domain.User, plainPassword string) bool {
+	ok, _, err := password.Verify(plainPassword, user.PasswordHash)
+	if err != nil {
+		log.Printf("ERROR: Could not verify password hash of user %d: %v", user.ID, err)
+	}
+	return ok
+}
+
+// PasswordResetEmailJob, worker'ın şifre sıfırlama e-postasını göndermesi için kuyruğa atılan görevdir.
+type PasswordResetEmailJob struct {
+	UserID    int       `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ForgotPassword, e-posta adresine ait bir kullanıcı varsa tek kullanımlık bir sıfırlama
+// token'ı üretir ve e-posta görevini kuyruğa atar. İstekler adres ve IP bazında sınırlandırılır;
+// aksi halde tekrarlanan istekler gelen kutusunu doldurup her seferinde önceki bağlantıyı
+// geçersiz kılardı. Hesapların tespit edilememesi için sınır bilinmeyen adreslere de uygulanır
+// ve bilinmeyen e-posta adreslerinde hata dönmez.
+func (s *UserService) ForgotPassword(ctx context.Context, email string) error {
+	emailKey := strings.ToLower(strings.TrimSpace(email))
+	if err := throttleEmailRequest(ctx, s.passwordResetLimiters, emailKey, auth.GetClientInfo(ctx).IP); err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.ServiceAccount {
+		return nil
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return apperrors.ErrInternalServer
+	}
+
+	tokenRepo := uow.PasswordResetTokenRepository()
+	// Aynı anda sadece en son gönderilen bağlantı geçerlidir.
+	if err := tokenRepo.InvalidateAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	resetToken, err := tokenRepo.Create(ctx, &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	job := PasswordResetEmailJob{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Token:     token,
+		ExpiresAt: resetToken.ExpiresAt,
+	}
+	jobPayload, err := json.Marshal(job)
+	if err != nil {
+		log.Printf("ERROR: Could not marshal password reset email job for user %d: %v", user.ID, err)
+		return nil
+	}
+	if err := s.queueClient.Publish(ctx, "app_exchange", "user.password_reset_email", jobPayload); err != nil {
+		log.Printf("ERROR: Could not publish password reset email job for user %d: %v", user.ID, err)
+		return nil
+	}
+	log.Printf("✓ Password reset email job published for user %d", user.ID)
+
+	return nil
+}
+
+// ResetPassword, geçerli bir sıfırlama token'ı ile kullanıcının şifresini değiştirir.
+// Token tek kullanımlıktır; başarılı bir sıfırlamadan sonra hesap kilidi ve hatalı giriş sayaçları
+// sıfırlanır, kullanıcının tüm oturumları sonlandırılır.
+func (s *UserService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" || newPassword == "" {
+		return apperrors.ErrValidation
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := password.Hash(newPassword)
+	if err != nil {
+		log.Printf("Error while hashing password: %v", err)
+		return apperrors.ErrInternalServer
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	tokenRepo := uow.PasswordResetTokenRepository()
+	resetToken, err := tokenRepo.FindByHashForUpdate(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvalidResetToken
+		}
+		return err
+	}
+	if resetToken.UsedAt != nil || resetToken.IsExpired() {
+		return apperrors.ErrInvalidResetToken
+	}
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvalidResetToken
+		}
+		return err
+	}
+	// E-postaya erişimi kanıtlanan kullanıcının hesap kilidi de kaldırılır.
+	if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"password_hash": hashedPassword,
+		"locked_until":  nil,
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Kullanılan token ile birlikte kullanıcının diğer bekleyen token'ları da geçersiz kılınır.
+	if err := tokenRepo.InvalidateAllForUser(ctx, resetToken.UserID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(resetToken.UserID),
+		Action:     domain.AuditActionPasswordReset,
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.resetLoginFailures(ctx, user.ID, strings.ToLower(user.Email))
+	// Şifresi ele geçirilmiş olabilecek hesabın açık oturumları derhal sonlandırılır.
+	if err := s.tokenService.RevokeAllForUser(ctx, resetToken.UserID); err != nil {
+		log.Printf("ERROR: Could not revoke sessions after password reset for user %d: %v", resetToken.UserID, err)
+	}
+
+	return nil
+}
+
+// ChangePassword, oturum açmış kullanıcının şifresini mevcut şifresini doğrulayarak değiştirir.
+// Şifre değiştikten sonra isteği yapan oturum (sessionID) dışındaki tüm oturumlar sonlandırılır.
+func (s *UserService) ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.ChangePasswordRequest) error {
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return apperrors.ErrValidation
+	}
+	if err := validatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+
+	if !verifyPassword(user, req.CurrentPassword) {
+		return apperrors.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := password.Hash(req.NewPassword)
+	if err != nil {
+		log.Printf("Error while hashing password: %v", err)
+		return apperrors.ErrInternalServer
+	}
+
+	if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"password_hash": hashedPassword,
+	}); err != nil {
+		return err
+	}
+	// Eski şifre için istenmiş sıfırlama bağlantıları artık kullanılamaz.
+	if err := uow.PasswordResetTokenRepository().InvalidateAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionPasswordChanged,
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	if err := s.tokenService.RevokeOtherSessions(ctx, user.ID, sessionID); err != nil {
+		log.Printf("ERROR: Could not revoke other sessions after password change for user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
@@ -4,22 +4,31 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"image/png"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/lib/pq"
+	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/auth"
 	"ths-erp.com/internal/domain"
 	"ths-erp.com/internal/dto"
 	"ths-erp.com/internal/platform/metrics"
+	"ths-erp.com/internal/platform/password"
 	"ths-erp.com/internal/platform/queue"
+	"ths-erp.com/internal/platform/ratelimit"
+	"ths-erp.com/internal/repository"
+	"ths-erp.com/internal/tenant"
 )
 
 type WelcomeEmailJob struct {
@@ -37,43 +46,56 @@ type IUserService interface {
 	DeleteUser(ctx context.Context, id int) error
 	Setup2FA(ctx context.Context, userID int) (*dto.Setup2FAResponse, error)
 	Enable2FA(ctx context.Context, userID int, code string) ([]string, error)
-	Disable2FA(ctx context.Context, userID int) error
+	Disable2FA(ctx context.Context, userID int, req *dto.Disable2FARequest) error
 	Verify2FA(ctx context.Context, userID int, code string) (bool, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error)
+	Get2FAStatus(ctx context.Context, userID int) (*dto.TwoFactorStatusResponse, error)
+	Login2FA(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	ChangePassword(ctx context.Context, userID int, sessionID string, req *dto.ChangePasswordRequest) error
+	GetLockStatus(ctx context.Context, userID int) (*dto.AccountLockStatusResponse, error)
+	UnlockUser(ctx context.Context, userID int) error
 }
 
-type UserService struct {
-	uowFactory  IUnitOfWorkFactory
-	mapper      IMapper[*domain.User, *dto.UserResponse]
-	queueClient *queue.RabbitMQClient
+// UserServiceConfig, UserService'in davranışını değiştiren ayarları içerir.
+type UserServiceConfig struct {
+	RequireEmailVerification bool // true ise e-posta adresi doğrulanmamış kullanıcılar giriş yapamaz
 }
 
-func NewUserService(uowFactory IUnitOfWorkFactory, mapper IMapper[*domain.User, *dto.UserResponse], queueClient *queue.RabbitMQClient) IUserService {
-	return &UserService{
-		uowFactory:  uowFactory,
-		mapper:      mapper,
-		queueClient: queueClient,
-	}
+type UserService struct {
+	uowFactory       IUnitOfWorkFactory
+	mapper           IMapper[*domain.User, *dto.UserResponse]
+	queueClient      *queue.RabbitMQClient
+	tokenService     ITokenService
+	permCache        IPermissionCache
+	twoFactorLimiter ratelimit.IFailureLimiter
+	loginLimiters    LoginLimiters
+	// verificationLimiters ve passwordResetLimiters, doğrulama e-postası yeniden gönderme ve
+	// şifre sıfırlama isteklerini sınırlar.
+	verificationLimiters  EmailRequestLimiters
+	passwordResetLimiters EmailRequestLimiters
+	cfg                   UserServiceConfig
 }
 
-func (s *UserService) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
-	uow := s.uowFactory.New(ctx)
-	defer uow.Rollback()
-
-	userRepo := uow.UserRepository()
-	user, err := userRepo.FindByEmail(ctx, email)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperrors.ErrInvalidCredentials
-		}
-		return nil, err
-	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
-	if err != nil {
-		return nil, apperrors.ErrInvalidCredentials
+// NewUserService, yeni bir UserService oluşturur. twoFactorLimiter, loginLimiters, verificationLimiters
+// veya passwordResetLimiters alanları nil ise ilgili denemeler sınırlandırılmaz (örn. worker süreci); permCache nil ise
+// silinen kullanıcıların yetki önbelleği temizlenmez.
+func NewUserService(uowFactory IUnitOfWorkFactory, mapper IMapper[*domain.User, *dto.UserResponse], queueClient *queue.RabbitMQClient, tokenService ITokenService, permCache IPermissionCache, twoFactorLimiter ratelimit.IFailureLimiter, loginLimiters LoginLimiters, verificationLimiters, passwordResetLimiters EmailRequestLimiters, cfg UserServiceConfig) IUserService {
+	return &UserService{
+		uowFactory:            uowFactory,
+		mapper:                mapper,
+		queueClient:           queueClient,
+		tokenService:          tokenService,
+		permCache:             permCache,
+		twoFactorLimiter:      twoFactorLimiter,
+		loginLimiters:         loginLimiters,
+		verificationLimiters:  verificationLimiters,
+		passwordResetLimiters: passwordResetLimiters,
+		cfg:                   cfg,
 	}
-
-	return user, nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, id int) (*dto.UserResponse, error) {
@@ -109,7 +131,7 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]dto.UserResponse, erro
 }
 
 func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error) {
-	if req.Name == "" || req.Email == "" || req.Password == "" {
+	if req.Name == "" || req.Email == "" || (req.Password == "" && !req.ServiceAccount) {
 		metrics.M.ValidationErrorsTotal.WithLabelValues("user", "missing_fields").Inc()
 		return nil, apperrors.ErrValidation
 	}
@@ -119,17 +141,26 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 		return nil, apperrors.ErrValidation
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		log.Printf("Error while hashing password: %v", err)
-		return nil, apperrors.ErrInternalServer
+	// Servis hesaplarının şifresi yoktur; boş özet hiçbir şifre ile eşleşmez.
+	var hashedPassword string
+	if !req.ServiceAccount {
+		if err := validatePassword(req.Password); err != nil {
+			return nil, err
+		}
+
+		var err error
+		hashedPassword, err = password.Hash(req.Password)
+		if err != nil {
+			log.Printf("Error while hashing password: %v", err)
+			return nil, apperrors.ErrInternalServer
+		}
 	}
 
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
 	// Check if email exists
-	_, err = uow.UserRepository().FindByEmail(ctx, req.Email)
+	_, err := uow.UserRepository().FindByEmail(ctx, req.Email)
 	if err == nil {
 		return nil, apperrors.ErrEmailExists
 	}
@@ -138,12 +169,44 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 	}
 
 	user := s.mapper.ToEntity(req)
-	user.PasswordHash = string(hashedPassword)
+	user.PasswordHash = hashedPassword
+	if user.ServiceAccount {
+		// Servis hesaplarına e-posta gönderilmez; adres sadece tanımlayıcıdır.
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
 
 	createdUser, err := uow.UserRepository().Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	// Yönetici tarafından oluşturulan kullanıcı, yöneticinin aktif organizasyonuna üye olur.
+	if organizationID, ok := tenant.OrganizationID(ctx); ok {
+		if _, err := uow.OrganizationRepository().AddMember(ctx, organizationID, createdUser.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(createdUser.ID),
+		Action:     domain.AuditActionCreated,
+		Changes:    auditChanges(nil, userAuditSnapshot(createdUser)),
+	}); err != nil {
+		return nil, err
+	}
+
+	if createdUser.ServiceAccount {
+		if err := uow.Commit(); err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Service account %d created", createdUser.ID)
+		return s.mapper.ToResponse(createdUser), nil
+	}
+
+	verificationJob, err := createEmailVerification(ctx, uow.EmailVerificationTokenRepository(), createdUser, createdUser.Email)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := uow.Commit(); err != nil {
 		return nil, err
@@ -165,6 +228,7 @@ func (s *UserService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 			log.Printf("✓ Welcome email job published for user %d", createdUser.ID)
 		}
 	}
+	s.publishEmailVerification(ctx, verificationJob)
 
 	return s.mapper.ToResponse(createdUser), nil
 }
@@ -178,41 +242,154 @@ func (s *UserService) UpdateUser(ctx context.Context, id int, req *dto.UpdateUse
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
-	updateData := &domain.User{
-		Name:  req.Name,
-		Email: req.Email,
-	}
-
-	updatedUser, err := uow.UserRepository().Update(ctx, id, updateData)
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.ErrNotFound
 		}
 		return nil, err
 	}
+	// Kullanıcılar bütün organizasyonlarda ortaktır. Başka organizasyonların da üyesi olan bir
+	// kullanıcının hesap bilgilerini sadece kendisi değiştirebilir; aksi halde bir organizasyonun
+	// yöneticisi e-posta adresini değiştirip şifre sıfırlama ile hesabı (diğer organizasyonlardaki
+	// yetkileri dahil) ele geçirebilir.
+	if actor, err := auth.GetUserFromContext(ctx); err != nil || actor.UserID != id {
+		memberships, err := uow.OrganizationRepository().CountMemberships(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if memberships > 1 {
+			return nil, apperrors.ErrUserInOtherOrgs
+		}
+	}
+	before := userAuditSnapshot(user)
+
+	// Yeni e-posta adresi doğrudan yazılmaz; doğrulanana kadar PendingEmail'de bekler
+	// ve kullanıcı eski adresiyle giriş yapmaya devam eder.
+	updateData := &domain.User{Name: req.Name}
+	var verificationJob *EmailVerificationJob
+	newEmail := strings.TrimSpace(req.Email)
+	switch {
+	case newEmail == "":
+	case strings.EqualFold(newEmail, user.Email):
+		// Mevcut adrese geri dönülmesi bekleyen değişikliği iptal eder.
+		if user.PendingEmail != "" {
+			if err := userRepo.UpdateFields(ctx, id, map[string]interface{}{"pending_email": ""}); err != nil {
+				return nil, err
+			}
+			if err := uow.EmailVerificationTokenRepository().InvalidateAllForUser(ctx, id); err != nil {
+				return nil, err
+			}
+			user.PendingEmail = ""
+		}
+	default:
+		_, err := userRepo.FindByEmail(ctx, newEmail)
+		if err == nil {
+			return nil, apperrors.ErrEmailExists
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		updateData.PendingEmail = newEmail
+		verificationJob, err = createEmailVerification(ctx, uow.EmailVerificationTokenRepository(), user, newEmail)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updatedUser := user
+	if updateData.Name != "" || updateData.PendingEmail != "" {
+		updatedUser, err = userRepo.Update(ctx, id, updateData)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.ErrNotFound
+			}
+			return nil, err
+		}
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(id),
+		Action:     domain.AuditActionUpdated,
+		Changes:    auditChanges(before, userAuditSnapshot(updatedUser)),
+	}); err != nil {
+		return nil, err
+	}
 
 	if err := uow.Commit(); err != nil {
 		return nil, err
 	}
 
+	if verificationJob != nil {
+		s.publishEmailVerification(ctx, verificationJob)
+	}
+
 	return s.mapper.ToResponse(updatedUser), nil
 }
 
+// DeleteUser, kullanıcıyı siler. Kullanıcı aktif organizasyon dışında başka organizasyonların da
+// üyesiyse silinmez (ErrUserInOtherOrgs); organizasyondan çıkarılması yeterlidir.
 func (s *UserService) DeleteUser(ctx context.Context, id int) error {
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
-	err := uow.UserRepository().Delete(ctx, id)
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return apperrors.ErrNotFound
 		}
 		return err
 	}
+	if organizationID, ok := tenant.OrganizationID(ctx); ok {
+		orgRepo := uow.OrganizationRepository()
+		memberships, err := orgRepo.CountMemberships(ctx, id)
+		if err != nil {
+			return err
+		}
+		if memberships > 1 {
+			return apperrors.ErrUserInOtherOrgs
+		}
+		if _, err := orgRepo.RemoveMember(ctx, organizationID, id); err != nil {
+			return err
+		}
+	}
+
+	if err := userRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrNotFound
+		}
+		return err
+	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(id),
+		Action:     domain.AuditActionDeleted,
+		Changes:    auditChanges(userAuditSnapshot(user), nil),
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	// Silinen kullanıcının açık oturumları derhal geçersiz kılınır.
+	if err := s.tokenService.RevokeAllForUser(ctx, id); err != nil {
+		log.Printf("ERROR: Could not revoke sessions of deleted user %d: %v", id, err)
+	}
+	if s.permCache != nil {
+		s.permCache.InvalidateUser(ctx, id)
+	}
 
-	return uow.Commit()
+	return nil
 }
 
+// Setup2FA, yeni bir TOTP anahtarı üretir. Anahtar Enable2FA ile bir kod doğrulanana kadar
+// beklemededir; 2FA etkinleşmez. 2FA zaten etkinse yeni anahtar üretilmez (Err2FAAlreadyEnabled):
+// aksi halde çalınmış bir oturum kendi anahtarını kurup Disable2FA'yı geçebilir ve kullanıcının
+// doğrulayıcı uygulaması çalışmaz hale gelir. Anahtarı değiştirmek için önce 2FA kapatılır.
 func (s *UserService) Setup2FA(ctx context.Context, userID int) (*dto.Setup2FAResponse, error) {
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
@@ -225,6 +402,9 @@ func (s *UserService) Setup2FA(ctx context.Context, userID int) (*dto.Setup2FARe
 		}
 		return nil, err
 	}
+	if user.TwoFactorEnabled {
+		return nil, apperrors.Err2FAAlreadyEnabled
+	}
 
 	key, err := totp.Generate(totp.GenerateOpts{
 		Issuer:      "THS-ERP",
@@ -272,39 +452,56 @@ func (s *UserService) Enable2FA(ctx context.Context, userID int, code string) ([
 		return nil, err
 	}
 
+	if user.TwoFactorEnabled {
+		return nil, apperrors.Err2FAAlreadyEnabled
+	}
 	if user.TwoFactorSecret == "" {
 		return nil, apperrors.Err2FASetupNotCompleted
 	}
 
-	valid := totp.Validate(code, user.TwoFactorSecret)
+	if err := s.checkTwoFactorLock(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	valid, err := consumeTOTP(ctx, userRepo, user, code)
+	if err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
 	if !valid {
+		s.registerTwoFactorFailure(ctx, user.ID)
 		return nil, apperrors.ErrInvalid2FACode
 	}
 
 	user.TwoFactorEnabled = true
 
-	recoveryCodes := make([]string, 10)
-	for i := 0; i < 10; i++ {
-		b := make([]byte, 8)
-		if _, err := rand.Read(b); err != nil {
-			return nil, apperrors.ErrInternalServer
-		}
-		recoveryCodes[i] = strings.ToLower(base64.RawURLEncoding.EncodeToString(b))
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
 	}
-	user.TwoFactorRecoveryCodes = recoveryCodes
+	user.TwoFactorRecoveryCodes = hashedCodes
 
 	if _, err := userRepo.Update(ctx, user.ID, user); err != nil {
 		return nil, apperrors.ErrInternalServer
 	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionTwoFactorEnabled,
+	}); err != nil {
+		return nil, err
+	}
 
 	if err := uow.Commit(); err != nil {
 		return nil, err
 	}
 
+	s.resetTwoFactorFailures(ctx, user.ID)
 	return recoveryCodes, nil
 }
 
-func (s *UserService) Disable2FA(ctx context.Context, userID int) error {
+// Disable2FA, 2FA'yı kapatır. Çalınmış bir oturumla 2FA'nın kapatılamaması için
+// geçerli bir TOTP/kurtarma kodu veya kullanıcının şifresi istenir.
+func (s *UserService) Disable2FA(ctx context.Context, userID int, req *dto.Disable2FARequest) error {
 	uow := s.uowFactory.New(ctx)
 	defer uow.Rollback()
 
@@ -317,15 +514,53 @@ func (s *UserService) Disable2FA(ctx context.Context, userID int) error {
 		return err
 	}
 
-	user.TwoFactorEnabled = false
-	user.TwoFactorSecret = ""
-	user.TwoFactorRecoveryCodes = nil
+	if req.Code == "" && req.Password == "" {
+		return apperrors.ErrValidation
+	}
 
-	if _, err := userRepo.Update(ctx, user.ID, user); err != nil {
+	if err := s.checkTwoFactorLock(ctx, user.ID); err != nil {
+		return err
+	}
+
+	switch {
+	case req.Code != "":
+		valid, err := consumeTOTP(ctx, userRepo, user, req.Code)
+		if err != nil {
+			return apperrors.ErrInternalServer
+		}
+		if !valid && matchRecoveryCode(user.TwoFactorRecoveryCodes, req.Code) < 0 {
+			s.registerTwoFactorFailure(ctx, user.ID)
+			return apperrors.ErrInvalid2FACode
+		}
+	default:
+		if !verifyPassword(user, req.Password) {
+			s.registerTwoFactorFailure(ctx, user.ID)
+			return apperrors.ErrInvalidCredentials
+		}
+	}
+
+	// Updates sıfır değerleri yazmadığı için alanlar map ile güncellenir.
+	if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"two_factor_enabled":        false,
+		"two_factor_secret":         "",
+		"two_factor_recovery_codes": nil,
+	}); err != nil {
 		return apperrors.ErrInternalServer
 	}
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionTwoFactorDisabled,
+	}); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
 
-	return uow.Commit()
+	s.resetTwoFactorFailures(ctx, user.ID)
+	return nil
 }
 
 func (s *UserService) Verify2FA(ctx context.Context, userID int, code string) (bool, error) {
@@ -345,22 +580,274 @@ func (s *UserService) Verify2FA(ctx context.Context, userID int, code string) (b
 		return true, nil
 	}
 
-	if totp.Validate(code, user.TwoFactorSecret) {
-		return true, nil
+	if err := s.checkTwoFactorLock(ctx, user.ID); err != nil {
+		return false, err
 	}
 
-	for i, recoveryCode := range user.TwoFactorRecoveryCodes {
-		if code == recoveryCode {
-			user.TwoFactorRecoveryCodes = append(user.TwoFactorRecoveryCodes[:i], user.TwoFactorRecoveryCodes[i+1:]...)
-			if _, err := userRepo.Update(ctx, user.ID, user); err != nil {
-				return false, apperrors.ErrInternalServer
-			}
-			if err := uow.Commit(); err != nil {
-				return false, err
-			}
-			return true, nil
+	valid, err := consumeTOTP(ctx, userRepo, user, code)
+	if err != nil {
+		return false, apperrors.ErrInternalServer
+	}
+
+	if !valid {
+		i := matchRecoveryCode(user.TwoFactorRecoveryCodes, code)
+		if i < 0 {
+			s.registerTwoFactorFailure(ctx, user.ID)
+			return false, apperrors.ErrInvalid2FACode
+		}
+		// Kurtarma kodları tek kullanımlıktır; kod eşzamanlı bir istekte kullanıldıysa reddedilir.
+		consumed, err := userRepo.ConsumeRecoveryCode(ctx, user.ID, user.TwoFactorRecoveryCodes[i])
+		if err != nil {
+			return false, apperrors.ErrInternalServer
+		}
+		if !consumed {
+			s.registerTwoFactorFailure(ctx, user.ID)
+			return false, apperrors.ErrInvalid2FACode
 		}
 	}
 
-	return false, apperrors.ErrInvalid2FACode
+	if err := uow.Commit(); err != nil {
+		return false, err
+	}
+
+	s.resetTwoFactorFailures(ctx, user.ID)
+	return true, nil
+}
+
+// RegenerateRecoveryCodes, mevcut kurtarma kodlarını geçersiz kılar ve yenilerini üretir.
+// Kurtarma kodu ile yeni kurtarma kodu üretilememesi için sadece güncel TOTP kodu kabul edilir.
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if !user.TwoFactorEnabled {
+		return nil, apperrors.Err2FASetupNotCompleted
+	}
+
+	if err := s.checkTwoFactorLock(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	valid, err := consumeTOTP(ctx, userRepo, user, code)
+	if err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
+	if !valid {
+		s.registerTwoFactorFailure(ctx, user.ID)
+		return nil, apperrors.ErrInvalid2FACode
+	}
+
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{"two_factor_recovery_codes": hashedCodes}); err != nil {
+		return nil, apperrors.ErrInternalServer
+	}
+	// Kodların kendisi değil, sadece sayısı kaydedilir.
+	if err := recordAudit(ctx, uow, &domain.AuditEvent{
+		EntityType: domain.AuditEntityUser,
+		EntityID:   strconv.Itoa(user.ID),
+		Action:     domain.AuditActionRecoveryCodesRegenerated,
+		Changes:    auditChanges(map[string]any{"recoveryCodes": len(user.TwoFactorRecoveryCodes)}, map[string]any{"recoveryCodes": len(hashedCodes)}),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.resetTwoFactorFailures(ctx, user.ID)
+	return recoveryCodes, nil
+}
+
+// Get2FAStatus, kullanıcının 2FA durumunu ve kalan kurtarma kodu sayısını döner.
+func (s *UserService) Get2FAStatus(ctx context.Context, userID int) (*dto.TwoFactorStatusResponse, error) {
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &dto.TwoFactorStatusResponse{
+		Enabled:                user.TwoFactorEnabled,
+		RemainingRecoveryCodes: len(user.TwoFactorRecoveryCodes),
+	}, nil
+}
+
+const recoveryCodeCount = 10
+
+// generateRecoveryCodes, kullanıcıya bir kez gösterilecek düz metin kurtarma kodlarını
+// ve veritabanında saklanacak SHA-256 özetlerini üretir.
+func generateRecoveryCodes() ([]string, pq.StringArray, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make(pq.StringArray, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, apperrors.ErrInternalServer
+		}
+		codes[i] = strings.ToLower(base64.RawURLEncoding.EncodeToString(b))
+		hashes[i] = auth.HashToken(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// matchRecoveryCode, verilen kodun özetini saklanan özetlerle sabit zamanlı olarak karşılaştırır
+// ve eşleşen kodun indeksini döner. Zamanlama sızıntısını önlemek için tüm liste her zaman taranır.
+func matchRecoveryCode(hashedCodes []string, code string) int {
+	if code == "" {
+		return -1
+	}
+	candidate := []byte(auth.HashToken(strings.ToLower(strings.TrimSpace(code))))
+	match := -1
+	for i, hashed := range hashedCodes {
+		if subtle.ConstantTimeCompare(candidate, []byte(hashed)) == 1 && match < 0 {
+			match = i
+		}
+	}
+	return match
+}
+
+const (
+	totpPeriod = 30 // saniye
+	totpSkew   = 1  // saat kayması için kabul edilen önceki/sonraki adım sayısı
+)
+
+// matchTOTP, kodu mevcut zaman adımı ve ±totpSkew komşu adımlar için kontrol eder.
+// Sadece lastStep'ten büyük adımlar kabul edilir; böylece kullanılmış bir kod
+// (veya ondan eski bir kod) geçerlilik penceresi içinde tekrar kullanılamaz.
+func matchTOTP(secret, code string, lastStep int64, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if secret == "" || len(code) != int(otp.DigitsSix) {
+		return 0, false
+	}
+
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// consumeTOTP, TOTP kodunu doğrular ve kullanılan zaman adımını kaydeder.
+// Adım veritabanında koşullu güncellendiği için eşzamanlı isteklerde de kod yalnızca bir kez kabul edilir.
+func consumeTOTP(ctx context.Context, userRepo repository.IUserRepository, user *domain.User, code string) (bool, error) {
+	step, ok := matchTOTP(user.TwoFactorSecret, code, user.TwoFactorLastStep, time.Now())
+	if !ok {
+		return false, nil
+	}
+	advanced, err := userRepo.AdvanceTwoFactorStep(ctx, user.ID, step)
+	if err != nil {
+		return false, err
+	}
+	if advanced {
+		user.TwoFactorLastStep = step
+	}
+	return advanced, nil
+}
+
+func twoFactorLimiterKey(userID int) string {
+	return strconv.Itoa(userID)
+}
+
+// checkTwoFactorLock, kullanıcı çok fazla hatalı 2FA denemesi nedeniyle kilitliyse hata döner.
+func (s *UserService) checkTwoFactorLock(ctx context.Context, userID int) error {
+	if s.twoFactorLimiter == nil {
+		return nil
+	}
+	lockedFor, err := s.twoFactorLimiter.Check(ctx, twoFactorLimiterKey(userID))
+	if err != nil {
+		log.Printf("ERROR: Could not check 2FA rate limit for user %d: %v", userID, err)
+		return apperrors.ErrInternalServer
+	}
+	if lockedFor > 0 {
+		return apperrors.ErrTooManyAttempts
+	}
+	return nil
+}
+
+// registerTwoFactorFailure, hatalı bir 2FA denemesini kaydeder.
+func (s *UserService) registerTwoFactorFailure(ctx context.Context, userID int) {
+	if s.twoFactorLimiter == nil {
+		return
+	}
+	lockedFor, err := s.twoFactorLimiter.RegisterFailure(ctx, twoFactorLimiterKey(userID))
+	if err != nil {
+		log.Printf("ERROR: Could not register 2FA failure for user %d: %v", userID, err)
+		return
+	}
+	if lockedFor > 0 {
+		log.Printf("WARNING: 2FA locked for user %d for %s after repeated failures", userID, lockedFor)
+	}
+}
+
+// resetTwoFactorFailures, başarılı bir 2FA doğrulamasından sonra hata sayacını sıfırlar.
+func (s *UserService) resetTwoFactorFailures(ctx context.Context, userID int) {
+	if s.twoFactorLimiter == nil {
+		return
+	}
+	if err := s.twoFactorLimiter.Reset(ctx, twoFactorLimiterKey(userID)); err != nil {
+		log.Printf("ERROR: Could not reset 2FA failures for user %d: %v", userID, err)
+	}
+}
+
+// Login2FA, şifre adımında verilen challenge token'ı ve bir TOTP ya da kurtarma kodunu
+// doğrulayarak girişi tamamlar. Kullanıcı kimliği istemciden değil, imzalı token'dan alınır.
+func (s *UserService) Login2FA(ctx context.Context, challengeToken, code string) (*dto.LoginResponse, error) {
+	claims, err := s.tokenService.VerifyChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := s.Verify2FA(ctx, claims.UserID, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, apperrors.ErrInvalid2FACode
+	}
+
+	if err := s.tokenService.ConsumeChallenge(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	uow := s.uowFactory.New(ctx)
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidChallenge
+		}
+		return nil, err
+	}
+
+	return s.tokenService.IssueTokens(ctx, user)
 }
@@ -0,0 +1,152 @@
+package tenant
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"ths-erp.com/internal/apperrors"
+	"ths-erp.com/internal/domain"
+)
+
+// Column, kiracıya ait tablolarda organizasyonu tutan sütundur.
+const Column = "organization_id"
+
+// Plugin, kiracıya ait modellerin (domain.ITenantEntity) sorgularını aktif organizasyonla
+// sınırlayan GORM eklentisidir:
+//   - select, update ve delete sorgularına "organization_id = <aktif organizasyon>" eklenir,
+//   - oluşturulan kayıtların organization_id alanı aktif organizasyonla doldurulur; başka bir
+//     organizasyona ait bir kayıt oluşturulmaya çalışılırsa apperrors.ErrCrossTenant döner.
+//
+// Filtre modelin tablosuna uygulanır. Raw SQL sorguları ve Joins ile eklenen kiracı tabloları
+// filtrelenmez; bu sorgular organizasyonu kendileri koşul olarak eklemelidir (bkz. RequireOrganization).
+//
+//	db.Use(tenant.Plugin{})
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", assignOrganization); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", restrictToOrganization); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", restrictWriteToOrganization); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenant:delete", restrictWriteToOrganization)
+}
+
+// RequireOrganization, raw SQL ile kiracı tablolarını okuyan repository'ler içindir: aktif
+// organizasyonu döner, yoksa apperrors.ErrNoOrganization döner. WithoutScope ile işaretlenmiş
+// context'lerde de organizasyon yoktur; bu sorguların tüm organizasyonlarda anlamı yoktur.
+func RequireOrganization(db *gorm.DB) (int, error) {
+	if id, ok := OrganizationID(db.Statement.Context); ok {
+		return id, nil
+	}
+	return 0, apperrors.ErrNoOrganization
+}
+
+func restrictToOrganization(db *gorm.DB) {
+	restrict(db, false)
+}
+
+func restrictWriteToOrganization(db *gorm.DB) {
+	restrict(db, true)
+}
+
+func restrict(db *gorm.DB, write bool) {
+	if db.Error != nil || !isTenantOwned(db.Statement.Schema) || IsUnscoped(db.Statement.Context) {
+		return
+	}
+	organizationID, ok := OrganizationID(db.Statement.Context)
+	if !ok {
+		db.AddError(apperrors.ErrNoOrganization)
+		return
+	}
+	// Koşulsuz bir update/delete'e organizasyon koşulu eklenirse GORM'un ErrMissingWhereClause
+	// koruması devre dışı kalır ve organizasyonun tüm kayıtları etkilenir; bu yüzden eklenmez.
+	if write && !hasConditions(db) {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: organizationID},
+	}})
+}
+
+func assignOrganization(db *gorm.DB) {
+	if db.Error != nil || !isTenantOwned(db.Statement.Schema) {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(Column)
+	if field == nil {
+		return
+	}
+	organizationID, ok := OrganizationID(db.Statement.Context)
+	unscoped := IsUnscoped(db.Statement.Context)
+	if !ok && !unscoped {
+		db.AddError(apperrors.ErrNoOrganization)
+		return
+	}
+
+	assign := func(rv reflect.Value) {
+		rv = reflect.Indirect(rv)
+		if rv.Kind() != reflect.Struct {
+			return
+		}
+		value, zero := field.ValueOf(db.Statement.Context, rv)
+		switch {
+		case zero && unscoped:
+			// Sistem süreçleri organizasyonu kayıtta açıkça belirtmelidir.
+			db.AddError(apperrors.ErrNoOrganization)
+		case zero:
+			db.AddError(field.Set(db.Statement.Context, rv, organizationID))
+		case !unscoped && value != organizationID:
+			db.AddError(apperrors.ErrCrossTenant)
+		}
+	}
+
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len() && db.Error == nil; i++ {
+			assign(rv.Index(i))
+		}
+	default:
+		assign(rv)
+	}
+}
+
+// hasConditions, update/delete sorgusunun bir koşulu olup olmadığını söyler: açık bir WHERE
+// veya GORM'un koşul olarak ekleyeceği birincil anahtar değeri (örn. Delete(&report)).
+func hasConditions(db *gorm.DB) bool {
+	if _, ok := db.Statement.Clauses["WHERE"]; ok || db.AllowGlobalUpdate {
+		return true
+	}
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Struct:
+		if pk := db.Statement.Schema.PrioritizedPrimaryField; pk != nil {
+			_, zero := pk.ValueOf(db.Statement.Context, rv)
+			return !zero
+		}
+		return false
+	case reflect.Slice, reflect.Array:
+		return rv.Len() > 0
+	}
+	return false
+}
+
+var tenantEntityType = reflect.TypeOf((*domain.ITenantEntity)(nil)).Elem()
+
+func isTenantOwned(s *schema.Schema) bool {
+	if s == nil {
+		return false
+	}
+	return reflect.PointerTo(s.ModelType).Implements(tenantEntityType)
+}
@@ -0,0 +1,60 @@
+// Package tenant, aynı kurulumda birden fazla organizasyonun (kiracı) barındırılmasını sağlar.
+// Aktif organizasyon context'ten okunur: WithOrganization ile açıkça verilmişse o, yoksa
+// kimliği doğrulanmış kullanıcının access token'ındaki "tid" claim'i (auth.AuthUser.TenantID).
+//
+// Kiracıya ait tabloların (domain.ITenantEntity) sorguları Plugin ile otomatik olarak aktif
+// organizasyonla sınırlanır. Aktif organizasyon yoksa sorgu apperrors.ErrNoOrganization ile
+// başarısız olur (fail closed); tüm organizasyonlarda çalışması gereken sistem süreçleri
+// (migration, worker) context'i WithoutScope ile işaretler.
+package tenant
+
+import (
+	"context"
+
+	"ths-erp.com/internal/auth"
+)
+
+type contextKey struct{}
+
+// scope, context'e eklenen kiracı kapsamıdır.
+type scope struct {
+	organizationID int
+	unscoped       bool
+}
+
+// WithOrganization, context'in aktif organizasyonunu belirler. Oturumdaki kullanıcının
+// organizasyonundan ve WithoutScope'tan önceliklidir (örn. worker'ın bir raporu raporun
+// organizasyonunda işlemesi veya yeni bir organizasyonun rollerinin oluşturulması).
+func WithOrganization(ctx context.Context, organizationID int) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope{organizationID: organizationID})
+}
+
+// WithoutScope, kiracı kısıtlamasını kaldırır; sorgular tüm organizasyonların kayıtlarını görür.
+// Sadece sistem süreçleri için kullanılmalıdır, kullanıcı isteklerinde kullanılmamalıdır.
+func WithoutScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope{unscoped: true})
+}
+
+// IsUnscoped, context'in WithoutScope ile işaretlenip işaretlenmediğini söyler.
+func IsUnscoped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	s, ok := ctx.Value(contextKey{}).(scope)
+	return ok && s.unscoped
+}
+
+// OrganizationID, context'teki aktif organizasyonu döner. Organizasyon yoksa (kullanıcı hiçbir
+// organizasyonun üyesi değilse veya istek kimliksizse) false döner.
+func OrganizationID(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	if s, ok := ctx.Value(contextKey{}).(scope); ok {
+		return s.organizationID, s.organizationID != 0
+	}
+	if user, err := auth.GetUserFromContext(ctx); err == nil && user.TenantID != 0 {
+		return user.TenantID, true
+	}
+	return 0, false
+}
@@ -31,6 +31,8 @@ func NewJobConsumer(channel *amqp.Channel, userService service.IUserService, rep
 // Her kuyruk kendi goroutine'inde çalışır, böylece birbirlerini bloklamazlar.
 func (c *JobConsumer) StartConsumers() {
 	go c.consume("welcome_emails_queue", c.handleWelcomeEmail)
+	go c.consume("password_reset_emails_queue", c.handlePasswordResetEmail)
+	go c.consume("email_verification_queue", c.handleEmailVerification)
 	go c.consume("reports_queue", c.handleGenerateReport)
 
 	logger.L.Info().Msg("All consumers started. Waiting for messages...")
@@ -86,6 +88,63 @@ func (c *JobConsumer) handleWelcomeEmail(d amqp.Delivery) {
 	}
 }
 
+// handlePasswordResetEmail, 'password_reset_emails_queue' kuyruğundan gelen mesajları işler.
+// Mesaj gövdesi sıfırlama token'ını içerdiği için loglanmaz.
+func (c *JobConsumer) handlePasswordResetEmail(d amqp.Delivery) {
+	l := logger.L.With().Str("job_type", "password_reset_email").Logger()
+
+	var job service.PasswordResetEmailJob
+	if err := json.Unmarshal(d.Body, &job); err != nil {
+		l.Error().Err(err).Msg("Failed to unmarshal message. Rejecting.")
+		d.Reject(false)
+		return
+	}
+	l.Info().Int("user_id", job.UserID).Msg("Received a password reset email job")
+
+	// Süresi dolmuş bir bağlantıyı göndermenin anlamı yok.
+	if time.Now().After(job.ExpiresAt) {
+		l.Warn().Int("user_id", job.UserID).Msg("Password reset token already expired. Dropping job.")
+		d.Ack(false)
+		return
+	}
+
+	if err := c.sendPasswordResetEmail(job); err != nil {
+		l.Error().Err(err).Int("user_id", job.UserID).Msg("Failed to process job. Nacking.")
+		d.Nack(false, false)
+	} else {
+		d.Ack(false)
+		l.Info().Int("user_id", job.UserID).Msg("Job processed successfully.")
+	}
+}
+
+// handleEmailVerification, 'email_verification_queue' kuyruğundan gelen mesajları işler.
+// Mesaj gövdesi doğrulama token'ını içerdiği için loglanmaz.
+func (c *JobConsumer) handleEmailVerification(d amqp.Delivery) {
+	l := logger.L.With().Str("job_type", "email_verification").Logger()
+
+	var job service.EmailVerificationJob
+	if err := json.Unmarshal(d.Body, &job); err != nil {
+		l.Error().Err(err).Msg("Failed to unmarshal message. Rejecting.")
+		d.Reject(false)
+		return
+	}
+	l.Info().Int("user_id", job.UserID).Msg("Received an email verification job")
+
+	if time.Now().After(job.ExpiresAt) {
+		l.Warn().Int("user_id", job.UserID).Msg("Verification token already expired. Dropping job.")
+		d.Ack(false)
+		return
+	}
+
+	if err := c.sendVerificationEmail(job); err != nil {
+		l.Error().Err(err).Int("user_id", job.UserID).Msg("Failed to process job. Nacking.")
+		d.Nack(false, false)
+	} else {
+		d.Ack(false)
+		l.Info().Int("user_id", job.UserID).Msg("Job processed successfully.")
+	}
+}
+
 // handleGenerateReport, 'reports_queue' kuyruğundan gelen mesajları işler.
 func (c *JobConsumer) handleGenerateReport(d amqp.Delivery) {
 	l := logger.L.With().Str("job_type", "generate_report").Logger()
@@ -123,3 +182,25 @@ func (c *JobConsumer) sendWelcomeEmail(job service.WelcomeEmailJob) error {
 	logger.L.Info().Str("email", job.Email).Msg("Email successfully sent.")
 	return nil
 }
+
+// sendPasswordResetEmail, şifre sıfırlama e-postası gönderme işini simüle eder.
+func (c *JobConsumer) sendPasswordResetEmail(job service.PasswordResetEmailJob) error {
+	logger.L.Info().Str("email", job.Email).Int("user_id", job.UserID).Time("expires_at", job.ExpiresAt).Msg("Sending password reset email...")
+
+	// Gerçek bir e-posta gönderme servisi çağrısını simüle edelim.
+	time.Sleep(2 * time.Second)
+
+	logger.L.Info().Str("email", job.Email).Msg("Email successfully sent.")
+	return nil
+}
+
+// sendVerificationEmail, e-posta doğrulama bağlantısı gönderme işini simüle eder.
+func (c *JobConsumer) sendVerificationEmail(job service.EmailVerificationJob) error {
+	logger.L.Info().Str("email", job.Email).Int("user_id", job.UserID).Time("expires_at", job.ExpiresAt).Msg("Sending verification email...")
+
+	// Gerçek bir e-posta gönderme servisi çağrısını simüle edelim.
+	time.Sleep(2 * time.Second)
+
+	logger.L.Info().Str("email", job.Email).Msg("Email successfully sent.")
+	return nil
+}
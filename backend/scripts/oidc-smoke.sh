@@ -0,0 +1,149 @@
+#!/usr/bin/env bash
+# OIDC login smoke test against the mock-oidc service in docker-compose.yml.
+#
+# Runs the login -> IdP -> callback round-trip the way a browser would and checks that:
+#   - the callback is rejected without the state cookie set by /login, or with another login's cookie,
+#   - a state can only be used once,
+#   - a code issued for another login (other nonce and PKCE verifier) is rejected,
+#   - a verified email creates a user just-in-time and later logins resolve it by subject,
+#   - an unverified or missing email never links to an existing account,
+#   - a new subject with a verified email is linked to the existing account.
+#
+# Prerequisites:
+#   docker compose up -d       (backend uses .env.development: OIDC_PROVIDERS=mock, OIDC_MOCK_AUTO_CREATE=true)
+#   curl and jq on the host
+#
+# The backend discovers the IdP as http://mock-oidc:8090, so authorization URLs point at that host.
+# The script sends those requests to localhost:8090 (MOCK_OIDC_CONNECT_TO) and keeps the Host header,
+# which keeps the issuer in the ID token equal to the one the backend expects.
+#
+# Usage: scripts/oidc-smoke.sh
+#   API_URL               backend base URL (default http://localhost:8080/api/v1)
+#   OIDC_PROVIDER         provider name (default mock)
+#   MOCK_OIDC_CONNECT_TO  curl --connect-to mapping (default mock-oidc:8090:localhost:8090)
+set -euo pipefail
+
+API_URL=${API_URL:-http://localhost:8080/api/v1}
+OIDC_PROVIDER=${OIDC_PROVIDER:-mock}
+MOCK_OIDC_CONNECT_TO=${MOCK_OIDC_CONNECT_TO:-mock-oidc:8090:localhost:8090}
+
+RUN_ID="$(date +%s)-$RANDOM"
+EMAIL="oidc-smoke-${RUN_ID}@example.com"
+WORK_DIR="$(mktemp -d)"
+trap 'rm -rf "$WORK_DIR"' EXIT
+
+FAILURES=0
+
+# begin_login <jar>: starts a login, stores the state cookie in <jar> and prints the IdP authorization URL.
+begin_login() {
+	local jar=$1 status
+	status=$(curl -s -o /dev/null -c "$jar" -w '%{http_code} %{redirect_url}' "$API_URL/auth/oidc/$OIDC_PROVIDER/login")
+	if [[ ${status%% *} != 302 ]]; then
+		echo "login did not redirect to the IdP (HTTP ${status%% *})" >&2
+		exit 1
+	fi
+	echo "${status#* }"
+}
+
+# authorize <authorization url> <subject> <claims json>: submits the mock IdP login form and prints
+# the callback URL the IdP redirects to.
+authorize() {
+	local url=$1 subject=$2 claims=$3 status
+	status=$(curl -s -o /dev/null --connect-to "$MOCK_OIDC_CONNECT_TO" -w '%{http_code} %{redirect_url}' \
+		--data-urlencode "username=$subject" --data-urlencode "claims=$claims" "$url")
+	if [[ ${status%% *} != 302 ]]; then
+		echo "mock IdP did not redirect back (HTTP ${status%% *})" >&2
+		exit 1
+	fi
+	echo "${status#* }"
+}
+
+# callback <url> [curl args...]: calls the backend callback; the response body goes to $WORK_DIR/body
+# and the HTTP status is printed.
+callback() {
+	local url=$1
+	shift
+	curl -s -o "$WORK_DIR/body" -w '%{http_code}' "$@" "$url"
+}
+
+# query_param <url> <name>
+query_param() {
+	printf '%s' "$1" | sed -n "s/.*[?&]$2=\([^&]*\).*/\1/p"
+}
+
+# state_cookie <jar>
+state_cookie() {
+	awk '$6 == "oidc_state" { print $7 }' "$1"
+}
+
+# user_id: the id of the user in the last callback response.
+user_id() {
+	jq -r '.data.user.id // empty' "$WORK_DIR/body" 2>/dev/null || true
+}
+
+# check <description> <expected> <actual>
+check() {
+	if [[ $2 == "$3" ]]; then
+		echo "PASS  $1"
+	else
+		echo "FAIL  $1 (expected $2, got $3)"
+		FAILURES=$((FAILURES + 1))
+	fi
+}
+
+verified_claims() {
+	printf '{"email":"%s","email_verified":true,"name":"OIDC Smoke"}' "$1"
+}
+
+echo "Running OIDC smoke test against $API_URL (provider $OIDC_PROVIDER, email $EMAIL)"
+
+# 1. State is bound to the browser that started the login.
+auth_url=$(begin_login "$WORK_DIR/jar1")
+cookie=$(state_cookie "$WORK_DIR/jar1")
+callback_url=$(authorize "$auth_url" "smoke-$RUN_ID-a" "$(verified_claims "$EMAIL")")
+check "callback without the state cookie is rejected" 400 "$(callback "$callback_url")"
+
+auth_url_other=$(begin_login "$WORK_DIR/jar-other")
+check "callback with another login's state cookie is rejected" 400 "$(callback "$callback_url" -b "$WORK_DIR/jar-other")"
+
+# 2. Verified email creates the user just-in-time.
+check "callback with the state cookie signs in" 200 "$(callback "$callback_url" -b "$WORK_DIR/jar1")"
+created_id=$(user_id)
+check "user is created just-in-time" true "$([[ -n $created_id ]] && echo true || echo false)"
+
+# 3. State is single use.
+check "replayed callback is rejected" 400 "$(callback "$callback_url" -H "Cookie: oidc_state=$cookie")"
+
+# 4. A code issued for another login does not match this login's nonce and PKCE verifier.
+other_callback=$(authorize "$auth_url_other" "smoke-$RUN_ID-a" "$(verified_claims "$EMAIL")")
+auth_url=$(begin_login "$WORK_DIR/jar2")
+own_callback=$(authorize "$auth_url" "smoke-$RUN_ID-a" "$(verified_claims "$EMAIL")")
+swapped="${API_URL}/auth/oidc/$OIDC_PROVIDER/callback?state=$(query_param "$own_callback" state)&code=$(query_param "$other_callback" code)"
+check "code from another login is rejected" 401 "$(callback "$swapped" -b "$WORK_DIR/jar2")"
+
+# 5. Returning subject resolves to the same user even if the IdP reports another email.
+auth_url=$(begin_login "$WORK_DIR/jar3")
+callback_url=$(authorize "$auth_url" "smoke-$RUN_ID-a" "$(verified_claims "changed-$EMAIL")")
+check "returning subject signs in" 200 "$(callback "$callback_url" -b "$WORK_DIR/jar3")"
+check "returning subject resolves to the same user" "$created_id" "$(user_id)"
+
+# 6. Unverified or missing email never links to the existing account.
+auth_url=$(begin_login "$WORK_DIR/jar4")
+callback_url=$(authorize "$auth_url" "smoke-$RUN_ID-b" "{\"email\":\"$EMAIL\",\"email_verified\":false}")
+check "unverified email is not linked" 403 "$(callback "$callback_url" -b "$WORK_DIR/jar4")"
+
+auth_url=$(begin_login "$WORK_DIR/jar5")
+callback_url=$(authorize "$auth_url" "smoke-$RUN_ID-c" '{}')
+check "missing email is not linked" 403 "$(callback "$callback_url" -b "$WORK_DIR/jar5")"
+
+# 7. New subject with the verified email is linked to the existing account.
+auth_url=$(begin_login "$WORK_DIR/jar6")
+callback_url=$(authorize "$auth_url" "smoke-$RUN_ID-d" "$(verified_claims "$EMAIL")")
+check "verified email links a new subject" 200 "$(callback "$callback_url" -b "$WORK_DIR/jar6")"
+check "linked subject resolves to the existing user" "$created_id" "$(user_id)"
+
+if ((FAILURES > 0)); then
+	echo "$FAILURES check(s) failed"
+	exit 1
+fi
+echo "All checks passed"